@@ -0,0 +1,361 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/joeqian10/neo-gogogo/tx"
+)
+
+// TxOptions are the transaction fields used by the generated transaction-building methods
+type TxOptions struct {
+	From          helper.UInt160 // the account which pays the fees
+	Attributes    []*tx.TransactionAttribute
+	ChangeAddress helper.UInt160 // From if it is zero
+	SysFee        helper.Fixed8
+	NetFee        helper.Fixed8
+}
+
+// goTypes are the Go types of the parameter types, the same as MakeInvocationScript and DecodeStack
+var goTypes = map[sc.ContractParameterType]string{
+	sc.Signature: "[]byte",
+	sc.Boolean:   "bool",
+	sc.Integer:   "*big.Int",
+	sc.Hash160:   "helper.UInt160",
+	sc.Hash256:   "helper.UInt256",
+	sc.ByteArray: "[]byte",
+	sc.PublicKey: "*keys.PublicKey",
+	sc.String:    "string",
+	sc.Array:     "[]interface{}",
+	sc.Map:       "[]abi.MapEntry",
+}
+
+// names used by the generated methods, the parameters of the same names are renamed
+var reservedNames = map[string]bool{
+	"c": true, "opts": true, "script": true, "response": true, "result": true, "err": true, "value": true, "ok": true,
+	"abi": true, "big": true, "fmt": true, "helper": true, "keys": true, "models": true, "rpc": true, "tx": true,
+}
+
+// names of the fields and the package level declarations of the generated code, the functions and the events
+// must not take them
+var reservedMethods = map[string]bool{"Abi": true, "Client": true, "TxBuilder": true}
+var reservedTypes = map[string]bool{"contractAbiJson": true, "contractAbi": true}
+
+type bindParameter struct {
+	Name   string // the name in the abi
+	GoName string
+	GoType string
+}
+
+type bindFunction struct {
+	Name       string
+	GoName     string
+	Parameters []bindParameter
+	ReturnType string // empty for Void
+	Zero       string // the zero value of ReturnType
+}
+
+type bindEvent struct {
+	Name   string
+	GoName string
+	Fields []bindParameter
+}
+
+type bindData struct {
+	Package    string
+	Type       string
+	Abi        string
+	StdImports []string
+	Imports    []string // imported with abi, helper, rpc and tx
+	Functions  []bindFunction
+	Events     []bindEvent
+}
+
+// GenerateBinding generates the Go source of a package which calls the contract of the abi. typeName is the
+// contract type, which has a read-only method for each function calling InvokeScript and a transaction-building
+// method calling TransactionBuilder.MakeInvocationTransaction. Each event gets a struct and a decoder
+// of models.RpcNotification.
+func (a *ContractAbi) GenerateBinding(pkg string, typeName string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) || !token.IsIdentifier(typeName) || reservedTypes[typeName] {
+		return nil, fmt.Errorf("invalid package name %s or type name %s", pkg, typeName)
+	}
+	abiJson, err := json.MarshalIndent(a, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	data := bindData{Package: pkg, Type: typeName, Abi: string(abiJson)}
+	used := map[string]bool{}
+	goType := func(t sc.ContractParameterType) string {
+		s, ok := goTypes[t]
+		if !ok {
+			return "interface{}"
+		}
+		if i := strings.Index(s, "."); i >= 0 {
+			used[strings.TrimLeft(s[:i], "*[]")] = true
+		}
+		return s
+	}
+	methods := map[string]bool{}
+	for name := range reservedMethods {
+		methods[name] = true
+	}
+	// the methods are checked after all of them are named, so that the conflicts are found in any order
+	addMethod := func(name string, source string) error {
+		if methods[name] {
+			return fmt.Errorf("%s conflicts with another method %s", source, name)
+		}
+		methods[name] = true
+		return nil
+	}
+	for _, f := range a.Functions {
+		bf := bindFunction{Name: f.Name, GoName: exportedName(f.Name)}
+		if err = addMethod(bf.GoName, "function "+f.Name); err != nil {
+			return nil, err
+		}
+		if err = addMethod(bf.GoName+"Transaction", "function "+f.Name); err != nil {
+			return nil, err
+		}
+		bf.Parameters = bindParameters(f.Parameters, goType, false)
+		if f.ReturnType != sc.Void {
+			bf.ReturnType = goType(f.ReturnType)
+			bf.Zero = zeroValue(bf.ReturnType)
+		}
+		data.Functions = append(data.Functions, bf)
+	}
+	types := map[string]bool{typeName: true, "New" + typeName: true}
+	for _, e := range a.Events {
+		be := bindEvent{Name: e.Name, GoName: exportedName(e.Name) + "Event"}
+		if types[be.GoName] {
+			return nil, fmt.Errorf("event %s conflicts with another type", e.Name)
+		}
+		types[be.GoName] = true
+		if err = addMethod("Decode"+be.GoName, "event "+e.Name); err != nil {
+			return nil, err
+		}
+		be.Fields = bindParameters(e.Parameters, goType, true)
+		data.Events = append(data.Events, be)
+	}
+	for _, bf := range data.Functions {
+		if types[bf.GoName] || types[bf.GoName+"Transaction"] {
+			return nil, fmt.Errorf("function %s conflicts with a type", bf.Name)
+		}
+	}
+	for _, bf := range data.Functions {
+		if bf.ReturnType != "" && bf.ReturnType != "interface{}" {
+			used["fmt"] = true
+		}
+	}
+	if len(data.Events) > 0 {
+		used["fmt"] = true
+		data.Imports = append(data.Imports, "github.com/joeqian10/neo-gogogo/rpc/models")
+	}
+	if used["fmt"] {
+		data.StdImports = append(data.StdImports, "fmt")
+	}
+	if used["big"] {
+		data.StdImports = append(data.StdImports, "math/big")
+	}
+	if used["keys"] {
+		data.Imports = append(data.Imports, "github.com/joeqian10/neo-gogogo/wallet/keys")
+	}
+
+	buf := bytes.Buffer{}
+	if err = bindTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// zeroValue returns the zero value of a type of goTypes
+func zeroValue(goType string) string {
+	switch goType {
+	case "bool":
+		return "false"
+	case "string":
+		return `""`
+	case "helper.UInt160", "helper.UInt256":
+		return goType + "{}"
+	default:
+		return "nil"
+	}
+}
+
+// bindParameters names the parameters as arguments or as exported fields
+func bindParameters(ps []Parameter, goType func(sc.ContractParameterType) string, exported bool) []bindParameter {
+	result := make([]bindParameter, len(ps))
+	names := map[string]bool{}
+	for i, p := range ps {
+		name := exportedName(p.Name)
+		if !exported {
+			name = unexportedName(p.Name)
+			if reservedNames[name] || token.IsKeyword(name) {
+				name += "Arg"
+			}
+		}
+		if names[name] {
+			name = fmt.Sprintf("%s%d", name, i)
+		}
+		names[name] = true
+		result[i] = bindParameter{Name: p.Name, GoName: name, GoType: goType(p.Type)}
+	}
+	return result
+}
+
+// exportedName converts a name such as "balance_of" or "balanceOf" to "BalanceOf"
+func exportedName(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	s := sb.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "X" + s
+	}
+	return s
+}
+
+func unexportedName(name string) string {
+	s := []rune(exportedName(name))
+	s[0] = unicode.ToLower(s[0])
+	return string(s)
+}
+
+var bindTemplate = template.Must(template.New("binding").Parse(`// Code generated by abigen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .StdImports}}
+	"{{.}}"
+{{- end}}
+
+	"github.com/joeqian10/neo-gogogo/abi"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/tx"
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+
+const contractAbiJson = ` + "`{{.Abi}}`" + `
+
+var contractAbi *abi.ContractAbi
+
+func init() {
+	var err error
+	contractAbi, err = abi.NewContractAbiFromJson([]byte(contractAbiJson))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// {{.Type}} calls the contract, set Abi.Hash to call the contract at another script hash
+type {{.Type}} struct {
+	Abi       *abi.ContractAbi
+	Client    rpc.IRpcClient
+	TxBuilder *tx.TransactionBuilder
+}
+
+func New{{.Type}}(client rpc.IRpcClient) *{{.Type}} {
+	a := *contractAbi
+	return &{{.Type}}{
+		Abi:       &a,
+		Client:    client,
+		TxBuilder: tx.NewTransactionBuilderFromClient(client),
+	}
+}
+
+// invoke runs the script of method by InvokeScript and decodes the result
+func (c *{{.Type}}) invoke(method string, args ...interface{}) (interface{}, error) {
+	script, err := c.Abi.MakeInvocationScript(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	response := c.Client.InvokeScript(helper.BytesToHex(script), helper.ZeroScriptHashString)
+	if response.HasError() {
+		return nil, response.GetError()
+	}
+	return c.Abi.DecodeInvokeResult(method, response.Result)
+}
+
+// makeTransaction makes the invocation transaction of method
+func (c *{{.Type}}) makeTransaction(opts abi.TxOptions, method string, args ...interface{}) (*tx.InvocationTransaction, error) {
+	script, err := c.Abi.MakeInvocationScript(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.TxBuilder.MakeInvocationTransaction(script, opts.From, opts.Attributes, opts.ChangeAddress, opts.SysFee, opts.NetFee)
+}
+{{range $f := .Functions}}
+// {{$f.GoName}} test runs {{$f.Name}}
+func (c *{{$.Type}}) {{$f.GoName}}({{range $i, $p := $f.Parameters}}{{if $i}}, {{end}}{{$p.GoName}} {{$p.GoType}}{{end}}) {{if $f.ReturnType}}({{$f.ReturnType}}, error){{else}}error{{end}} {
+	{{if $f.ReturnType}}result{{else}}_{{end}}, err := c.invoke("{{$f.Name}}"{{range $f.Parameters}}, {{.GoName}}{{end}})
+	if err != nil {
+		return {{if $f.ReturnType}}{{$f.Zero}}, {{end}}err
+	}
+	{{- if $f.ReturnType}}
+	{{- if eq $f.ReturnType "interface{}"}}
+	return result, nil
+	{{- else}}
+	value, ok := result.({{$f.ReturnType}})
+	if !ok {
+		return {{$f.Zero}}, fmt.Errorf("unexpected result %T of {{$f.Name}}", result)
+	}
+	return value, nil
+	{{- end}}
+	{{- else}}
+	return nil
+	{{- end}}
+}
+
+// {{$f.GoName}}Transaction makes the transaction to call {{$f.Name}}
+func (c *{{$.Type}}) {{$f.GoName}}Transaction(opts abi.TxOptions{{range $f.Parameters}}, {{.GoName}} {{.GoType}}{{end}}) (*tx.InvocationTransaction, error) {
+	return c.makeTransaction(opts, "{{$f.Name}}"{{range $f.Parameters}}, {{.GoName}}{{end}})
+}
+{{end}}
+{{- range $e := .Events}}
+// {{$e.GoName}} is the notification {{$e.Name}}
+type {{$e.GoName}} struct {
+{{- range $e.Fields}}
+	{{.GoName}} {{.GoType}}
+{{- end}}
+}
+
+// Decode{{$e.GoName}} decodes the notification {{$e.Name}}
+func (c *{{$.Type}}) Decode{{$e.GoName}}(n models.RpcNotification) (*{{$e.GoName}}, error) {
+	data, err := c.Abi.DecodeNotification(n)
+	if err != nil {
+		return nil, err
+	}
+	if data.Name != "{{$e.Name}}" {
+		return nil, fmt.Errorf("expected event {{$e.Name}}, got %s", data.Name)
+	}
+	e := {{$e.GoName}}{}
+	{{- range $e.Fields}}
+	{{- if eq .GoType "interface{}"}}
+	e.{{.GoName}} = data.Fields["{{.Name}}"]
+	{{- else}}
+	e.{{.GoName}}, _ = data.Fields["{{.Name}}"].({{.GoType}})
+	{{- end}}
+	{{- end}}
+	return &e, nil
+}
+{{end}}`))
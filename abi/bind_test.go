@@ -0,0 +1,134 @@
+package abi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractAbi_GenerateBinding(t *testing.T) {
+	a, _ := NewContractAbiFromJson([]byte(testAbi))
+	code, err := a.GenerateBinding("token", "Token")
+	assert.Nil(t, err)
+
+	f, err := parser.ParseFile(token.NewFileSet(), "token.go", code, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "token", f.Name.Name)
+	funcs := map[string]*ast.FuncDecl{}
+	types := map[string]bool{}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			funcs[d.Name.Name] = d
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				if ts, ok := s.(*ast.TypeSpec); ok {
+					types[ts.Name.Name] = true
+				}
+			}
+		}
+	}
+	for _, name := range []string{"NewToken", "Main", "MainTransaction", "Name", "Decimals", "BalanceOf",
+		"BalanceOfTransaction", "Transfer", "TransferTransaction", "DecodeTransferEvent"} {
+		assert.Contains(t, funcs, name)
+	}
+	assert.True(t, types["Token"])
+	assert.True(t, types["TransferEvent"])
+	// opts, from, to and amount
+	assert.Equal(t, 4, len(funcs["TransferTransaction"].Type.Params.List))
+	assert.Equal(t, "amount", funcs["Transfer"].Type.Params.List[2].Names[0].Name)
+
+	_, err = a.GenerateBinding("token-go", "Token")
+	assert.NotNil(t, err)
+}
+
+// buildBinding compiles the generated code in a package of the module, so that the imports are resolved
+func buildBinding(t *testing.T, pkg string, code []byte) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not found")
+	}
+	if err = os.MkdirAll("testdata", 0755); err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("testdata", pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove("testdata") // only if it is empty
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, pkg+".go"), code, 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(goTool, "vet", "./"+dir).CombinedOutput()
+	assert.Nil(t, err, string(out))
+}
+
+func TestContractAbi_GenerateBinding_Build(t *testing.T) {
+	a, _ := NewContractAbiFromJson([]byte(testAbi))
+	code, err := a.GenerateBinding("token", "Token")
+	assert.Nil(t, err)
+	buildBinding(t, "token", code)
+
+	a, err = NewContractAbiFromJson([]byte(`{
+    "hash": "0x5b7074e873973a6ed3708862f219a6fbf4d1c411",
+    "entrypoint": "get",
+    "functions": [
+        {"name": "get", "parameters": [{"name": "v", "type": "Integer"}], "returntype": "String"},
+        {"name": "put", "parameters": [{"name": "value", "type": "Map"}, {"name": "ok", "type": "Boolean"}, {"name": "fmt", "type": "Any"}], "returntype": "Map"},
+        {"name": "owner", "parameters": [], "returntype": "Hash160"},
+        {"name": "key", "parameters": [], "returntype": "PublicKey"},
+        {"name": "run", "parameters": [], "returntype": "Void"}
+    ],
+    "events": []
+}`))
+	assert.Nil(t, err)
+	code, err = a.GenerateBinding("store", "Store")
+	assert.Nil(t, err)
+	buildBinding(t, "store", code)
+}
+
+func TestContractAbi_GenerateBinding_Conflicts(t *testing.T) {
+	for _, functions := range []string{
+		`{"name": "get", "parameters": [], "returntype": "Void"}, {"name": "getTransaction", "parameters": [], "returntype": "Void"}`,
+		`{"name": "getTransaction", "parameters": [], "returntype": "Void"}, {"name": "get", "parameters": [], "returntype": "Void"}`,
+		`{"name": "abi", "parameters": [], "returntype": "Void"}`,
+		`{"name": "client", "parameters": [], "returntype": "Void"}`,
+		`{"name": "txBuilder", "parameters": [], "returntype": "Void"}`,
+		`{"name": "decodeTransferEvent", "parameters": [], "returntype": "Void"}`,
+	} {
+		a, err := NewContractAbiFromJson([]byte(`{
+    "hash": "0x5b7074e873973a6ed3708862f219a6fbf4d1c411",
+    "entrypoint": "main",
+    "functions": [{"name": "main", "parameters": [], "returntype": "Void"}, ` + functions + `],
+    "events": [{"name": "transfer", "parameters": [], "returntype": "Void"}]
+}`))
+		assert.Nil(t, err)
+		_, err = a.GenerateBinding("token", "Token")
+		assert.NotNil(t, err, functions)
+	}
+}
+
+func TestBindParameters(t *testing.T) {
+	ps := bindParameters([]Parameter{{Name: "type"}, {Name: "from_address"}, {Name: "err"}, {Name: "Type"}}, func(sc.ContractParameterType) string {
+		return "interface{}"
+	}, false)
+	assert.Equal(t, "typeArg", ps[0].GoName)
+	assert.Equal(t, "fromAddress", ps[1].GoName)
+	assert.Equal(t, "errArg", ps[2].GoName)
+	assert.Equal(t, "typeArg3", ps[3].GoName)
+
+	fields := bindParameters([]Parameter{{Name: "from"}, {Name: "1st"}}, func(sc.ContractParameterType) string {
+		return "interface{}"
+	}, true)
+	assert.Equal(t, "From", fields[0].GoName)
+	assert.Equal(t, "X1st", fields[1].GoName)
+}
@@ -0,0 +1,219 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+)
+
+// ContractAbi is the .abi.json file generated by the neo 2.x compiler
+type ContractAbi struct {
+	Hash       helper.UInt160 `json:"hash"`
+	EntryPoint string         `json:"entrypoint"`
+	Functions  []Function     `json:"functions"`
+	Events     []Event        `json:"events"`
+}
+
+type Parameter struct {
+	Name string                   `json:"name"`
+	Type sc.ContractParameterType `json:"type"`
+}
+
+type Function struct {
+	Name       string                   `json:"name"`
+	Parameters []Parameter              `json:"parameters"`
+	ReturnType sc.ContractParameterType `json:"returntype"`
+}
+
+type Event struct {
+	Name       string                   `json:"name"`
+	Parameters []Parameter              `json:"parameters"`
+	ReturnType sc.ContractParameterType `json:"returntype"`
+}
+
+// EventData is a notification decoded by its Event, the fields are named by the event parameters
+type EventData struct {
+	Contract helper.UInt160
+	Name     string
+	Fields   map[string]interface{}
+}
+
+// LoadContractAbi reads the abi file at path
+func LoadContractAbi(path string) (*ContractAbi, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewContractAbiFromJson(data)
+}
+
+func NewContractAbiFromJson(data []byte) (*ContractAbi, error) {
+	a := ContractAbi{}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+	if _, err := a.GetFunction(a.EntryPoint); err != nil {
+		return nil, fmt.Errorf("entry point %s is not a function", a.EntryPoint)
+	}
+	return &a, nil
+}
+
+func (a *ContractAbi) GetFunction(name string) (*Function, error) {
+	for i := range a.Functions {
+		if a.Functions[i].Name == name {
+			return &a.Functions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("function %s not found", name)
+}
+
+func (a *ContractAbi) GetEvent(name string) (*Event, error) {
+	for i := range a.Events {
+		if a.Events[i].Name == name {
+			return &a.Events[i], nil
+		}
+	}
+	return nil, fmt.Errorf("event %s not found", name)
+}
+
+// MakeInvocationScript makes the script to call method with args, which are converted to the parameter types:
+//
+//	Boolean            bool
+//	Integer            int, int64, uint64 and other integers, *big.Int, big.Int
+//	Hash160            helper.UInt160, an address or a hex string
+//	Hash256            helper.UInt256, a hex string
+//	ByteArray          []byte
+//	Signature          []byte
+//	PublicKey          *keys.PublicKey, []byte, a hex string
+//	String             string
+//	Array              a slice of the types above, or []sc.ContractParameter
+//	Map                a map of the types above, or []sc.ContractParameterPair
+//	Any                any type above
+//
+// an sc.ContractParameter of the parameter type is accepted as well. The entry point is called with args,
+// and the other functions are called through the entry point with their names and the args in an array.
+func (a *ContractAbi) MakeInvocationScript(method string, args ...interface{}) ([]byte, error) {
+	f, err := a.GetFunction(method)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != len(f.Parameters) {
+		return nil, fmt.Errorf("function %s expects %d arguments, got %d", method, len(f.Parameters), len(args))
+	}
+	cps := make([]sc.ContractParameter, len(args))
+	for i, arg := range args {
+		cps[i], err = NewContractParameter(f.Parameters[i].Type, arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s of function %s: %v", f.Parameters[i].Name, method, err)
+		}
+	}
+	sb := sc.NewScriptBuilder()
+	if method == a.EntryPoint {
+		err = sb.MakeInvocationScript(a.Hash.Bytes(), "", cps)
+	} else {
+		err = sb.MakeInvocationScript(a.Hash.Bytes(), method, cps)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sb.ToArray(), nil
+}
+
+// DecodeInvokeResult decodes the first item of the stack to the return type of method,
+// see DecodeStack for the Go types
+func (a *ContractAbi) DecodeInvokeResult(method string, result models.InvokeResult) (interface{}, error) {
+	f, err := a.GetFunction(method)
+	if err != nil {
+		return nil, err
+	}
+	if f.ReturnType == sc.Void {
+		if strings.Contains(result.State, "FAULT") {
+			return nil, fmt.Errorf("engine faulted")
+		}
+		return nil, nil
+	}
+	item, err := result.Item(0)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeStack(sc.ContractParameter(item), f.ReturnType)
+}
+
+// DecodeNotification decodes a notification of the contract, its state is an array of the event name
+// followed by the event parameters
+func (a *ContractAbi) DecodeNotification(n models.RpcNotification) (*EventData, error) {
+	contract, err := helper.UInt160FromString(n.Contract)
+	if err != nil {
+		return nil, err
+	}
+	if contract != a.Hash {
+		return nil, fmt.Errorf("notification of contract %s, expected %s", contract.String(), a.Hash.String())
+	}
+	items, ok := n.State.Value.([]sc.ContractParameter)
+	if n.State.Type != sc.Array || !ok || len(items) == 0 {
+		return nil, fmt.Errorf("notification state is not an event")
+	}
+	name, err := DecodeStack(items[0], sc.String)
+	if err != nil {
+		return nil, err
+	}
+	e, err := a.GetEvent(name.(string))
+	if err != nil {
+		return nil, err
+	}
+	if len(items)-1 != len(e.Parameters) {
+		return nil, fmt.Errorf("event %s expects %d fields, got %d", e.Name, len(e.Parameters), len(items)-1)
+	}
+	data := EventData{Contract: contract, Name: e.Name, Fields: make(map[string]interface{}, len(e.Parameters))}
+	for i, p := range e.Parameters {
+		v, err := DecodeStack(items[i+1], p.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of event %s: %v", p.Name, e.Name, err)
+		}
+		data.Fields[p.Name] = v
+	}
+	return &data, nil
+}
+
+// NewContractParameter converts v to a parameter of type t by sc.NewContractParameterWithType,
+// a public key is checked to be on the curve. A []MapEntry of DecodeStack is accepted for a Map.
+func NewContractParameter(t sc.ContractParameterType, v interface{}) (sc.ContractParameter, error) {
+	if entries, ok := v.([]MapEntry); ok && (t == sc.Map || t == sc.Any) {
+		return mapEntriesParameter(entries)
+	}
+	cp, err := sc.NewContractParameterWithType(t, v)
+	if err != nil {
+		return cp, err
+	}
+	if cp.Type == sc.PublicKey {
+		if b, ok := cp.Value.([]byte); ok {
+			if _, err = keys.NewPublicKey(b); err != nil {
+				return cp, err
+			}
+		}
+	}
+	return cp, nil
+}
+
+// mapEntriesParameter converts the entries to a Map parameter, the types of the keys and the values are inferred
+func mapEntriesParameter(entries []MapEntry) (sc.ContractParameter, error) {
+	pairs := make([]sc.ContractParameterPair, len(entries))
+	for i, e := range entries {
+		key, err := NewContractParameter(sc.Any, e.Key)
+		if err != nil {
+			return sc.ContractParameter{}, fmt.Errorf("map key: %v", err)
+		}
+		value, err := NewContractParameter(sc.Any, e.Value)
+		if err != nil {
+			return sc.ContractParameter{}, fmt.Errorf("map value: %v", err)
+		}
+		pairs[i] = sc.ContractParameterPair{Key: key, Value: value}
+	}
+	return sc.NewContractParameterWithType(sc.Map, pairs)
+}
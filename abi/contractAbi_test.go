@@ -0,0 +1,225 @@
+package abi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAbi = `{
+    "hash": "0x5b7074e873973a6ed3708862f219a6fbf4d1c411",
+    "entrypoint": "Main",
+    "functions": [
+        {
+            "name": "Main",
+            "parameters": [
+                {"name": "operation", "type": "String"},
+                {"name": "args", "type": "Array"}
+            ],
+            "returntype": "ByteArray"
+        },
+        {"name": "name", "parameters": [], "returntype": "String"},
+        {"name": "decimals", "parameters": [], "returntype": "Integer"},
+        {
+            "name": "balanceOf",
+            "parameters": [{"name": "account", "type": "Hash160"}],
+            "returntype": "Integer"
+        },
+        {
+            "name": "transfer",
+            "parameters": [
+                {"name": "from", "type": "Hash160"},
+                {"name": "to", "type": "Hash160"},
+                {"name": "amount", "type": "Integer"}
+            ],
+            "returntype": "Boolean"
+        }
+    ],
+    "events": [
+        {
+            "name": "transfer",
+            "parameters": [
+                {"name": "from", "type": "Hash160"},
+                {"name": "to", "type": "Hash160"},
+                {"name": "amount", "type": "Integer"}
+            ],
+            "returntype": "Void"
+        }
+    ]
+}`
+
+func TestLoadContractAbi(t *testing.T) {
+	f, err := ioutil.TempFile("", "*.abi.json")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	_, _ = f.WriteString(testAbi)
+	_ = f.Close()
+
+	a, err := LoadContractAbi(f.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, "5b7074e873973a6ed3708862f219a6fbf4d1c411", a.Hash.String())
+	assert.Equal(t, 5, len(a.Functions))
+	assert.Equal(t, sc.Array, a.Functions[0].Parameters[1].Type)
+	transfer, err := a.GetEvent("transfer")
+	assert.Nil(t, err)
+	assert.Equal(t, sc.Void, transfer.ReturnType)
+
+	_, err = NewContractAbiFromJson([]byte(`{"entrypoint": "Main", "functions": [{"name": "Main", "returntype": "Bytes"}]}`))
+	assert.NotNil(t, err)
+	_, err = NewContractAbiFromJson([]byte(`{"entrypoint": "Main", "functions": []}`))
+	assert.NotNil(t, err)
+}
+
+func TestContractAbi_MakeInvocationScript(t *testing.T) {
+	a, _ := NewContractAbiFromJson([]byte(testAbi))
+	from, _ := helper.AddressToScriptHash("AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+	to, _ := helper.AddressToScriptHash("AdQk428wVzpkHTxc4MP5UMdsgNdrm36dyV")
+
+	script, err := a.MakeInvocationScript("transfer", "AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY", to, uint64(100000000))
+	assert.Nil(t, err)
+	sb := sc.NewScriptBuilder()
+	sb.MakeInvocationScript(a.Hash.Bytes(), "transfer", []sc.ContractParameter{
+		{Type: sc.Hash160, Value: from.Bytes()},
+		{Type: sc.Hash160, Value: to.Bytes()},
+		{Type: sc.Integer, Value: *big.NewInt(100000000)},
+	})
+	assert.Equal(t, sb.ToArray(), script)
+
+	// the entry point is called with the args
+	script, err = a.MakeInvocationScript("Main", "balanceOf", []interface{}{from})
+	assert.Nil(t, err)
+	sb = sc.NewScriptBuilder()
+	sb.MakeInvocationScript(a.Hash.Bytes(), "", []sc.ContractParameter{
+		{Type: sc.String, Value: "balanceOf"},
+		{Type: sc.Array, Value: []sc.ContractParameter{{Type: sc.Hash160, Value: from.Bytes()}}},
+	})
+	assert.Equal(t, sb.ToArray(), script)
+
+	_, err = a.MakeInvocationScript("transfer", from, to)
+	assert.NotNil(t, err)
+	_, err = a.MakeInvocationScript("transfer", from, to, "1")
+	assert.NotNil(t, err)
+	_, err = a.MakeInvocationScript("mint")
+	assert.NotNil(t, err)
+}
+
+func TestContractAbi_DecodeInvokeResult(t *testing.T) {
+	a, _ := NewContractAbiFromJson([]byte(testAbi))
+	result := models.InvokeResult{}
+	err := json.Unmarshal([]byte(`{
+        "script": "00c1046e616d656711c4d1f4fba619f2628870d36e3a9773e874705b",
+        "state": "HALT",
+        "gas_consumed": "0.142",
+        "stack": [{"type": "ByteArray", "value": "4e455035205553"}]
+    }`), &result)
+	assert.Nil(t, err)
+	name, err := a.DecodeInvokeResult("name", result)
+	assert.Nil(t, err)
+	assert.Equal(t, "NEP5 US", name)
+
+	result.Stack = []sc.ContractParameter{{Type: sc.Integer, Value: *big.NewInt(8)}}
+	decimals, err := a.DecodeInvokeResult("decimals", result)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(8), decimals)
+
+	result.Stack = []sc.ContractParameter{{Type: sc.ByteArray, Value: []byte{0x00, 0xe1, 0xf5, 0x05}}}
+	balance, err := a.DecodeInvokeResult("balanceOf", result)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(100000000), balance)
+
+	result.Stack = []sc.ContractParameter{{Type: sc.Integer, Value: *big.NewInt(1)}}
+	ok, err := a.DecodeInvokeResult("transfer", result)
+	assert.Nil(t, err)
+	assert.Equal(t, true, ok)
+
+	// the state of older neo-cli 2.x nodes
+	result.State = "HALT, BREAK"
+	ok, err = a.DecodeInvokeResult("transfer", result)
+	assert.Nil(t, err)
+	assert.Equal(t, true, ok)
+
+	result.State = "FAULT"
+	_, err = a.DecodeInvokeResult("transfer", result)
+	assert.NotNil(t, err)
+}
+
+func TestDecodeStack_Array(t *testing.T) {
+	s := sc.ContractParameter{}
+	err := json.Unmarshal([]byte(`{"type": "Array", "value": [
+        {"type": "ByteArray", "value": "01"},
+        {"type": "Integer", "value": "-2"},
+        {"type": "Boolean", "value": true},
+        {"type": "Array", "value": []}
+    ]}`), &s)
+	assert.Nil(t, err)
+	v, err := DecodeStack(s, sc.Array)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{[]byte{1}, big.NewInt(-2), true, []interface{}{}}, v)
+
+	_, err = DecodeStack(sc.ContractParameter{Type: sc.ByteArray, Value: []byte{1}}, sc.Array)
+	assert.NotNil(t, err)
+}
+
+func TestDecodeStack_Map(t *testing.T) {
+	s := sc.ContractParameter{}
+	err := json.Unmarshal([]byte(`{"type": "Map", "value": [
+        {"key": {"type": "ByteArray", "value": "01"}, "value": {"type": "Integer", "value": "5"}},
+        {"key": {"type": "Integer", "value": "2"}, "value": {"type": "Map", "value": []}}
+    ]}`), &s)
+	assert.Nil(t, err)
+	v, err := DecodeStack(s, sc.Map)
+	assert.Nil(t, err)
+	assert.Equal(t, []MapEntry{
+		{Key: []byte{1}, Value: big.NewInt(5)},
+		{Key: big.NewInt(2), Value: []MapEntry{}},
+	}, v)
+
+	// the decoded map can be passed back as an argument
+	cp, err := NewContractParameter(sc.Map, v)
+	assert.Nil(t, err)
+	assert.Equal(t, sc.Map, cp.Type)
+	assert.Equal(t, 2, len(cp.Value.([]sc.ContractParameterPair)))
+
+	// a map in an array
+	v, err = DecodeStack(sc.ContractParameter{Type: sc.Array, Value: []sc.ContractParameter{s}}, sc.Array)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(v.([]interface{})[0].([]MapEntry)))
+
+	_, err = DecodeStack(sc.ContractParameter{Type: sc.Array, Value: []sc.ContractParameter{}}, sc.Map)
+	assert.NotNil(t, err)
+}
+
+func TestContractAbi_DecodeNotification(t *testing.T) {
+	a, _ := NewContractAbiFromJson([]byte(testAbi))
+	n := models.RpcNotification{}
+	err := json.Unmarshal([]byte(`{
+        "contract": "0x5b7074e873973a6ed3708862f219a6fbf4d1c411",
+        "state": {
+            "type": "Array",
+            "value": [
+                {"type": "ByteArray", "value": "7472616e73666572"},
+                {"type": "ByteArray", "value": "c7e7ac6e4b3a3a06d4a5efd9c1e5bb6a16e8a0b1"},
+                {"type": "ByteArray", "value": "e8cd20a8ebb3f26cd10b1bb7ce58ec0a1a1ac1d7"},
+                {"type": "ByteArray", "value": "00e1f505"}
+            ]
+        }
+    }`), &n)
+	assert.Nil(t, err)
+	e, err := a.DecodeNotification(n)
+	assert.Nil(t, err)
+	assert.Equal(t, "transfer", e.Name)
+	assert.Equal(t, a.Hash, e.Contract)
+	assert.Equal(t, "b1a0e8166abbe5c1d9efa5d4063a3a4b6eace7c7", e.Fields["from"].(helper.UInt160).String())
+	assert.Equal(t, big.NewInt(100000000), e.Fields["amount"])
+
+	n.Contract = "0x14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26"
+	_, err = a.DecodeNotification(n)
+	assert.NotNil(t, err)
+}
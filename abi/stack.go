@@ -0,0 +1,144 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+)
+
+// MapEntry is an entry of a Map decoded by DecodeStack, a slice keeps the order of the entries and the
+// keys which are not comparable in Go such as []byte
+type MapEntry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// DecodeStack converts an item of the stack of an InvokeResult to the Go type of t:
+//
+//	Boolean            bool
+//	Integer            *big.Int
+//	Hash160            helper.UInt160
+//	Hash256            helper.UInt256
+//	ByteArray          []byte
+//	Signature          []byte
+//	PublicKey          *keys.PublicKey
+//	String             string
+//	Array              []interface{}, the items are decoded as Any
+//	Map                []MapEntry, the keys and the values are decoded as Any
+//	Any                the type of the stack item, []byte for ByteArray
+//	Void               nil
+//
+// the items are converted the same as the NeoVM, such as an Integer to a ByteArray.
+func DecodeStack(s sc.ContractParameter, t sc.ContractParameterType) (interface{}, error) {
+	switch t {
+	case sc.Void:
+		return nil, nil
+	case sc.Any:
+		switch s.Type {
+		case sc.Boolean:
+			return stackBoolean(s)
+		case sc.Integer:
+			return stackBigInt(s)
+		case sc.String:
+			return DecodeStack(s, sc.String)
+		case sc.Array:
+			return DecodeStack(s, sc.Array)
+		case sc.Map:
+			return DecodeStack(s, sc.Map)
+		case sc.InteropInterface:
+			return nil, nil
+		default:
+			return stackBytes(s)
+		}
+	case sc.Boolean:
+		return stackBoolean(s)
+	case sc.Integer:
+		return stackBigInt(s)
+	case sc.Hash160:
+		b, err := stackBytes(s)
+		if err != nil {
+			return nil, err
+		}
+		return helper.UInt160FromBytes(b)
+	case sc.Hash256:
+		b, err := stackBytes(s)
+		if err != nil {
+			return nil, err
+		}
+		return helper.UInt256FromBytes(b)
+	case sc.ByteArray, sc.Signature:
+		return stackBytes(s)
+	case sc.PublicKey:
+		b, err := stackBytes(s)
+		if err != nil {
+			return nil, err
+		}
+		return keys.NewPublicKey(b)
+	case sc.String:
+		b, err := stackBytes(s)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case sc.Array:
+		items, err := stackItems(s)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]interface{}, len(items))
+		for i, item := range items {
+			if result[i], err = DecodeStack(item, sc.Any); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case sc.Map:
+		pairs, ok := s.Value.([]sc.ContractParameterPair)
+		if s.Type != sc.Map || !ok {
+			return nil, fmt.Errorf("%s can not be converted to a map", s.Type.String())
+		}
+		result := make([]MapEntry, len(pairs))
+		for i, pair := range pairs {
+			key, err := DecodeStack(pair.Key, sc.Any)
+			if err != nil {
+				return nil, err
+			}
+			value, err := DecodeStack(pair.Value, sc.Any)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = MapEntry{Key: key, Value: value}
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("parameter type %s is not supported", t.String())
+	}
+}
+
+func stackBytes(s sc.ContractParameter) ([]byte, error) {
+	return models.StackItem(s).AsBytes()
+}
+
+func stackBigInt(s sc.ContractParameter) (*big.Int, error) {
+	return models.StackItem(s).AsBigInt()
+}
+
+func stackBoolean(s sc.ContractParameter) (bool, error) {
+	return models.StackItem(s).AsBool()
+}
+
+func stackItems(s sc.ContractParameter) ([]sc.ContractParameter, error) {
+	items, err := models.StackItem(s).AsArray()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]sc.ContractParameter, len(items))
+	for i := range items {
+		result[i] = sc.ContractParameter(items[i])
+	}
+	return result, nil
+}
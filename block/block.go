@@ -1,18 +1,155 @@
 package block
 
 import (
-	//"github.com/joeqian10/neo-gogogo/rpc/models"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/joeqian10/neo-gogogo/crypto"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
 	"github.com/joeqian10/neo-gogogo/tx"
+	"strings"
 )
 
+const MaxTransactionsPerBlock = 65535
+
 type Block struct {
 	BlockHeader
-	Tx []*tx.Transaction
+	Tx []tx.ITransaction
+}
+
+// NewBlockFromRPC converts a block returned by getblock with verbose=1, the transactions
+// are converted by tx.NewTransactionFromRPC. An error is returned if the hash of the
+// result differs from rpcBlock.Hash.
+func NewBlockFromRPC(rpcBlock *models.RpcBlock) (*Block, error) {
+	header, err := NewBlockHeaderFromRPC(&rpcBlock.RpcBlockHeader)
+	if err != nil {
+		return nil, err
+	}
+	if header.HashString() != strings.TrimPrefix(rpcBlock.Hash, "0x") {
+		return nil, fmt.Errorf("hash mismatch: expected %s got %s", rpcBlock.Hash, header.HashString())
+	}
+	block := &Block{
+		BlockHeader: *header,
+		Tx:          make([]tx.ITransaction, len(rpcBlock.Tx)),
+	}
+	for i, rpcTx := range rpcBlock.Tx {
+		block.Tx[i], err = tx.NewTransactionFromRPC(rpcTx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return block, nil
+}
+
+// NewBlockFromBytes deserializes a raw block with its transactions
+func NewBlockFromBytes(b []byte) (*Block, error) {
+	r := bytes.NewReader(b)
+	br := io.NewBinaryReaderFromIO(r)
+	block := &Block{}
+	block.Deserialize(br)
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("format error: %d bytes left after the block", r.Len())
+	}
+	return block, nil
+}
+
+// NewBlockFromHexString parses the hex string returned by getblock with verbose=0
+func NewBlockFromHexString(rawBlock string) (*Block, error) {
+	b, err := hex.DecodeString(rawBlock)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockFromBytes(b)
+}
+
+// ComputeMerkleRoot returns the merkle root of the transactions of the block
+func (b *Block) ComputeMerkleRoot() (helper.UInt256, error) {
+	hashes := make([][]byte, len(b.Tx))
+	for i, t := range b.Tx {
+		if t == nil {
+			return helper.UInt256{}, fmt.Errorf("nil transaction at %d", i)
+		}
+		hashes[i] = crypto.Hash256(t.UnsignedRawTransaction())
+	}
+	root, err := crypto.ComputeMerkleRoot(hashes)
+	if err != nil {
+		return helper.UInt256{}, err
+	}
+	return helper.UInt256FromBytes(root)
 }
 
-//func NewBlockFromRPC(rpcBlock *models.RpcBlock) (*Block, error) {
-//	var block = new &Block{
-//		BlockHeader: NewBlockHeaderFromRPC(&rpcBlock.RpcBlockHeader),
-//		Tx:          nil,
-//	}
-//}
+// RebuildMerkleRoot sets MerkleRoot after the transactions are changed
+func (b *Block) RebuildMerkleRoot() error {
+	root, err := b.ComputeMerkleRoot()
+	if err != nil {
+		return err
+	}
+	b.MerkleRoot = root
+	return nil
+}
+
+// Verify checks the header against prev, see BlockHeader.Verify, and the merkle root of the transactions
+func (b *Block) Verify(prev *BlockHeader) error {
+	if err := b.BlockHeader.Verify(prev); err != nil {
+		return err
+	}
+	root, err := b.ComputeMerkleRoot()
+	if err != nil {
+		return err
+	}
+	if root != b.MerkleRoot {
+		return fmt.Errorf("merkle root mismatch: expected %s got %s", b.MerkleRoot.String(), root.String())
+	}
+	return nil
+}
+
+// Deserialize implements Serializable interface.
+func (b *Block) Deserialize(br *io.BinaryReader) {
+	b.BlockHeader.DeserializeUnsigned(br)
+	b.BlockHeader.deserializeWitness(br)
+	n := br.ReadVarUint()
+	if br.Err != nil {
+		return
+	}
+	if n == 0 || n > MaxTransactionsPerBlock {
+		br.Err = fmt.Errorf("format error: invalid transaction count %d", n)
+		return
+	}
+	b.Tx = make([]tx.ITransaction, 0, n)
+	for i := uint64(0); i < n; i++ {
+		t := tx.DeserializeTransaction(br)
+		if br.Err != nil {
+			return
+		}
+		b.Tx = append(b.Tx, t)
+	}
+}
+
+// Serialize implements Serializable interface.
+func (b *Block) Serialize(bw *io.BinaryWriter) {
+	b.BlockHeader.SerializeUnsigned(bw)
+	b.BlockHeader.serializeWitness(bw)
+	bw.WriteVarUint(uint64(len(b.Tx)))
+	for i, t := range b.Tx {
+		if t == nil {
+			bw.Err = fmt.Errorf("nil transaction at %d", i)
+			return
+		}
+		t.Serialize(bw)
+	}
+}
+
+// RawBlock returns the serialized block, the same as getblock with verbose=0
+func (b *Block) RawBlock() []byte {
+	buf := io.NewBufBinaryWriter()
+	b.Serialize(buf.BinaryWriter)
+	if buf.Err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/helper/io"
 	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
 	"github.com/joeqian10/neo-gogogo/tx"
 )
 
@@ -66,6 +67,15 @@ func NewBlockHeaderFromRPC(header *models.RpcBlockHeader) (*BlockHeader, error)
 
 func (bh *BlockHeader) Deserialize(br *io.BinaryReader) {
 	bh.DeserializeUnsigned(br)
+	bh.deserializeWitness(br)
+	var b byte
+	br.ReadLE(&b)
+	if b != byte(0) {
+		br.Err = fmt.Errorf("format error: check byte must equal 0 got %d", b)
+	}
+}
+
+func (bh *BlockHeader) deserializeWitness(br *io.BinaryReader) {
 	var b byte
 	br.ReadLE(&b)
 	if b != byte(1) {
@@ -75,10 +85,6 @@ func (bh *BlockHeader) Deserialize(br *io.BinaryReader) {
 		bh.Witness = &tx.Witness{}
 	}
 	bh.Witness.Deserialize(br)
-	br.ReadLE(&b)
-	if b != byte(0) {
-		br.Err = fmt.Errorf("format error: check byte must equal 0 got %d", b)
-	}
 }
 
 //DeserializeUnsigned deserialize blockheader without witness
@@ -94,9 +100,18 @@ func (bh *BlockHeader) DeserializeUnsigned(br *io.BinaryReader) {
 
 func (bh *BlockHeader) Serialize(bw *io.BinaryWriter) {
 	bh.SerializeUnsigned(bw)
+	bh.serializeWitness(bw)
+	bw.WriteLE(byte(0))
+}
+
+// serializeWitness writes the witness, an empty one if it is nil such as for a block not signed yet
+func (bh *BlockHeader) serializeWitness(bw *io.BinaryWriter) {
 	bw.WriteLE(byte(1))
+	if bh.Witness == nil {
+		(&tx.Witness{}).Serialize(bw)
+		return
+	}
 	bh.Witness.Serialize(bw)
-	bw.WriteLE(byte(0))
 }
 
 //SerializeUnsigned serialize blockheader without witness
@@ -130,3 +145,46 @@ func (bh *BlockHeader) Hash() helper.UInt256 {
 	bh._hash, _ = helper.UInt256FromBytes(hash)
 	return bh._hash
 }
+
+// Verify checks that the header follows prev and is signed by the validators set in prev.NextConsensus
+func (bh *BlockHeader) Verify(prev *BlockHeader) error {
+	if prev == nil {
+		return fmt.Errorf("previous header is nil")
+	}
+	if bh.Index != prev.Index+1 {
+		return fmt.Errorf("index mismatch: expected %d got %d", prev.Index+1, bh.Index)
+	}
+	if bh.PrevHash != prev.Hash() {
+		return fmt.Errorf("previous hash mismatch: expected %s got %s", prev.Hash().String(), bh.PrevHash.String())
+	}
+	if bh.Timestamp <= prev.Timestamp {
+		return fmt.Errorf("timestamp %d is not after previous timestamp %d", bh.Timestamp, prev.Timestamp)
+	}
+	return bh.VerifyWitness(prev.NextConsensus)
+}
+
+// VerifyWitness checks that the verification script of the witness hashes to nextConsensus
+// and that the invocation script carries enough valid signatures of the header
+func (bh *BlockHeader) VerifyWitness(nextConsensus helper.UInt160) error {
+	if bh.Witness == nil {
+		return fmt.Errorf("witness is nil")
+	}
+	if bh.Witness.GetScriptHash() != nextConsensus {
+		return fmt.Errorf("witness script hash mismatch: expected %s got %s",
+			nextConsensus.String(), bh.Witness.GetScriptHash().String())
+	}
+	// the script is trusted once its hash matches, only the invocation script needs to be checked
+	verification := bh.Witness.VerificationScript
+	var ok bool
+	if len(verification) == 35 && verification[34] == byte(sc.CHECKSIG) {
+		ok = len(bh.Witness.InvocationScript) == 65 && tx.VerifySignatureWitness(bh.GetHashData(), bh.Witness)
+	} else if len(verification) > 0 && verification[len(verification)-1] == byte(sc.CHECKMULTISIG) {
+		ok = tx.VerifyMultiSignatureWitness(bh.GetHashData(), bh.Witness)
+	} else {
+		return fmt.Errorf("unsupported verification script")
+	}
+	if !ok {
+		return fmt.Errorf("invalid witness of block %d", bh.Index)
+	}
+	return nil
+}
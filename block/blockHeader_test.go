@@ -101,6 +101,16 @@ func TestBlockHeader_Serialize(t *testing.T) {
 	assert.Equal(t, helper.BytesToHex(requiredData), helper.BytesToHex(buf.Bytes()))
 }
 
+func TestBlockHeader_Serialize_NoWitness(t *testing.T) {
+	bh := SetupBlockHeaderWithValues()
+	bh.Witness = nil
+	buf := io.NewBufBinaryWriter()
+	bh.Serialize(buf.BinaryWriter)
+	assert.Nil(t, buf.Err)
+	b := buf.Bytes()
+	assert.Equal(t, "01000000", helper.BytesToHex(b[len(b)-4:])) // empty witness and check bit
+}
+
 func TestBlockHeader_SerializeUnsigned(t *testing.T) {
 	bh := SetupBlockHeaderWithValues()
 	buf := io.NewBufBinaryWriter()
@@ -0,0 +1,210 @@
+package block
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/tx"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// mainnet genesis block
+const genesisBlock = "000000000000000000000000000000000000000000000000000000000000000000000000f41bc036e39b0d6b0579c851c6fde83af802fa4e57bec0bc3365eae3abf43f8065fc8857000000001dac2b7c0000000059e75d652b5d3827bf04c165bbe9ef95cca4bf5501000151" +
+	"04" +
+	"00001dac2b7c00000000" +
+	"400000455b7b226c616e67223a227a682d434e222c226e616d65223a22e5b08fe89a81e882a1227d2c7b226c616e67223a22656e222c226e616d65223a22416e745368617265227d5d0000c16ff28623000000da1745e9b549bd0bfa1a569971c77eba30cd5a4b00000000" +
+	"400001445b7b226c616e67223a227a682d434e222c226e616d65223a22e5b08fe89a81e5b881227d2c7b226c616e67223a22656e222c226e616d65223a22416e74436f696e227d5d0000c16ff286230008009f7fd096d37ed2c0e3f7f0cfc924beef4ffceb6800000000" +
+	"01000000019b7cffdaa674beae0f930ebe6085af9093e5fe56b34a5c220ccdcf6efc336fc50000c16ff28623005fa99d93303775fe50ca119c327759313eccfa1c01000151"
+
+const genesisBlockJson = `{
+	"hash": "0xd42561e3d30e15be6400b6df2f328e02d2bf6354c41dce433bc57687c82144bf",
+	"size": 401,
+	"version": 0,
+	"previousblockhash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+	"merkleroot": "0x803ff4abe3ea6533bcc0be574efa02f83ae8fdc651c879056b0d9be336c01bf4",
+	"time": 1468595301,
+	"index": 0,
+	"nonce": "000000007c2bac1d",
+	"nextconsensus": "APyEx5f4Zm4oCHwFWiSTaph1fPBxZacYVR",
+	"script": {"invocation": "", "verification": "51"},
+	"tx": [
+		{
+			"txid": "0xfb5bd72b2d6792d75dc2f1084ffa9e9f70ca85543c717a6b13d9959b452a57d6",
+			"size": 10, "type": "MinerTransaction", "version": 0,
+			"attributes": [], "vin": [], "vout": [], "sys_fee": "0", "net_fee": "0", "scripts": [],
+			"nonce": 2083236893
+		},
+		{
+			"txid": "0xc56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b",
+			"size": 107, "type": "RegisterTransaction", "version": 0,
+			"attributes": [], "vin": [], "vout": [], "sys_fee": "0", "net_fee": "0", "scripts": [],
+			"asset": {
+				"type": "GoverningToken",
+				"name": [{"lang": "zh-CN", "name": "小蚁股"}, {"lang": "en", "name": "AntShare"}],
+				"amount": "100000000", "precision": 0, "owner": "00",
+				"admin": "Abf2qMs1pzQb8kYk9RuxtUb9jtRKJVuBJt"
+			}
+		},
+		{
+			"txid": "0x602c79718b16e442de58778e148d0b1084e3b2dffd5de6b7b16cee7969282de7",
+			"size": 106, "type": "RegisterTransaction", "version": 0,
+			"attributes": [], "vin": [], "vout": [], "sys_fee": "0", "net_fee": "0", "scripts": [],
+			"asset": {
+				"type": "UtilityToken",
+				"name": [{"lang": "zh-CN", "name": "小蚁币"}, {"lang": "en", "name": "AntCoin"}],
+				"amount": "100000000", "precision": 8, "owner": "00",
+				"admin": "AWKECj9RD8rS8RPcpCgYVjk1DeYyHwxZm3"
+			}
+		},
+		{
+			"txid": "0x3631f66024ca6f5b033d7e0809eb993443374830025af904fb51b0334f127cda",
+			"size": 69, "type": "IssueTransaction", "version": 0,
+			"attributes": [], "vin": [],
+			"vout": [{
+				"n": 0,
+				"asset": "0xc56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b",
+				"value": "100000000",
+				"address": "AQVh2pG732YvtNaxEGkQUei3YA4cvo7d2i"
+			}],
+			"sys_fee": "0", "net_fee": "0",
+			"scripts": [{"invocation": "", "verification": "51"}]
+		}
+	]
+}`
+
+var genesisTxIds = []string{
+	"fb5bd72b2d6792d75dc2f1084ffa9e9f70ca85543c717a6b13d9959b452a57d6",
+	"c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b",
+	"602c79718b16e442de58778e148d0b1084e3b2dffd5de6b7b16cee7969282de7",
+	"3631f66024ca6f5b033d7e0809eb993443374830025af904fb51b0334f127cda",
+}
+
+func TestNewBlockFromHexString(t *testing.T) {
+	block, err := NewBlockFromHexString(genesisBlock)
+	assert.Nil(t, err)
+	assert.Equal(t, "d42561e3d30e15be6400b6df2f328e02d2bf6354c41dce433bc57687c82144bf", block.HashString())
+	assert.Equal(t, 4, len(block.Tx))
+	for i, id := range genesisTxIds {
+		assert.Equal(t, id, block.Tx[i].HashString())
+	}
+	assert.IsType(t, &tx.MinerTransaction{}, block.Tx[0])
+	assert.IsType(t, &tx.RegisterTransaction{}, block.Tx[1])
+	assert.IsType(t, &tx.IssueTransaction{}, block.Tx[3])
+	assert.Equal(t, uint32(2083236893), block.Tx[0].(*tx.MinerTransaction).Nonce)
+	assert.Equal(t, tx.UtilityToken, block.Tx[2].(*tx.RegisterTransaction).AssetType)
+
+	_, err = NewBlockFromHexString(genesisBlock + "00")
+	assert.NotNil(t, err)
+	_, err = NewBlockFromHexString(genesisBlock[:len(genesisBlock)-2])
+	assert.NotNil(t, err)
+}
+
+func TestNewBlockFromRPC(t *testing.T) {
+	rpcBlock := models.RpcBlock{}
+	err := json.Unmarshal([]byte(genesisBlockJson), &rpcBlock)
+	assert.Nil(t, err)
+
+	block, err := NewBlockFromRPC(&rpcBlock)
+	assert.Nil(t, err)
+	assert.Equal(t, "d42561e3d30e15be6400b6df2f328e02d2bf6354c41dce433bc57687c82144bf", block.HashString())
+	assert.Equal(t, 4, len(block.Tx))
+	for i, id := range genesisTxIds {
+		assert.Equal(t, id, block.Tx[i].HashString())
+	}
+	raw, _ := NewBlockFromHexString(genesisBlock)
+	for i := range raw.Tx {
+		assert.Equal(t, raw.Tx[i].RawTransaction(), block.Tx[i].RawTransaction())
+	}
+
+	rpcBlock.Nonce = "000000007c2bac1e"
+	_, err = NewBlockFromRPC(&rpcBlock)
+	assert.NotNil(t, err)
+}
+
+func TestBlock_Serialize(t *testing.T) {
+	block, err := NewBlockFromHexString(genesisBlock)
+	assert.Nil(t, err)
+	assert.Equal(t, genesisBlock, hex.EncodeToString(block.RawBlock()))
+}
+
+func TestBlock_Serialize_NoWitness(t *testing.T) {
+	block, _ := NewBlockFromHexString(genesisBlock)
+	block.Witness = nil
+	raw := block.RawBlock()
+	assert.NotNil(t, raw)
+
+	// an empty witness is written
+	unsigned, err := NewBlockFromBytes(raw)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(unsigned.Witness.InvocationScript))
+	assert.Equal(t, 0, len(unsigned.Witness.VerificationScript))
+	assert.Equal(t, block.Hash(), unsigned.Hash())
+
+	block.Tx[1] = nil
+	assert.Nil(t, block.RawBlock())
+	_, err = block.ComputeMerkleRoot()
+	assert.NotNil(t, err)
+	assert.NotNil(t, block.Verify(&block.BlockHeader))
+}
+
+func TestBlock_ComputeMerkleRoot(t *testing.T) {
+	block, _ := NewBlockFromHexString(genesisBlock)
+	root, err := block.ComputeMerkleRoot()
+	assert.Nil(t, err)
+	assert.Equal(t, "803ff4abe3ea6533bcc0be574efa02f83ae8fdc651c879056b0d9be336c01bf4", root.String())
+
+	block.Tx = block.Tx[:3]
+	err = block.RebuildMerkleRoot()
+	assert.Nil(t, err)
+	assert.NotEqual(t, root, block.MerkleRoot)
+}
+
+func TestBlock_Verify(t *testing.T) {
+	pairs := make([]*keys.KeyPair, 4)
+	publicKeys := make([]*keys.PublicKey, 4)
+	for i := range pairs {
+		pairs[i], _ = keys.GenerateKeyPair()
+		publicKeys[i] = pairs[i].PublicKey
+	}
+	script, _ := keys.CreateMultiSigRedeemScript(3, publicKeys...)
+	nextConsensus, _ := helper.BytesToScriptHash(script)
+
+	genesis, _ := NewBlockFromHexString(genesisBlock)
+	prev := genesis.BlockHeader
+	prev.NextConsensus = nextConsensus
+
+	block, _ := NewBlockFromHexString(genesisBlock)
+	block.Index = 1
+	block.PrevHash = prev.Hash()
+	block.Timestamp = prev.Timestamp + 15
+	block.Tx = block.Tx[:1]
+	_ = block.RebuildMerkleRoot()
+	block.Witness, _ = tx.CreateMultiSignatureWitness(block.GetHashData(), pairs[:3], 3, publicKeys)
+	assert.Nil(t, block.Verify(&prev))
+
+	// not enough signatures
+	witness := block.Witness
+	block.Witness, _ = tx.CreateMultiSignatureWitness(block.GetHashData(), pairs[:2], 2, publicKeys)
+	block.Witness.VerificationScript = script
+	assert.NotNil(t, block.Verify(&prev))
+	block.Witness = witness
+
+	// signed by other validators
+	prev.NextConsensus = genesis.NextConsensus
+	assert.NotNil(t, block.Verify(&prev))
+	prev.NextConsensus = nextConsensus
+
+	// transactions changed
+	block.Tx = genesis.Tx
+	assert.NotNil(t, block.Verify(&prev))
+	block.Tx = block.Tx[:1]
+
+	// not linked
+	block.Index = 2
+	assert.NotNil(t, block.Verify(&prev))
+	block.Index = 1
+	assert.Nil(t, block.Verify(&prev))
+}
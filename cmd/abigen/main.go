@@ -0,0 +1,54 @@
+// Command abigen generates a Go package to call a neo 2.x contract from its .abi.json file.
+//
+//	abigen -abi token.abi.json -pkg token -type Token -out token/token.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joeqian10/neo-gogogo/abi"
+)
+
+func main() {
+	abiPath := flag.String("abi", "", "path of the .abi.json file")
+	pkg := flag.String("pkg", "", "package name of the generated file")
+	typeName := flag.String("type", "", "type name of the contract, the package name in title case by default")
+	out := flag.String("out", "", "output file, stdout by default")
+	flag.Parse()
+
+	if *abiPath == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *typeName == "" {
+		*typeName = strings.ToUpper((*pkg)[:1]) + (*pkg)[1:]
+	}
+	if err := run(*abiPath, *pkg, *typeName, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "abigen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(abiPath string, pkg string, typeName string, out string) error {
+	a, err := abi.LoadContractAbi(abiPath)
+	if err != nil {
+		return err
+	}
+	code, err := a.GenerateBinding(pkg, typeName)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(out, code, 0644)
+}
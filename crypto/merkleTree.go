@@ -0,0 +1,93 @@
+package crypto
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// MerkleTree is the binary hash tree of the transactions in a block. The hashes are
+// in little endian, the same as helper.UInt256.Bytes(). A node with no right sibling
+// is paired with itself, as neo does.
+type MerkleTree struct {
+	levels [][][]byte // levels[0] are the leaves, the last level is the root
+}
+
+// NewMerkleTree builds the tree of the given hashes, which must not be empty
+func NewMerkleTree(hashes [][]byte) (*MerkleTree, error) {
+	if len(hashes) == 0 {
+		return nil, fmt.Errorf("merkle tree needs at least one hash")
+	}
+	leaves := make([][]byte, len(hashes))
+	for i, h := range hashes {
+		if len(h) != 32 {
+			return nil, fmt.Errorf("expected hash of size 32 got %d", len(h))
+		}
+		leaves[i] = h
+	}
+	levels := [][][]byte{leaves}
+	for level := leaves; len(level) > 1; {
+		parents := make([][]byte, (len(level)+1)/2)
+		for i := range parents {
+			left := level[i*2]
+			right := left
+			if i*2+1 < len(level) {
+				right = level[i*2+1]
+			}
+			parents[i] = Hash256(append(append(make([]byte, 0, 64), left...), right...))
+		}
+		levels = append(levels, parents)
+		level = parents
+	}
+	return &MerkleTree{levels: levels}, nil
+}
+
+// ComputeMerkleRoot returns the root of the tree of the given hashes
+func ComputeMerkleRoot(hashes [][]byte) ([]byte, error) {
+	tree, err := NewMerkleTree(hashes)
+	if err != nil {
+		return nil, err
+	}
+	return tree.Root(), nil
+}
+
+// Root returns the hash of the root node
+func (t *MerkleTree) Root() []byte {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Depth returns the number of levels, 1 for a single hash
+func (t *MerkleTree) Depth() int {
+	return len(t.levels)
+}
+
+// Proof returns the sibling hashes from the leaf at index up to the root,
+// which is checked by VerifyMerkleProof
+func (t *MerkleTree) Proof(index int) ([][]byte, error) {
+	if index < 0 || index >= len(t.levels[0]) {
+		return nil, fmt.Errorf("index %d out of range", index)
+	}
+	var path [][]byte
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling >= len(level) {
+			sibling = index
+		}
+		path = append(path, level[sibling])
+		index /= 2
+	}
+	return path, nil
+}
+
+// VerifyMerkleProof checks that hash is the leaf at index of the tree with the given root
+func VerifyMerkleProof(root []byte, hash []byte, index int, path [][]byte) bool {
+	current := hash
+	for _, sibling := range path {
+		if index%2 == 0 {
+			current = Hash256(append(append(make([]byte, 0, 64), current...), sibling...))
+		} else {
+			current = Hash256(append(append(make([]byte, 0, 64), sibling...), current...))
+		}
+		index /= 2
+	}
+	return index == 0 && bytes.Equal(current, root)
+}
@@ -0,0 +1,61 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hashes in big endian as shown by neo, the tree works on little endian
+func leHashes(hashes ...string) [][]byte {
+	result := make([][]byte, len(hashes))
+	for i, h := range hashes {
+		b, _ := hex.DecodeString(h)
+		for l, r := 0, len(b)-1; l < r; l, r = l+1, r-1 {
+			b[l], b[r] = b[r], b[l]
+		}
+		result[i] = b
+	}
+	return result
+}
+
+func TestComputeMerkleRoot(t *testing.T) {
+	// mainnet genesis block
+	hashes := leHashes(
+		"fb5bd72b2d6792d75dc2f1084ffa9e9f70ca85543c717a6b13d9959b452a57d6",
+		"c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b",
+		"602c79718b16e442de58778e148d0b1084e3b2dffd5de6b7b16cee7969282de7",
+		"3631f66024ca6f5b033d7e0809eb993443374830025af904fb51b0334f127cda")
+	root, err := ComputeMerkleRoot(hashes)
+	assert.Nil(t, err)
+	assert.Equal(t, leHashes("803ff4abe3ea6533bcc0be574efa02f83ae8fdc651c879056b0d9be336c01bf4")[0], root)
+
+	root, err = ComputeMerkleRoot(hashes[:1])
+	assert.Nil(t, err)
+	assert.Equal(t, hashes[0], root)
+
+	_, err = ComputeMerkleRoot(nil)
+	assert.NotNil(t, err)
+	_, err = ComputeMerkleRoot([][]byte{{0x01}})
+	assert.NotNil(t, err)
+}
+
+func TestMerkleTree_Proof(t *testing.T) {
+	hashes := make([][]byte, 5)
+	for i := range hashes {
+		hashes[i] = Hash256([]byte{byte(i)})
+	}
+	tree, err := NewMerkleTree(hashes)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, tree.Depth())
+
+	for i, h := range hashes {
+		path, err := tree.Proof(i)
+		assert.Nil(t, err)
+		assert.True(t, VerifyMerkleProof(tree.Root(), h, i, path))
+		assert.False(t, VerifyMerkleProof(tree.Root(), hashes[(i+1)%5], i, path))
+	}
+	_, err = tree.Proof(5)
+	assert.NotNil(t, err)
+}
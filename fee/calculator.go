@@ -0,0 +1,160 @@
+package fee
+
+import (
+	"bytes"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/tx"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+)
+
+// signatureSize is the size of an invocation script pushing a signature
+const signatureSize = 65
+
+// Calculator computes the fees of transactions offline by a Policy
+type Calculator struct {
+	Policy Policy
+}
+
+func NewCalculator(policy Policy) *Calculator {
+	return &Calculator{Policy: policy}
+}
+
+// Calculate returns the system fee and the network fee required by t, see EstimateSize for verificationScripts
+func (c *Calculator) Calculate(t tx.ITransaction, verificationScripts ...[]byte) (helper.Fixed8, helper.Fixed8) {
+	return c.SystemFee(t), c.NetworkFeeOfSize(EstimateSize(t, verificationScripts...))
+}
+
+// SystemFee returns the system fee of t, which is the Gas of an InvocationTransaction
+func (c *Calculator) SystemFee(t tx.ITransaction) helper.Fixed8 {
+	switch v := t.(type) {
+	case *tx.InvocationTransaction:
+		return v.Gas
+	case *tx.RegisterTransaction:
+		// NEO and GAS are registered in the genesis block
+		if v.AssetType == tx.GoverningToken || v.AssetType == tx.UtilityToken {
+			return helper.Zero
+		}
+	case *tx.IssueTransaction:
+		if v.Version >= 1 {
+			return helper.Zero
+		}
+		free := true
+		for _, output := range v.Outputs {
+			if output.AssetId != tx.NeoToken && output.AssetId != tx.GasToken {
+				free = false
+				break
+			}
+		}
+		if free {
+			return helper.Zero
+		}
+	case *tx.StateTransaction:
+		// registering a validator costs the same as an EnrollmentTransaction
+		fee := helper.Zero
+		for _, d := range v.Descriptors {
+			if d.Type == tx.Validator && d.Field == "Registered" && isTrue(d.Value) {
+				fee = fee.Add(c.Policy.SystemFees[tx.Enrollment_Transaction])
+			}
+		}
+		return fee
+	}
+	return c.Policy.SystemFees[t.GetTransaction().Type]
+}
+
+// InvocationGas returns the Gas of an InvocationTransaction running a script which consumes gasConsumed,
+// the free gas is deducted and the rest is rounded up to an integer
+func (c *Calculator) InvocationGas(gasConsumed helper.Fixed8) helper.Fixed8 {
+	gas := gasConsumed.Sub(c.Policy.FreeGas)
+	if !gas.GreaterThan(helper.Zero) {
+		return helper.Zero
+	}
+	return gas.Ceiling()
+}
+
+// NetworkFee returns the network fee required by the size of t
+func (c *Calculator) NetworkFee(t tx.ITransaction) helper.Fixed8 {
+	return c.NetworkFeeOfSize(len(t.RawTransaction()))
+}
+
+// NetworkFeeOfSize returns the network fee required by a transaction of size bytes. A transaction above
+// MaxFreeTransactionSize pays FeePerExtraByte for each extra byte, and no less than LowPriorityThreshold
+// because a low priority transaction can not exceed the free size.
+func (c *Calculator) NetworkFeeOfSize(size int) helper.Fixed8 {
+	extra := size - c.Policy.MaxFreeTransactionSize
+	if extra <= 0 {
+		return helper.Zero
+	}
+	fee := helper.NewFixed8(c.Policy.FeePerExtraByte.Value * int64(extra))
+	if fee.LessThan(c.Policy.LowPriorityThreshold) {
+		return c.Policy.LowPriorityThreshold
+	}
+	return fee
+}
+
+// IsLowPriority returns true if a transaction paying networkFee is low priority, at most
+// MaxFreeTransactionsPerBlock of them are packed in a block
+func (c *Calculator) IsLowPriority(networkFee helper.Fixed8) bool {
+	return networkFee.LessThan(c.Policy.LowPriorityThreshold)
+}
+
+// EstimateSize returns the size of t once it is signed for verificationScripts in the signing order. The invocation
+// scripts of the signature and the multi-signature contracts are estimated by their signature counts, the others
+// are taken as empty. The Script attribute added by tx.AddSignature for the first signer is counted as well.
+// The current size is returned if no verification script is given.
+func EstimateSize(t tx.ITransaction, verificationScripts ...[]byte) int {
+	if len(verificationScripts) == 0 {
+		return len(t.RawTransaction())
+	}
+	size := len(t.UnsignedRawTransaction()) + varIntSize(len(verificationScripts))
+	base := t.GetTransaction()
+	if len(base.Witnesses) == 0 && !hasScriptAttribute(base, verificationScripts[0]) {
+		n := len(base.Attributes)
+		size += 1 + 20 + varIntSize(n+1) - varIntSize(n) // usage and script hash
+	}
+	for _, script := range verificationScripts {
+		invocationSize := signatureSize * signatureCount(script)
+		size += varIntSize(invocationSize) + invocationSize + varIntSize(len(script)) + len(script)
+	}
+	return size
+}
+
+// signatureCount returns the number of the signatures required by a verification script
+func signatureCount(script []byte) int {
+	if len(script) == 35 && script[0] == 33 && script[34] == 0xac { // PUSHBYTES33 CHECKSIG
+		return 1
+	}
+	if m, _, err := keys.ParseMultiSigRedeemScript(script); err == nil {
+		return m
+	}
+	return 0
+}
+
+func hasScriptAttribute(t *tx.Transaction, verificationScript []byte) bool {
+	scriptHash, err := helper.BytesToScriptHash(verificationScript)
+	if err != nil {
+		return false
+	}
+	for _, attr := range t.Attributes {
+		if attr.Usage == tx.Script && bytes.Equal(attr.Data, scriptHash.Bytes()) {
+			return true
+		}
+	}
+	return false
+}
+
+func varIntSize(n int) int {
+	buf := io.NewBufBinaryWriter()
+	buf.WriteVarUint(uint64(n))
+	return len(buf.Bytes())
+}
+
+func isTrue(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return true
+		}
+	}
+	return false
+}
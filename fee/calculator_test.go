@@ -0,0 +1,88 @@
+package fee
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/tx"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculator_SystemFee(t *testing.T) {
+	c := NewCalculator(MainNetPolicy())
+
+	itx := tx.NewInvocationTransaction([]byte{0x51})
+	itx.Gas = helper.Fixed8FromInt64(2)
+	assert.Equal(t, helper.Fixed8FromInt64(2), c.SystemFee(itx))
+
+	assert.Equal(t, helper.Zero, c.SystemFee(tx.NewContractTransaction()))
+
+	rtx := &tx.RegisterTransaction{Transaction: tx.NewTransaction(), AssetType: tx.Token}
+	rtx.Type = tx.Register_Transaction
+	assert.Equal(t, helper.Fixed8FromInt64(10000), c.SystemFee(rtx))
+	rtx.AssetType = tx.UtilityToken
+	assert.Equal(t, helper.Zero, c.SystemFee(rtx))
+
+	asset, _ := helper.UInt256FromString("a1b2c3d4e5f60718293a4b5c6d7e8f901a2b3c4d5e6f708192a3b4c5d6e7f809")
+	istx := tx.NewIssueTransaction(nil)
+	istx.Outputs = []*tx.TransactionOutput{tx.NewTransactionOutput(tx.GasToken, helper.One, helper.UInt160{})}
+	assert.Equal(t, helper.Zero, c.SystemFee(istx))
+	istx.Outputs = append(istx.Outputs, tx.NewTransactionOutput(asset, helper.One, helper.UInt160{}))
+	assert.Equal(t, helper.Fixed8FromInt64(500), c.SystemFee(istx))
+	assert.Equal(t, helper.Fixed8FromInt64(5), NewCalculator(TestNetPolicy()).SystemFee(istx))
+
+	stx := tx.NewStateTransaction(nil)
+	stx.Descriptors = []*tx.StateDescriptor{
+		{Type: tx.Validator, Field: "Registered", Value: []byte{1}},
+		{Type: tx.Account, Field: "Votes", Value: []byte{0}},
+	}
+	assert.Equal(t, helper.Fixed8FromInt64(1000), c.SystemFee(stx))
+}
+
+func TestCalculator_InvocationGas(t *testing.T) {
+	c := NewCalculator(MainNetPolicy())
+	assert.Equal(t, helper.Zero, c.InvocationGas(helper.Fixed8FromInt64(10)))
+	gas, _ := helper.Fixed8FromString("10.001")
+	assert.Equal(t, helper.One, c.InvocationGas(gas))
+	gas, _ = helper.Fixed8FromString("12")
+	assert.Equal(t, helper.Fixed8FromInt64(2), c.InvocationGas(gas))
+}
+
+func TestCalculator_NetworkFeeOfSize(t *testing.T) {
+	c := NewCalculator(MainNetPolicy())
+	assert.Equal(t, helper.Zero, c.NetworkFeeOfSize(1024))
+	// no less than the low priority threshold
+	assert.Equal(t, helper.NewFixed8(100000), c.NetworkFeeOfSize(1025))
+	assert.Equal(t, helper.NewFixed8(1000*1000), c.NetworkFeeOfSize(2024))
+	assert.True(t, c.IsLowPriority(helper.NewFixed8(99999)))
+	assert.False(t, c.IsLowPriority(helper.NewFixed8(100000)))
+}
+
+func TestCalculator_FeePolicy(t *testing.T) {
+	// the main net policy is the default policy of the TransactionBuilder
+	var policy tx.FeePolicy = NewCalculator(MainNetPolicy())
+	for _, size := range []int{1024, 1025, 2024} {
+		assert.Equal(t, tx.DefaultFeePolicy{}.NetworkFeeOfSize(size), policy.NetworkFeeOfSize(size))
+	}
+	for _, gas := range []string{"9.5", "10", "10.001", "12"} {
+		consumed, _ := helper.Fixed8FromString(gas)
+		assert.Equal(t, tx.DefaultFeePolicy{}.InvocationGas(consumed), policy.InvocationGas(consumed))
+	}
+}
+
+func TestEstimateSize(t *testing.T) {
+	pair, _ := keys.NewKeyPairFromWIF("L1caMUAsHr2dKwhqbMpYRcCzmzvZTfYZSCBefgARhz9iimAFRn1z")
+	itx := tx.NewInvocationTransaction(make([]byte, 2000))
+	verification := keys.CreateSignatureRedeemScript(pair.PublicKey)
+	size := EstimateSize(itx, verification)
+
+	err := tx.AddSignature(itx, pair)
+	assert.Nil(t, err)
+	assert.Equal(t, len(itx.RawTransaction()), size)
+	assert.Equal(t, size, EstimateSize(itx))
+
+	sysFee, netFee := NewCalculator(MainNetPolicy()).Calculate(itx)
+	assert.Equal(t, helper.Zero, sysFee)
+	assert.Equal(t, helper.NewFixed8(int64(size-1024)*1000), netFee)
+}
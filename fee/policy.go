@@ -0,0 +1,53 @@
+package fee
+
+import (
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/tx"
+)
+
+// Policy is the fee rules of a network, the system fees are from protocol.json and the others are
+// from the SimplePolicy plugin of neo 2.x
+type Policy struct {
+	// SystemFees are the system fees of RegisterTransaction, IssueTransaction, PublishTransaction
+	// and EnrollmentTransaction, the other types have no system fee
+	SystemFees map[tx.TransactionType]helper.Fixed8
+	// FreeGas is the gas of an invocation which is free
+	FreeGas helper.Fixed8
+	// MaxFreeTransactionSize is the size of a transaction which requires no network fee
+	MaxFreeTransactionSize int
+	// FeePerExtraByte is the network fee per byte above MaxFreeTransactionSize
+	FeePerExtraByte helper.Fixed8
+	// LowPriorityThreshold is the network fee below which a transaction is low priority
+	LowPriorityThreshold helper.Fixed8
+	// MaxFreeTransactionsPerBlock is the max number of low priority transactions in a block
+	MaxFreeTransactionsPerBlock int
+}
+
+// MainNetPolicy returns the policy of the neo 2.x main net
+func MainNetPolicy() Policy {
+	return Policy{
+		SystemFees: map[tx.TransactionType]helper.Fixed8{
+			tx.Enrollment_Transaction: helper.Fixed8FromInt64(1000),
+			tx.Issue_Transaction:      helper.Fixed8FromInt64(500),
+			tx.Publish_Transaction:    helper.Fixed8FromInt64(500),
+			tx.Register_Transaction:   helper.Fixed8FromInt64(10000),
+		},
+		FreeGas:                     helper.NewFixed8(tx.DefaultFreeGas),
+		MaxFreeTransactionSize:      tx.DefaultMaxFreeTransactionSize,
+		FeePerExtraByte:             helper.NewFixed8(tx.DefaultFeePerExtraByte),
+		LowPriorityThreshold:        helper.NewFixed8(tx.DefaultLowPriorityThreshold),
+		MaxFreeTransactionsPerBlock: 20,
+	}
+}
+
+// TestNetPolicy returns the policy of the neo 2.x test net, whose system fees are lower
+func TestNetPolicy() Policy {
+	p := MainNetPolicy()
+	p.SystemFees = map[tx.TransactionType]helper.Fixed8{
+		tx.Enrollment_Transaction: helper.Fixed8FromInt64(10),
+		tx.Issue_Transaction:      helper.Fixed8FromInt64(5),
+		tx.Publish_Transaction:    helper.Fixed8FromInt64(5),
+		tx.Register_Transaction:   helper.Fixed8FromInt64(100),
+	}
+	return p
+}
@@ -1,8 +1,11 @@
 package helper
 
 import (
+	"encoding/json"
 	"fmt"
 	"go/constant"
+	"math"
+	"math/big"
 	"strconv"
 	"strings"
 )
@@ -35,9 +38,9 @@ func Fixed8ToInt64(f Fixed8) int64 {
 	return int64(f.Value) / D
 }
 
-// Fixed8FromFloat64 returns a new Fixed8 type multiplied by decimals.
+// Fixed8FromFloat64 returns a new Fixed8 type multiplied by decimals, rounded to the nearest satoshi.
 func Fixed8FromFloat64(val float64) Fixed8 {
-	return NewFixed8(int64(val * D))
+	return NewFixed8(int64(math.Round(val * D)))
 }
 
 // Fixed8ToFloat64 returns the decimal value of a Fixed8 type.
@@ -64,7 +67,7 @@ func Fixed8FromString(s string) (Fixed8, error) {
 	for i := len(parts[1]); i < PRECISION; i++ {
 		dp *= 10
 	}
-	if ip < 0 {
+	if strings.HasPrefix(parts[0], "-") { // ip is 0 for "-0.5"
 		return NewFixed8(ip*D - dp), nil
 	}
 	return NewFixed8(ip*D + dp), nil
@@ -92,6 +95,49 @@ func Fixed8ToString(input Fixed8) string {
 	return buf.String()
 }
 
+// Fixed8FromNumber parses a json number such as "81.96167" or "1E-05" exactly, and rounds it to the nearest satoshi.
+// The nodes format the amounts as doubles, so a number may have more than 8 decimals.
+func Fixed8FromNumber(s string) (Fixed8, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Fixed8{}, fmt.Errorf("invalid Fixed8 number %s", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(D))
+	// round half away from zero
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(r.Sign())))
+	}
+	if !q.IsInt64() {
+		return Fixed8{}, fmt.Errorf("Fixed8 number %s out of range", s)
+	}
+	return NewFixed8(q.Int64()), nil
+}
+
+// UnmarshalJSON implements the json unmarshaller interface, a Fixed8 can be a json number or a decimal string.
+func (f *Fixed8) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, "\"") {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	v, err := Fixed8FromNumber(s)
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// MarshalJSON implements the json marshaller interface, a Fixed8 is a decimal string as the nodes return.
+func (f Fixed8) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.String())
+}
+
 // String implements the Stringer interface.
 func (f Fixed8) String() string {
 	buf := new(strings.Builder)
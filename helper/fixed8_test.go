@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -24,6 +25,10 @@ func TestFixed8FromString(t *testing.T) {
 	f, err := Fixed8FromString("1234.5678")
 	assert.Nil(t, err)
 	assert.Equal(t, int64(123456780000), f.Value)
+
+	f, err = Fixed8FromString("-0.00000001")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), f.Value)
 }
 
 func TestFixed8ToInt64(t *testing.T) {
@@ -90,4 +95,48 @@ func TestFixed8_LessThan(t *testing.T) {
 func TestFixed8_String(t *testing.T) {
 	s := NewFixed8(100000000).String()
 	assert.Equal(t, "1", s)
-}
\ No newline at end of file
+}
+func TestFixed8FromNumber(t *testing.T) {
+	f, err := Fixed8FromNumber("81.96167")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8196167000), f.Value)
+
+	f, err = Fixed8FromNumber("1E-05")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000), f.Value)
+
+	f, err = Fixed8FromNumber("1e+08")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100000000*D), f.Value)
+
+	// rounded to the nearest satoshi
+	f, err = Fixed8FromNumber("0.30000000000000004")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30000000), f.Value)
+	f, err = Fixed8FromNumber("-0.000000005")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), f.Value)
+
+	_, err = Fixed8FromNumber("abc")
+	assert.NotNil(t, err)
+	_, err = Fixed8FromNumber("100000000000")
+	assert.NotNil(t, err)
+}
+
+func TestFixed8_UnmarshalJSON(t *testing.T) {
+	var v struct {
+		A Fixed8 `json:"a"`
+		B Fixed8 `json:"b"`
+	}
+	err := json.Unmarshal([]byte(`{"a": 27844.821, "b": "0.126"}`), &v)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2784482100000), v.A.Value)
+	assert.Equal(t, int64(12600000), v.B.Value)
+
+	b, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":"27844.821","b":"0.126"}`, string(b))
+
+	err = json.Unmarshal([]byte(`{"a": true}`), &v)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,93 @@
+package lightclient
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/joeqian10/neo-gogogo/block"
+	"github.com/joeqian10/neo-gogogo/helper"
+)
+
+// ErrNotFound is returned by an IHeaderStore for an unknown index or hash
+var ErrNotFound = errors.New("header not found")
+
+// IHeaderStore persists the verified header chain, which is contiguous
+// from the trusted header to the tip
+type IHeaderStore interface {
+	// Tip returns the header with the highest index, nil if the store is empty
+	Tip() (*block.BlockHeader, error)
+	GetHeader(index uint32) (*block.BlockHeader, error)
+	GetIndex(hash helper.UInt256) (uint32, error)
+	// PutHeader appends header after the tip
+	PutHeader(header *block.BlockHeader) error
+	// DeleteFrom removes the headers from index to the tip, it is used to switch to another fork
+	DeleteFrom(index uint32) error
+}
+
+// MemoryHeaderStore keeps the headers in memory
+type MemoryHeaderStore struct {
+	mu      sync.RWMutex
+	headers map[uint32]*block.BlockHeader
+	indexes map[helper.UInt256]uint32
+	tip     *block.BlockHeader
+}
+
+func NewMemoryHeaderStore() *MemoryHeaderStore {
+	return &MemoryHeaderStore{
+		headers: make(map[uint32]*block.BlockHeader),
+		indexes: make(map[helper.UInt256]uint32),
+	}
+}
+
+func (s *MemoryHeaderStore) Tip() (*block.BlockHeader, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tip, nil
+}
+
+func (s *MemoryHeaderStore) GetHeader(index uint32) (*block.BlockHeader, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	header, ok := s.headers[index]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return header, nil
+}
+
+func (s *MemoryHeaderStore) GetIndex(hash helper.UInt256) (uint32, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	index, ok := s.indexes[hash]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return index, nil
+}
+
+func (s *MemoryHeaderStore) PutHeader(header *block.BlockHeader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tip != nil && header.Index != s.tip.Index+1 {
+		return errors.New("header does not follow the tip")
+	}
+	s.headers[header.Index] = header
+	s.indexes[header.Hash()] = header.Index
+	s.tip = header
+	return nil
+}
+
+func (s *MemoryHeaderStore) DeleteFrom(index uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.tip != nil && s.tip.Index >= index {
+		delete(s.headers, s.tip.Index)
+		delete(s.indexes, s.tip.Hash())
+		if s.tip.Index == 0 {
+			s.tip = nil
+			break
+		}
+		s.tip = s.headers[s.tip.Index-1] // nil once the first header is removed
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package lightclient
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/block"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryHeaderStore(t *testing.T) {
+	v := newValidators(t)
+	chain := v.extend([]*block.BlockHeader{v.genesis()}, 3, 0)
+	s := NewMemoryHeaderStore()
+	tip, err := s.Tip()
+	assert.Nil(t, err)
+	assert.Nil(t, tip)
+
+	for _, h := range chain {
+		assert.Nil(t, s.PutHeader(h))
+	}
+	assert.NotNil(t, s.PutHeader(chain[1]))
+	tip, _ = s.Tip()
+	assert.Equal(t, uint32(3), tip.Index)
+	index, err := s.GetIndex(chain[2].Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), index)
+
+	assert.Nil(t, s.DeleteFrom(2))
+	tip, _ = s.Tip()
+	assert.Equal(t, uint32(1), tip.Index)
+	_, err = s.GetHeader(2)
+	assert.Equal(t, ErrNotFound, err)
+	_, err = s.GetIndex(chain[3].Hash())
+	assert.Equal(t, ErrNotFound, err)
+
+	assert.Nil(t, s.DeleteFrom(0))
+	tip, _ = s.Tip()
+	assert.Nil(t, tip)
+}
@@ -0,0 +1,302 @@
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/joeqian10/neo-gogogo/block"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/mpt"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/tx"
+)
+
+const DefaultMaxReorgDepth = 100
+
+var (
+	// ErrForkDetected is returned when the node follows another validly signed chain which is not longer than ours
+	ErrForkDetected = errors.New("fork detected")
+	// ErrReorgTooDeep is returned when no common ancestor is found within MaxReorgDepth headers
+	ErrReorgTooDeep = errors.New("reorg deeper than the limit")
+)
+
+// LightClient follows the header chain of a node starting from a trusted header. Every header
+// is verified against the NextConsensus of its previous header before it is stored, so the
+// node is only trusted to deliver the data, not to tell the truth.
+//
+//	c, err := lightclient.NewLightClient(rpc.NewClient(url), lightclient.NewMemoryHeaderStore(), checkpoint)
+//	tip, err := c.Sync()
+type LightClient struct {
+	Client        rpc.IRpcClient
+	MaxReorgDepth uint32 // max number of headers replaced when switching to another fork
+	// OnReorg is called after switching to another fork, removed are the replaced headers in ascending order
+	OnReorg func(ancestor *block.BlockHeader, removed []*block.BlockHeader)
+
+	store   IHeaderStore
+	trusted uint32 // index of the trusted header, it is never replaced
+	syncMu  sync.Mutex
+}
+
+// NewLightClient creates a LightClient whose chain starts from trusted, usually the genesis block
+// or a checkpoint obtained out of band. A non empty store must contain trusted.
+func NewLightClient(client rpc.IRpcClient, store IHeaderStore, trusted *block.BlockHeader) (*LightClient, error) {
+	tip, err := store.Tip()
+	if err != nil {
+		return nil, err
+	}
+	if tip == nil {
+		if err = store.PutHeader(trusted); err != nil {
+			return nil, err
+		}
+	} else {
+		stored, err := store.GetHeader(trusted.Index)
+		if err != nil {
+			return nil, fmt.Errorf("trusted header is not in the store: %v", err)
+		}
+		if stored.Hash() != trusted.Hash() {
+			return nil, fmt.Errorf("trusted header %s differs from the stored one", trusted.Hash().String())
+		}
+	}
+	return &LightClient{
+		Client:        client,
+		MaxReorgDepth: DefaultMaxReorgDepth,
+		store:         store,
+		trusted:       trusted.Index,
+	}, nil
+}
+
+// Tip returns the verified header with the highest index
+func (c *LightClient) Tip() (*block.BlockHeader, error) {
+	return c.store.Tip()
+}
+
+// GetHeaderByIndex returns a verified header, ErrNotFound if it is not synced
+func (c *LightClient) GetHeaderByIndex(index uint32) (*block.BlockHeader, error) {
+	return c.store.GetHeader(index)
+}
+
+// GetHeaderByHash returns a verified header, ErrNotFound if it is not synced
+func (c *LightClient) GetHeaderByHash(hash helper.UInt256) (*block.BlockHeader, error) {
+	index, err := c.store.GetIndex(hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.store.GetHeader(index)
+}
+
+// Sync downloads and verifies the headers up to the height of the node, and returns the new tip.
+// If the node is on another fork, the chain switches to it when it is valid and longer than ours,
+// else ErrForkDetected is returned. Headers verified before an error are kept.
+func (c *LightClient) Sync() (*block.BlockHeader, error) {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	tip, err := c.store.Tip()
+	if err != nil {
+		return nil, err
+	}
+	response := c.Client.GetBlockCount()
+	if response.HasError() {
+		return tip, response.GetError()
+	}
+	if response.Result < 1 {
+		return tip, fmt.Errorf("invalid block count %d", response.Result)
+	}
+	height := uint32(response.Result - 1)
+
+	// the node may have replaced our tip even if it has no new block
+	check := tip.Index
+	if height < check {
+		check = height
+	}
+	if check > c.trusted {
+		same, err := c.sameAsNode(check)
+		if err != nil {
+			return tip, err
+		}
+		if !same {
+			if tip, err = c.reorg(check, height); err != nil {
+				return tip, err
+			}
+		}
+	}
+
+	for tip.Index < height {
+		header, err := c.fetchHeader(tip.Index + 1)
+		if err != nil {
+			return tip, err
+		}
+		if header.PrevHash != tip.Hash() {
+			// the node replaced our tip since the check above
+			if tip, err = c.reorg(tip.Index, height); err != nil {
+				return tip, err
+			}
+			continue
+		}
+		if err = header.Verify(tip); err != nil {
+			return tip, err
+		}
+		if err = c.store.PutHeader(header); err != nil {
+			return tip, err
+		}
+		tip = header
+	}
+	return tip, nil
+}
+
+// reorg finds the common ancestor of our chain and the chain of the node below index,
+// then switches to the chain of the node if it is valid and longer than ours
+func (c *LightClient) reorg(index uint32, height uint32) (*block.BlockHeader, error) {
+	tip, err := c.store.Tip()
+	if err != nil {
+		return nil, err
+	}
+	var ancestor *block.BlockHeader
+	for i := index; i > c.trusted && index-i < c.MaxReorgDepth; {
+		i--
+		same, err := c.sameAsNode(i)
+		if err != nil {
+			return tip, err
+		}
+		if same {
+			if ancestor, err = c.store.GetHeader(i); err != nil {
+				return tip, err
+			}
+			break
+		}
+	}
+	if ancestor == nil {
+		return tip, ErrReorgTooDeep
+	}
+	if height <= tip.Index {
+		return tip, fmt.Errorf("%w: the node has another header at %d", ErrForkDetected, ancestor.Index+1)
+	}
+
+	// verify the whole branch before touching the store
+	branch := make([]*block.BlockHeader, 0, height-ancestor.Index)
+	prev := ancestor
+	for i := ancestor.Index + 1; i <= height; i++ {
+		header, err := c.fetchHeader(i)
+		if err != nil {
+			return tip, err
+		}
+		if err = header.Verify(prev); err != nil {
+			return tip, fmt.Errorf("%w: invalid branch from %d: %v", ErrForkDetected, ancestor.Index+1, err)
+		}
+		branch = append(branch, header)
+		prev = header
+	}
+
+	removed := make([]*block.BlockHeader, 0, tip.Index-ancestor.Index)
+	for i := ancestor.Index + 1; i <= tip.Index; i++ {
+		header, err := c.store.GetHeader(i)
+		if err != nil {
+			return tip, err
+		}
+		removed = append(removed, header)
+	}
+	if err = c.store.DeleteFrom(ancestor.Index + 1); err != nil {
+		return tip, err
+	}
+	for _, header := range branch {
+		if err = c.store.PutHeader(header); err != nil {
+			return ancestor, err
+		}
+	}
+	if c.OnReorg != nil {
+		c.OnReorg(ancestor, removed)
+	}
+	return prev, nil
+}
+
+// sameAsNode checks whether the node has our header at index
+func (c *LightClient) sameAsNode(index uint32) (bool, error) {
+	header, err := c.store.GetHeader(index)
+	if err != nil {
+		return false, err
+	}
+	response := c.Client.GetBlockHash(index)
+	if response.HasError() {
+		return false, response.GetError()
+	}
+	hash, err := helper.UInt256FromString(response.Result)
+	if err != nil {
+		return false, err
+	}
+	return hash == header.Hash(), nil
+}
+
+func (c *LightClient) fetchHeader(index uint32) (*block.BlockHeader, error) {
+	hashResponse := c.Client.GetBlockHash(index)
+	if hashResponse.HasError() {
+		return nil, hashResponse.GetError()
+	}
+	response := c.Client.GetBlockHeaderByHash(hashResponse.Result)
+	if response.HasError() {
+		return nil, response.GetError()
+	}
+	header, err := block.NewBlockHeaderFromRPC(&response.Result)
+	if err != nil {
+		return nil, err
+	}
+	if header.Index != index {
+		return nil, fmt.Errorf("node returned header %d for index %d", header.Index, index)
+	}
+	return header, nil
+}
+
+// VerifyBlock checks that the block is in the verified chain and that its transactions match the merkle root
+func (c *LightClient) VerifyBlock(b *block.Block) error {
+	header, err := c.store.GetHeader(b.Index)
+	if err != nil {
+		return err
+	}
+	if header.Hash() != b.Hash() {
+		return fmt.Errorf("block %d is not in the verified chain", b.Index)
+	}
+	root, err := b.ComputeMerkleRoot()
+	if err != nil {
+		return err
+	}
+	if root != header.MerkleRoot {
+		return fmt.Errorf("merkle root mismatch in block %d", b.Index)
+	}
+	return nil
+}
+
+// VerifyTransaction downloads the block of the transaction and checks that it is in the verified chain
+func (c *LightClient) VerifyTransaction(txId string) (tx.ITransaction, error) {
+	heightResponse := c.Client.GetTransactionHeight(txId)
+	if heightResponse.HasError() {
+		return nil, heightResponse.GetError()
+	}
+	response := c.Client.GetBlockByIndex(uint32(heightResponse.Result))
+	if response.HasError() {
+		return nil, response.GetError()
+	}
+	b, err := block.NewBlockFromRPC(&response.Result)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.VerifyBlock(b); err != nil {
+		return nil, err
+	}
+	for _, t := range b.Tx {
+		if t.HashString() == strings.TrimPrefix(txId, "0x") {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("transaction %s is not in block %d", txId, b.Index)
+}
+
+// VerifyStateRoot checks that the state root is signed by the validators in the NextConsensus
+// of the verified header at the same index, mpt.VerifyProof can then be used with its root
+func (c *LightClient) VerifyStateRoot(sr *mpt.StateRoot) error {
+	header, err := c.store.GetHeader(sr.Index)
+	if err != nil {
+		return err
+	}
+	return sr.VerifyWitness(header.NextConsensus)
+}
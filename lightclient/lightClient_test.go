@@ -0,0 +1,275 @@
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/block"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/mpt"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/tx"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type validators struct {
+	pairs         []*keys.KeyPair
+	publicKeys    []*keys.PublicKey
+	nextConsensus helper.UInt160
+}
+
+func newValidators(t *testing.T) *validators {
+	v := &validators{pairs: make([]*keys.KeyPair, 4), publicKeys: make([]*keys.PublicKey, 4)}
+	for i := range v.pairs {
+		v.pairs[i], _ = keys.GenerateKeyPair()
+		v.publicKeys[i] = v.pairs[i].PublicKey
+	}
+	script, err := keys.CreateMultiSigRedeemScript(3, v.publicKeys...)
+	assert.Nil(t, err)
+	v.nextConsensus, _ = helper.BytesToScriptHash(script)
+	return v
+}
+
+func (v *validators) genesis() *block.BlockHeader {
+	return &block.BlockHeader{
+		Timestamp:     1468595301,
+		NextConsensus: v.nextConsensus,
+		Witness:       &tx.Witness{InvocationScript: []byte{}, VerificationScript: []byte{0x51}},
+	}
+}
+
+// extend appends count signed headers to chain, nonce makes a fork differ from the main chain
+func (v *validators) extend(chain []*block.BlockHeader, count int, nonce uint64) []*block.BlockHeader {
+	result := append([]*block.BlockHeader{}, chain...)
+	for i := 0; i < count; i++ {
+		prev := result[len(result)-1]
+		h := &block.BlockHeader{
+			PrevHash:      prev.Hash(),
+			Timestamp:     prev.Timestamp + 15,
+			Index:         prev.Index + 1,
+			ConsensusData: nonce,
+			NextConsensus: v.nextConsensus,
+		}
+		h.Witness, _ = tx.CreateMultiSignatureWitness(h.GetHashData(), v.pairs[:3], 3, v.publicKeys)
+		result = append(result, h)
+	}
+	return result
+}
+
+func toRpcHeader(h *block.BlockHeader) models.RpcBlockHeader {
+	return models.RpcBlockHeader{
+		Hash:              "0x" + h.HashString(),
+		PreviousBlockHash: h.PrevHash.String(),
+		MerkleRoot:        h.MerkleRoot.String(),
+		Time:              int(h.Timestamp),
+		Index:             int(h.Index),
+		Nonce:             fmt.Sprintf("%016x", h.ConsensusData),
+		NextConsensus:     helper.ScriptHashToAddress(h.NextConsensus),
+		Witness: models.RpcWitness{
+			Invocation:   helper.BytesToHex(h.Witness.InvocationScript),
+			Verification: helper.BytesToHex(h.Witness.VerificationScript),
+		},
+	}
+}
+
+// newNode mocks a node whose best chain is chain
+func newNode(chain []*block.BlockHeader) *rpc.RpcClientMock {
+	client := new(rpc.RpcClientMock)
+	client.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: len(chain)})
+	for _, h := range chain {
+		hash := "0x" + h.HashString()
+		client.On("GetBlockHash", h.Index).Return(rpc.GetBlockHashResponse{Result: hash})
+		client.On("GetBlockHeaderByHash", hash).Return(rpc.GetBlockHeaderResponse{Result: toRpcHeader(h)})
+	}
+	return client
+}
+
+func TestLightClient_Sync(t *testing.T) {
+	v := newValidators(t)
+	chain := v.extend([]*block.BlockHeader{v.genesis()}, 5, 0)
+
+	c, err := NewLightClient(newNode(chain), NewMemoryHeaderStore(), chain[0])
+	assert.Nil(t, err)
+	tip, err := c.Sync()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(5), tip.Index)
+	assert.Equal(t, chain[5].Hash(), tip.Hash())
+
+	h, err := c.GetHeaderByIndex(3)
+	assert.Nil(t, err)
+	assert.Equal(t, chain[3].Hash(), h.Hash())
+	h, err = c.GetHeaderByHash(chain[4].Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(4), h.Index)
+	_, err = c.GetHeaderByIndex(6)
+	assert.Equal(t, ErrNotFound, err)
+
+	// nothing new
+	tip, err = c.Sync()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(5), tip.Index)
+
+	// new blocks
+	chain = v.extend(chain, 2, 0)
+	c.Client = newNode(chain)
+	tip, err = c.Sync()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(7), tip.Index)
+}
+
+func TestLightClient_Sync_InvalidWitness(t *testing.T) {
+	v := newValidators(t)
+	chain := v.extend([]*block.BlockHeader{v.genesis()}, 3, 0)
+	// header 3 signed by other validators
+	other := newValidators(t)
+	chain = append(chain[:3], other.extend(chain[:3], 1, 0)[3])
+
+	c, _ := NewLightClient(newNode(chain), NewMemoryHeaderStore(), chain[0])
+	tip, err := c.Sync()
+	assert.NotNil(t, err)
+	assert.Equal(t, uint32(2), tip.Index)
+}
+
+func TestLightClient_Sync_Reorg(t *testing.T) {
+	v := newValidators(t)
+	chain := v.extend([]*block.BlockHeader{v.genesis()}, 5, 0)
+	c, _ := NewLightClient(newNode(chain), NewMemoryHeaderStore(), chain[0])
+	_, err := c.Sync()
+	assert.Nil(t, err)
+
+	var ancestor *block.BlockHeader
+	var removed []*block.BlockHeader
+	c.OnReorg = func(a *block.BlockHeader, r []*block.BlockHeader) {
+		ancestor, removed = a, r
+	}
+
+	// a fork from 3 with the same height is not followed
+	fork := v.extend(chain[:3], 3, 1)
+	c.Client = newNode(fork)
+	tip, err := c.Sync()
+	assert.True(t, errors.Is(err, ErrForkDetected))
+	assert.Equal(t, chain[5].Hash(), tip.Hash())
+	assert.Nil(t, ancestor)
+
+	// a longer fork is followed
+	fork = v.extend(fork, 1, 1)
+	c.Client = newNode(fork)
+	tip, err = c.Sync()
+	assert.Nil(t, err)
+	assert.Equal(t, fork[6].Hash(), tip.Hash())
+	assert.Equal(t, chain[2].Hash(), ancestor.Hash())
+	assert.Equal(t, 3, len(removed))
+	assert.Equal(t, chain[3].Hash(), removed[0].Hash())
+	_, err = c.GetHeaderByHash(chain[4].Hash())
+	assert.Equal(t, ErrNotFound, err)
+	h, _ := c.GetHeaderByIndex(4)
+	assert.Equal(t, fork[4].Hash(), h.Hash())
+}
+
+func TestLightClient_Sync_ReorgTooDeep(t *testing.T) {
+	v := newValidators(t)
+	chain := v.extend([]*block.BlockHeader{v.genesis()}, 5, 0)
+	c, _ := NewLightClient(newNode(chain), NewMemoryHeaderStore(), chain[0])
+	c.MaxReorgDepth = 2
+	_, _ = c.Sync()
+
+	fork := v.extend(chain[:2], 6, 1)
+	c.Client = newNode(fork)
+	tip, err := c.Sync()
+	assert.Equal(t, ErrReorgTooDeep, err)
+	assert.Equal(t, chain[5].Hash(), tip.Hash())
+}
+
+func TestLightClient_Sync_InvalidFork(t *testing.T) {
+	v := newValidators(t)
+	chain := v.extend([]*block.BlockHeader{v.genesis()}, 3, 0)
+	c, _ := NewLightClient(newNode(chain), NewMemoryHeaderStore(), chain[0])
+	_, _ = c.Sync()
+
+	other := newValidators(t)
+	fork := other.extend(chain[:2], 3, 1)
+	c.Client = newNode(fork)
+	tip, err := c.Sync()
+	assert.True(t, errors.Is(err, ErrForkDetected))
+	assert.Equal(t, chain[3].Hash(), tip.Hash())
+}
+
+func TestNewLightClient(t *testing.T) {
+	v := newValidators(t)
+	chain := v.extend([]*block.BlockHeader{v.genesis()}, 2, 0)
+	store := NewMemoryHeaderStore()
+	c, _ := NewLightClient(newNode(chain), store, chain[0])
+	_, _ = c.Sync()
+
+	// reopen the store
+	c, err := NewLightClient(newNode(chain), store, chain[0])
+	assert.Nil(t, err)
+	tip, _ := c.Tip()
+	assert.Equal(t, uint32(2), tip.Index)
+
+	// another trusted header
+	_, err = NewLightClient(newNode(chain), store, v.genesis())
+	assert.Nil(t, err) // same content, same hash
+	other := v.genesis()
+	other.Timestamp++
+	_, err = NewLightClient(newNode(chain), store, other)
+	assert.NotNil(t, err)
+}
+
+func TestLightClient_VerifyBlock(t *testing.T) {
+	v := newValidators(t)
+	genesis := v.genesis()
+	miner := &tx.MinerTransaction{Transaction: tx.NewTransaction(), Nonce: 1}
+	b := &block.Block{Tx: []tx.ITransaction{miner}}
+	b.PrevHash = genesis.Hash()
+	b.Timestamp = genesis.Timestamp + 15
+	b.Index = 1
+	b.NextConsensus = v.nextConsensus
+	assert.Nil(t, b.RebuildMerkleRoot())
+	b.Witness, _ = tx.CreateMultiSignatureWitness(b.GetHashData(), v.pairs[:3], 3, v.publicKeys)
+
+	chain := []*block.BlockHeader{genesis, &b.BlockHeader}
+	c, _ := NewLightClient(newNode(chain), NewMemoryHeaderStore(), genesis)
+	_, err := c.Sync()
+	assert.Nil(t, err)
+	assert.Nil(t, c.VerifyBlock(b))
+
+	// transactions changed
+	miner.Nonce = 2
+	assert.NotNil(t, c.VerifyBlock(b))
+
+	// not synced
+	b.Index = 2
+	assert.Equal(t, ErrNotFound, c.VerifyBlock(b))
+	client := new(rpc.RpcClientMock)
+	client.On("GetTransactionHeight", mock.Anything).Return(rpc.GetTransactionHeightResponse{
+		ErrorResponse: rpc.ErrorResponse{Error: rpc.RpcError{Code: -100, Message: "Unknown transaction"}},
+	})
+	c.Client = client
+	_, err = c.VerifyTransaction("0x" + miner.HashString())
+	assert.True(t, errors.Is(err, rpc.ErrUnknownItem))
+}
+
+func TestLightClient_VerifyStateRoot(t *testing.T) {
+	v := newValidators(t)
+	chain := v.extend([]*block.BlockHeader{v.genesis()}, 2, 0)
+	c, _ := NewLightClient(newNode(chain), NewMemoryHeaderStore(), chain[0])
+	_, _ = c.Sync()
+
+	sr := &mpt.StateRoot{
+		Index:     2,
+		PreHash:   "0x0000000000000000000000000000000000000000000000000000000000000000",
+		StateRoot: "0x34db5a993a95e0db79efe8220bf142e5952056bb59834fe3b91fc1611ed4385e",
+	}
+	witness, _ := tx.CreateMultiSignatureWitness(sr.GetHashData(), v.pairs[:3], 3, v.publicKeys)
+	sr.Witness.InvocationScript = helper.BytesToHex(witness.InvocationScript)
+	sr.Witness.VerificationScript = helper.BytesToHex(witness.VerificationScript)
+	assert.Nil(t, c.VerifyStateRoot(sr))
+
+	sr.Index = 3
+	assert.Equal(t, ErrNotFound, c.VerifyStateRoot(sr))
+}
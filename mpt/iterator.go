@@ -0,0 +1,198 @@
+package mpt
+
+import (
+	"bytes"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	nio "github.com/joeqian10/neo-gogogo/helper/io"
+)
+
+// groupSize the size of the groups in a serialized storage key
+const groupSize = 16
+
+//Iterator walks the key value pairs of a Trie in ascending order of the keys,
+//the hashNodes are resolved only when their keys are in the range
+//
+//	it := trie.NewIterator(prefix)
+//	for it.Next() {
+//		key, value := it.Key(), it.Value()
+//	}
+//	err := it.Err()
+type Iterator struct {
+	trie  *Trie
+	stack []iteratorFrame
+	start []byte // nibbles, inclusive
+	end   []byte // nibbles, exclusive, nil for no limit
+	key   []byte
+	value []byte
+	err   error
+}
+
+type iteratorFrame struct {
+	n    node
+	path []byte // nibbles from the root to n
+}
+
+//NewIterator iterate the keys starting with prefix
+func (t *Trie) NewIterator(prefix []byte) *Iterator {
+	start := helper.ToNibbles(prefix)
+	return t.newIterator(start, nextPrefix(start))
+}
+
+//NewRangeIterator iterate the keys from start inclusive to end exclusive, a nil end is no limit
+func (t *Trie) NewRangeIterator(start []byte, end []byte) *Iterator {
+	var e []byte
+	if end != nil {
+		e = helper.ToNibbles(end)
+	}
+	return t.newIterator(helper.ToNibbles(start), e)
+}
+
+func (t *Trie) newIterator(start []byte, end []byte) *Iterator {
+	return &Iterator{
+		trie:  t,
+		stack: []iteratorFrame{{n: t.root, path: []byte{}}},
+		start: start,
+		end:   end,
+	}
+}
+
+//Next move to the next pair, false at the end or on error
+func (it *Iterator) Next() bool {
+	it.key, it.value = nil, nil
+	for it.err == nil && len(it.stack) > 0 {
+		f := it.stack[len(it.stack)-1]
+		it.stack = it.stack[:len(it.stack)-1]
+		if isEmpty(f.n) {
+			continue
+		}
+		// all keys under f.path are not less than it, and the following frames are greater
+		if it.end != nil && bytes.Compare(f.path, it.end) >= 0 {
+			it.stack = nil
+			return false
+		}
+		// skip the subtrees before start without resolving them
+		m := len(f.path)
+		if len(it.start) < m {
+			m = len(it.start)
+		}
+		if bytes.Compare(f.path[:m], it.start[:m]) < 0 {
+			continue
+		}
+		switch n := f.n.(type) {
+		case hashNode:
+			nn, err := it.trie.resolve(n)
+			if err != nil {
+				it.err = err
+				return false
+			}
+			it.stack = append(it.stack, iteratorFrame{n: nn, path: f.path})
+		case valueNode:
+			if len(f.path)%2 != 0 || bytes.Compare(f.path, it.start) < 0 {
+				continue
+			}
+			it.key, it.value = fromNibbles(f.path), n
+			return true
+		case fullNode:
+			for i := 15; i >= 0; i-- {
+				it.stack = append(it.stack, iteratorFrame{n: n.children[i], path: concatNibbles(f.path, []byte{byte(i)})})
+			}
+			// the value of this path comes before the longer keys
+			it.stack = append(it.stack, iteratorFrame{n: n.children[16], path: f.path})
+		case shortNode:
+			it.stack = append(it.stack, iteratorFrame{n: n.next, path: concatNibbles(f.path, n.key)})
+		}
+	}
+	return false
+}
+
+//Key the key of the current pair
+func (it *Iterator) Key() []byte {
+	return it.key
+}
+
+//Value the value of the current pair
+func (it *Iterator) Value() []byte {
+	return it.value
+}
+
+//Err the error which stops the iteration, such as a node missing in the db
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+//StorageIterator walks the storage of a contract, the keys and values are
+//decoded from blockchain.Storagekey and blockchain.StorageItem
+type StorageIterator struct {
+	*Iterator
+	key   []byte
+	value []byte
+}
+
+//NewStorageIterator iterate the storage of the contract whose keys start with prefix
+func (t *Trie) NewStorageIterator(scriptHash helper.UInt160, prefix []byte) *StorageIterator {
+	return &StorageIterator{Iterator: t.NewIterator(storageSearchPrefix(scriptHash, prefix))}
+}
+
+//Next move to the next storage, false at the end or on error
+func (it *StorageIterator) Next() bool {
+	it.key, it.value = nil, nil
+	if !it.Iterator.Next() {
+		return false
+	}
+	_, key, err := resolveKey(it.Iterator.Key())
+	if err != nil {
+		it.err = err
+		return false
+	}
+	value, err := resolveValue(it.Iterator.Value())
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.key, it.value = key, value
+	return true
+}
+
+//Key the key of the current storage in the contract
+func (it *StorageIterator) Key() []byte {
+	return it.key
+}
+
+//Value the value of the current storage
+func (it *StorageIterator) Value() []byte {
+	return it.value
+}
+
+// storageSearchPrefix the prefix of the serialized storage keys starting with prefix,
+// the complete groups are followed by 0 and the last group is not padded
+func storageSearchPrefix(scriptHash helper.UInt160, prefix []byte) []byte {
+	buf := nio.NewBufBinaryWriter()
+	buf.WriteLE(scriptHash)
+	for len(prefix) >= groupSize {
+		buf.WriteLE(prefix[:groupSize])
+		buf.WriteLE(byte(0))
+		prefix = prefix[groupSize:]
+	}
+	buf.WriteLE(prefix)
+	return buf.Bytes()
+}
+
+// nextPrefix the smallest path greater than all the paths starting with prefix, nil if there is none
+func nextPrefix(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0x0f {
+			next := concatNibbles(prefix[:i], nil)
+			return append(next, prefix[i]+1)
+		}
+	}
+	return nil
+}
+
+func fromNibbles(path []byte) []byte {
+	r := make([]byte, len(path)/2)
+	for i := range r {
+		r[i] = path[i*2]<<4 | path[i*2+1]
+	}
+	return r
+}
@@ -0,0 +1,107 @@
+package mpt
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/blockchain"
+	"github.com/joeqian10/neo-gogogo/helper"
+	nio "github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrie_NewIterator(t *testing.T) {
+	keys := [][]byte{{0x01}, {0x01, 0x02}, {0x01, 0x03}, {0x02, 0x00}, {0x12, 0x34, 0x56}, {0x12, 0x35}, {0xff}}
+	db := NewMemoryDb()
+	trie, _ := NewTrie(nil, db)
+	// put in another order
+	for _, i := range []int{4, 0, 6, 2, 5, 1, 3} {
+		assert.Nil(t, trie.Put(keys[i], []byte{byte(i)}))
+	}
+	root, _ := trie.Commit()
+	trie, _ = NewTrie(root.Bytes(), db)
+
+	collect := func(it *Iterator) [][]byte {
+		var r [][]byte
+		for it.Next() {
+			r = append(r, it.Key())
+		}
+		assert.Nil(t, it.Err())
+		return r
+	}
+	assert.Equal(t, keys, collect(trie.NewIterator(nil)))
+	assert.Equal(t, keys[:3], collect(trie.NewIterator([]byte{0x01})))
+	assert.Equal(t, keys[4:5], collect(trie.NewIterator([]byte{0x12, 0x34})))
+	assert.Equal(t, keys[6:], collect(trie.NewIterator([]byte{0xff})))
+	assert.Nil(t, collect(trie.NewIterator([]byte{0x03})))
+	assert.Nil(t, collect(trie.NewIterator([]byte{0x12, 0x34, 0x56, 0x78})))
+
+	assert.Equal(t, keys[1:4], collect(trie.NewRangeIterator([]byte{0x01, 0x02}, []byte{0x12})))
+	assert.Equal(t, keys[3:], collect(trie.NewRangeIterator([]byte{0x01, 0x04}, nil)))
+	assert.Nil(t, collect(trie.NewRangeIterator([]byte{0x13}, []byte{0xfe})))
+
+	// values
+	it := trie.NewIterator([]byte{0x12})
+	assert.True(t, it.Next())
+	assert.Equal(t, []byte{0x04}, it.Value())
+	assert.True(t, it.Next())
+	assert.Equal(t, []byte{0x05}, it.Value())
+	assert.False(t, it.Next())
+}
+
+func TestTrie_NewStorageIterator(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("8adba7caee53e2f12f130b065411840cf7b4f9a5")
+	other, _ := helper.UInt160FromString("0000000000000000000000000000000000000001")
+	long := []byte("balance0123456789abcdef")
+	storages := [][]byte{[]byte("allowance"), []byte("balanceA"), []byte("balanceB"), long, []byte("total")}
+	trie, _ := NewTrie(nil, NewMemoryDb())
+	for _, sh := range []helper.UInt160{scriptHash, other} {
+		for _, k := range storages {
+			sKey, _ := nio.ToArray(&blockchain.Storagekey{ScriptHash: sh, Key: k})
+			item, _ := nio.ToArray(&blockchain.StorageItem{Value: append([]byte{sh.Bytes()[0]}, k...)})
+			assert.Nil(t, trie.Put(sKey, item))
+		}
+	}
+
+	var found [][]byte
+	it := trie.NewStorageIterator(scriptHash, []byte("balance"))
+	for it.Next() {
+		found = append(found, it.Key())
+		assert.Equal(t, append([]byte{scriptHash.Bytes()[0]}, it.Key()...), it.Value())
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, 3, len(found))
+	assert.Contains(t, found, long)
+
+	// a prefix longer than a group
+	it = trie.NewStorageIterator(scriptHash, long[:18])
+	assert.True(t, it.Next())
+	assert.Equal(t, long, it.Key())
+	assert.False(t, it.Next())
+
+	it = trie.NewStorageIterator(other, nil)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	assert.Equal(t, len(storages), count)
+}
+
+func TestTrie_NewIterator_ProofDb(t *testing.T) {
+	root, _ := helper.UInt256FromString("34db5a993a95e0db79efe8220bf142e5952056bb59834fe3b91fc1611ed4385e")
+	scriptHash, key, proofs, _ := ResolveProof(helper.HexToBytes(testProof))
+	trie, _ := NewTrie(root.Bytes(), NewProofDb(proofs))
+
+	// the nodes in the proof are enough for the key
+	it := trie.NewStorageIterator(scriptHash, key)
+	assert.True(t, it.Next())
+	assert.Equal(t, key, it.Key())
+	assert.Equal(t, "c071b504", helper.BytesToHex(it.Value()))
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+
+	// the other nodes are missing
+	it = trie.NewStorageIterator(scriptHash, nil)
+	for it.Next() {
+	}
+	assert.NotNil(t, it.Err())
+}
@@ -0,0 +1,40 @@
+package mpt
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+)
+
+//MemoryDb a writable db in memory
+type MemoryDb struct {
+	mu    sync.RWMutex
+	nodes map[string][]byte
+}
+
+//NewMemoryDb new instance of MemoryDb
+func NewMemoryDb() *MemoryDb {
+	return &MemoryDb{
+		nodes: make(map[string][]byte),
+	}
+}
+
+//Get for TrieDb
+func (md *MemoryDb) Get(key []byte) ([]byte, error) {
+	md.mu.RLock()
+	defer md.mu.RUnlock()
+	keystr := helper.BytesToHex(key)
+	if v, ok := md.nodes[keystr]; ok {
+		return v, nil
+	}
+	return nil, errors.New("cant find the value in MemoryDb, key=" + keystr)
+}
+
+//Put for TrieDb
+func (md *MemoryDb) Put(key []byte, value []byte) error {
+	md.mu.Lock()
+	defer md.mu.Unlock()
+	md.nodes[helper.BytesToHex(key)] = append([]byte{}, value...)
+	return nil
+}
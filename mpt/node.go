@@ -3,6 +3,7 @@ package mpt
 import (
 	"errors"
 
+	"github.com/joeqian10/neo-gogogo/crypto"
 	"github.com/joeqian10/neo-gogogo/helper/io"
 )
 
@@ -31,6 +32,49 @@ func decodeNode(data []byte) (node, error) {
 	return nil, errors.New("invalid node type to decode")
 }
 
+// encodeNode encodes n the same as the Neo 2 state root plugin, children are referenced by their hashes
+func encodeNode(n node) []byte {
+	buf := io.NewBufBinaryWriter()
+	switch n := n.(type) {
+	case fullNode:
+		buf.WriteLE(fullNodeType)
+		for _, child := range n.children {
+			buf.WriteVarBytes(nodeHash(child))
+		}
+	case shortNode:
+		buf.WriteLE(shortNodeType)
+		buf.WriteVarBytes(n.key)
+		buf.WriteVarBytes(nodeHash(n.next))
+	case hashNode:
+		buf.WriteLE(hashNodeType)
+		buf.WriteVarBytes(n)
+	case valueNode:
+		buf.WriteLE(valueNodeType)
+		buf.WriteVarBytes(n)
+	}
+	return buf.Bytes()
+}
+
+// nodeHash returns the hash referencing n, which is empty for an empty node
+func nodeHash(n node) hashNode {
+	if isEmpty(n) {
+		return hashNode{}
+	}
+	if h, ok := n.(hashNode); ok {
+		return h
+	}
+	return crypto.Hash256(encodeNode(n))
+}
+
+// isEmpty checks whether n is an absent child, which is decoded as an empty hashNode
+func isEmpty(n node) bool {
+	if n == nil {
+		return true
+	}
+	h, ok := n.(hashNode)
+	return ok && len(h) == 0
+}
+
 type fullNode struct {
 	children [17]node
 }
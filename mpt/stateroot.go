@@ -1,8 +1,12 @@
 package mpt
 
 import (
+	"fmt"
+
+	"github.com/joeqian10/neo-gogogo/crypto"
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
 )
 
 //StateRoot truct of StateRoot message
@@ -52,3 +56,65 @@ func (sr *StateRoot) SerializeUnsigned(bw *io.BinaryWriter) {
 	stateRoot, _ := helper.UInt256FromString(sr.StateRoot)
 	bw.WriteLE(stateRoot)
 }
+
+//GetHashData the unsigned data which is signed by the validators
+func (sr *StateRoot) GetHashData() []byte {
+	buf := io.NewBufBinaryWriter()
+	sr.SerializeUnsigned(buf.BinaryWriter)
+	return buf.Bytes()
+}
+
+//Hash hash of the unsigned data
+func (sr *StateRoot) Hash() helper.UInt256 {
+	hash, _ := helper.UInt256FromBytes(crypto.Hash256(sr.GetHashData()))
+	return hash
+}
+
+//VerifyWitness check that the StateRoot is signed by the validators whose
+//multi-signature script hash is nextConsensus, which is the NextConsensus
+//of the block header at sr.Index
+func (sr *StateRoot) VerifyWitness(nextConsensus helper.UInt160) error {
+	verification := helper.HexToBytes(sr.Witness.VerificationScript)
+	scriptHash, err := helper.BytesToScriptHash(verification)
+	if err != nil {
+		return err
+	}
+	if scriptHash != nextConsensus {
+		return fmt.Errorf("witness script hash mismatch: expected %s got %s", nextConsensus.String(), scriptHash.String())
+	}
+	m, validators, err := keys.ParseMultiSigRedeemScript(verification)
+	if err != nil {
+		return err
+	}
+	invocation := helper.HexToBytes(sr.Witness.InvocationScript)
+	if len(invocation)%65 != 0 || len(invocation)/65 < m {
+		return fmt.Errorf("not enough signatures in the witness of state root %d", sr.Index)
+	}
+	signatures := make([][]byte, len(invocation)/65)
+	for i := range signatures {
+		if invocation[i*65] != 64 {
+			return fmt.Errorf("invalid invocation script of state root %d", sr.Index)
+		}
+		signatures[i] = invocation[i*65+1 : i*65+65]
+	}
+	if !keys.VerifyMultiSig(sr.GetHashData(), signatures, validators) {
+		return fmt.Errorf("invalid witness of state root %d", sr.Index)
+	}
+	return nil
+}
+
+//VerifyWitnessByValidators check that the StateRoot is signed by the consensus
+//multi-signature of validators, which requires n - (n - 1) / 3 signatures
+func (sr *StateRoot) VerifyWitnessByValidators(validators []*keys.PublicKey) error {
+	n := len(validators)
+	// copy since the keys are sorted in place
+	script, err := keys.CreateMultiSigRedeemScript(n-(n-1)/3, append([]*keys.PublicKey{}, validators...)...)
+	if err != nil {
+		return err
+	}
+	nextConsensus, err := helper.BytesToScriptHash(script)
+	if err != nil {
+		return err
+	}
+	return sr.VerifyWitness(nextConsensus)
+}
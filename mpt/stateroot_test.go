@@ -6,11 +6,15 @@ import (
 
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+	"github.com/stretchr/testify/assert"
 )
 
+const testStateRoot = `{"version":0,"index":409,"prehash":"0x5094cbeb0642557262cb3ea61d57a1b47119eafce9725ec4ac79398c30b3c7a0","stateroot":"0x0000000000000000000000000000000000000000000000000000000000000000","witness":{"invocation":"40cd8d09b0f8bec0c342ebfc1024d0805297b439fdff3a2eccad01c1bd1ab2e8d4671bb1780bb5c8199092e75dfde567db19117f87494012491751a2cfaca42179409c54a8a1d7ae58b8090e2526c548294d37c909c671e64b2a9c5acf83a4d0605242e6f6ffcc3fe240b94c5446cc588256daa1f0bf065d27b881a43404c9d0be8840c9ba349daf13dae1ff77adf5ca7d3a0a913ba534ce3fc5e645a950fb628b61e96c470e3efba66fb4f3e7fc5aef7396949eda98bd080cff14dec42f7cb791226a","verification":"5321030f59a5482a4e42a2e5a848608dac4e84a698e567e2860e0ca5f23fc9e818d37c21032e78261370d4d62cf4c13584ca90f46c5565117b5b97544312f2e7b7c36b9eba21026e271722c21c482f0ac74dd932e61cdc2a2dd889633a2c5d8ecef43f2769f51e2103d55bfbcd493d06ab49c09cde0cea5d9ba890d81331a2fcd6f68d329932d0398f54ae"}}`
+
 func TestStateRootSerialize(t *testing.T) {
 	var sr StateRoot
-	data := []byte(`{"version":0,"index":409,"prehash":"0x5094cbeb0642557262cb3ea61d57a1b47119eafce9725ec4ac79398c30b3c7a0","stateroot":"0x0000000000000000000000000000000000000000000000000000000000000000","witness":{"invocation":"40cd8d09b0f8bec0c342ebfc1024d0805297b439fdff3a2eccad01c1bd1ab2e8d4671bb1780bb5c8199092e75dfde567db19117f87494012491751a2cfaca42179409c54a8a1d7ae58b8090e2526c548294d37c909c671e64b2a9c5acf83a4d0605242e6f6ffcc3fe240b94c5446cc588256daa1f0bf065d27b881a43404c9d0be8840c9ba349daf13dae1ff77adf5ca7d3a0a913ba534ce3fc5e645a950fb628b61e96c470e3efba66fb4f3e7fc5aef7396949eda98bd080cff14dec42f7cb791226a","verification":"5321030f59a5482a4e42a2e5a848608dac4e84a698e567e2860e0ca5f23fc9e818d37c21032e78261370d4d62cf4c13584ca90f46c5565117b5b97544312f2e7b7c36b9eba21026e271722c21c482f0ac74dd932e61cdc2a2dd889633a2c5d8ecef43f2769f51e2103d55bfbcd493d06ab49c09cde0cea5d9ba890d81331a2fcd6f68d329932d0398f54ae"}}`)
+	data := []byte(testStateRoot)
 	err := json.Unmarshal(data, &sr)
 	if err != nil {
 		t.Error(err)
@@ -25,3 +29,29 @@ func TestStateRootSerialize(t *testing.T) {
 		t.Error("serialize failed")
 	}
 }
+
+func TestStateRoot_VerifyWitness(t *testing.T) {
+	var sr StateRoot
+	_ = json.Unmarshal([]byte(testStateRoot), &sr)
+	verification := helper.HexToBytes(sr.Witness.VerificationScript)
+	nextConsensus, _ := helper.BytesToScriptHash(verification)
+	assert.Nil(t, sr.VerifyWitness(nextConsensus))
+
+	_, validators, _ := keys.ParseMultiSigRedeemScript(verification)
+	// the order of the keys does not matter
+	validators[0], validators[3] = validators[3], validators[0]
+	assert.Nil(t, sr.VerifyWitnessByValidators(validators))
+
+	// other validators
+	assert.NotNil(t, sr.VerifyWitnessByValidators(validators[:3]))
+	assert.NotNil(t, sr.VerifyWitness(helper.UInt160{}))
+
+	// changed root
+	sr.StateRoot = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	assert.NotNil(t, sr.VerifyWitness(nextConsensus))
+	sr.StateRoot = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+	// not enough signatures
+	sr.Witness.InvocationScript = sr.Witness.InvocationScript[:130*2]
+	assert.NotNil(t, sr.VerifyWitness(nextConsensus))
+}
@@ -10,13 +10,17 @@ import (
 	nio "github.com/joeqian10/neo-gogogo/helper/io"
 )
 
+//ErrNotFound the key is not in the trie
+var ErrNotFound = errors.New("trie cant find the path")
+
 //Trie mpt tree
 type Trie struct {
 	db   *trieDb
 	root node
 }
 
-//NewTrie new a trie instance
+//NewTrie new a trie instance, an empty or zero root is an empty trie.
+//The db must be an IKVDb to Commit the changes.
 func NewTrie(root []byte, db IKVReadOnlyDb) (*Trie, error) {
 	if db == nil {
 		return nil, errors.New("failed initialize Trie, invalid db")
@@ -24,6 +28,9 @@ func NewTrie(root []byte, db IKVReadOnlyDb) (*Trie, error) {
 	t := &Trie{
 		db: newTrieDb(db),
 	}
+	if len(root) == 0 || bytes.Equal(root, make([]byte, len(root))) {
+		return t, nil
+	}
 	r, err := t.resolve(hashNode(root))
 	if err != nil {
 		return nil, err
@@ -53,7 +60,7 @@ func (t *Trie) get(n node, path []byte) (node, error) {
 		if len(path) == 0 {
 			return n, nil
 		}
-		return n, errors.New("trie cant find the path")
+		return n, ErrNotFound
 	case fullNode:
 		f := n.(fullNode)
 		if len(path) == 0 {
@@ -63,26 +70,216 @@ func (t *Trie) get(n node, path []byte) (node, error) {
 	case shortNode:
 		s := n.(shortNode)
 		if !bytes.HasPrefix(path, s.key) {
-			return nil, errors.New("trie cant find the path")
+			return nil, ErrNotFound
 		}
 		return t.get(s.next, bytes.TrimPrefix(path, s.key))
 	case hashNode:
+		if isEmpty(n) {
+			return nil, ErrNotFound
+		}
 		nn, err := t.resolve(n.(hashNode))
 		if err != nil {
 			return nil, err
 		}
 		return t.get(nn, path)
 	}
-	return nil, errors.New("trie cant find the path")
+	return nil, ErrNotFound
+}
+
+//Put inserts or updates the value of key, a contract storage is put with
+//the serialized blockchain.Storagekey and blockchain.StorageItem
+func (t *Trie) Put(key []byte, value []byte) error {
+	if len(key) == 0 {
+		return errors.New("trie cant put an empty key")
+	}
+	n, err := t.put(t.root, helper.ToNibbles(key), valueNode(append([]byte{}, value...)))
+	if err != nil {
+		return err
+	}
+	t.root = n
+	return nil
+}
+
+func (t *Trie) put(n node, path []byte, val node) (node, error) {
+	if isEmpty(n) {
+		return newShortNode(path, val), nil
+	}
+	switch n := n.(type) {
+	case valueNode:
+		if len(path) == 0 {
+			return val, nil
+		}
+		// the value of a shorter key is kept in the value child of a fullNode
+		f := fullNode{}
+		f.children[16] = n
+		return t.put(f, path, val)
+	case fullNode:
+		var err error
+		if len(path) == 0 {
+			n.children[16], err = t.put(n.children[16], path, val)
+		} else {
+			n.children[path[0]], err = t.put(n.children[path[0]], path[1:], val)
+		}
+		return n, err
+	case shortNode:
+		if bytes.HasPrefix(path, n.key) {
+			next, err := t.put(n.next, path[len(n.key):], val)
+			if err != nil {
+				return nil, err
+			}
+			return shortNode{key: n.key, next: next}, nil
+		}
+		prefix := commonPrefix(n.key, path)
+		keyRemain := n.key[len(prefix):]
+		pathRemain := path[len(prefix):]
+		f := fullNode{}
+		f.children[keyRemain[0]] = newShortNode(keyRemain[1:], n.next)
+		if len(pathRemain) == 0 {
+			f.children[16] = val
+		} else {
+			f.children[pathRemain[0]] = newShortNode(pathRemain[1:], val)
+		}
+		if len(prefix) == 0 {
+			return f, nil
+		}
+		return shortNode{key: prefix, next: f}, nil
+	case hashNode:
+		nn, err := t.resolve(n)
+		if err != nil {
+			return nil, err
+		}
+		return t.put(nn, path, val)
+	}
+	return nil, errors.New("invalid node type to put")
+}
+
+//Delete removes key from the trie, ErrNotFound if it is absent
+func (t *Trie) Delete(key []byte) error {
+	n, err := t.delete(t.root, helper.ToNibbles(key))
+	if err != nil {
+		return err
+	}
+	t.root = n
+	return nil
+}
+
+func (t *Trie) delete(n node, path []byte) (node, error) {
+	if isEmpty(n) {
+		return nil, ErrNotFound
+	}
+	switch n := n.(type) {
+	case valueNode:
+		if len(path) != 0 {
+			return nil, ErrNotFound
+		}
+		return nil, nil
+	case fullNode:
+		var err error
+		if len(path) == 0 {
+			n.children[16], err = t.delete(n.children[16], path)
+		} else {
+			n.children[path[0]], err = t.delete(n.children[path[0]], path[1:])
+		}
+		if err != nil {
+			return nil, err
+		}
+		// a fullNode with a single child is reduced
+		last, count := 0, 0
+		for i, child := range n.children {
+			if !isEmpty(child) {
+				last = i
+				count++
+			}
+		}
+		if count > 1 {
+			return n, nil
+		}
+		if count == 0 {
+			return nil, nil
+		}
+		child := n.children[last]
+		if last == 16 {
+			return child, nil
+		}
+		if h, ok := child.(hashNode); ok {
+			if child, err = t.resolve(h); err != nil {
+				return nil, err
+			}
+		}
+		return newShortNode([]byte{byte(last)}, child), nil
+	case shortNode:
+		if !bytes.HasPrefix(path, n.key) {
+			return nil, ErrNotFound
+		}
+		next, err := t.delete(n.next, path[len(n.key):])
+		if err != nil {
+			return nil, err
+		}
+		if isEmpty(next) {
+			return nil, nil
+		}
+		return newShortNode(n.key, next), nil
+	case hashNode:
+		nn, err := t.resolve(n)
+		if err != nil {
+			return nil, err
+		}
+		return t.delete(nn, path)
+	}
+	return nil, errors.New("invalid node type to delete")
+}
+
+//RootHash the hash of the root node including the changes not committed, zero for an empty trie
+func (t *Trie) RootHash() helper.UInt256 {
+	hash := nodeHash(t.root)
+	if len(hash) == 0 {
+		return helper.UInt256{}
+	}
+	root, _ := helper.UInt256FromBytes(hash)
+	return root
+}
+
+//Commit stores the changed nodes into the db, and returns the new root hash
+func (t *Trie) Commit() (helper.UInt256, error) {
+	hash, err := t.db.commit(t.root)
+	if err != nil {
+		return helper.UInt256{}, err
+	}
+	if len(hash) == 0 {
+		t.root = nil
+		return helper.UInt256{}, nil
+	}
+	t.root = hash
+	return helper.UInt256FromBytes(hash)
+}
+
+// newShortNode returns next if key is empty, and merges the keys if next is a shortNode
+func newShortNode(key []byte, next node) node {
+	if len(key) == 0 {
+		return next
+	}
+	if s, ok := next.(shortNode); ok {
+		return shortNode{key: concatNibbles(key, s.key), next: s.next}
+	}
+	return shortNode{key: concatNibbles(key, nil), next: next}
+}
+
+func concatNibbles(a []byte, b []byte) []byte {
+	r := make([]byte, 0, len(a)+len(b))
+	return append(append(r, a...), b...)
+}
+
+func commonPrefix(a []byte, b []byte) []byte {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return a[:i]
 }
 
 //VerifyProof directly verify proof
 func VerifyProof(root []byte, scriptHash helper.UInt160, key []byte, proof [][]byte) ([]byte, error) {
-	sKey := blockchain.Storagekey{
-		ScriptHash: scriptHash,
-		Key:        key,
-	}
-	vkey, err := nio.ToArray(&sKey)
+	vkey, err := storageKey(scriptHash, key)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +295,94 @@ func VerifyProof(root []byte, scriptHash helper.UInt160, key []byte, proof [][]b
 	return resolveValue(value)
 }
 
+//VerifyExclusionProof verify that the key is absent under root, the proof is the
+//path to the node where the key diverges, which is produced by GetProof
+func VerifyExclusionProof(root []byte, scriptHash helper.UInt160, key []byte, proof [][]byte) error {
+	vkey, err := storageKey(scriptHash, key)
+	if err != nil {
+		return err
+	}
+	trie, err := NewTrie(root, NewProofDb(proof))
+	if err != nil {
+		return err
+	}
+	_, err = trie.Get(vkey)
+	if err == nil {
+		return errors.New("key exists in the trie")
+	}
+	if err != ErrNotFound {
+		return err // a node of the path is missing
+	}
+	return nil
+}
+
+//GetProof get the encoded nodes from the root to the value of key, which is
+//checked by VerifyProof. If key is absent, the nodes end where it diverges,
+//which is checked by VerifyExclusionProof.
+func (t *Trie) GetProof(key []byte) ([][]byte, error) {
+	var proof [][]byte
+	n, path := t.root, helper.ToNibbles(key)
+	for !isEmpty(n) {
+		if h, ok := n.(hashNode); ok {
+			nn, err := t.resolve(h)
+			if err != nil {
+				return nil, err
+			}
+			n = nn
+		}
+		proof = append(proof, encodeNode(n))
+		switch nn := n.(type) {
+		case valueNode:
+			return proof, nil
+		case fullNode:
+			if len(path) == 0 {
+				n = nn.children[16]
+			} else {
+				n, path = nn.children[path[0]], path[1:]
+			}
+		case shortNode:
+			if !bytes.HasPrefix(path, nn.key) {
+				return proof, nil
+			}
+			n, path = nn.next, path[len(nn.key):]
+		default:
+			return nil, errors.New("invalid node type to prove")
+		}
+	}
+	return proof, nil
+}
+
+//GetStorageProof get the proof of a contract storage in the format of the
+//getproof rpc method, which is read by ResolveProof
+func (t *Trie) GetStorageProof(scriptHash helper.UInt160, key []byte) ([]byte, error) {
+	vkey, err := storageKey(scriptHash, key)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := t.GetProof(vkey)
+	if err != nil {
+		return nil, err
+	}
+	buf := nio.NewBufBinaryWriter()
+	buf.WriteVarBytes(vkey)
+	buf.WriteVarUint(uint64(len(proof)))
+	for _, p := range proof {
+		buf.WriteVarBytes(p)
+	}
+	if buf.Err != nil {
+		return nil, buf.Err
+	}
+	return buf.Bytes(), nil
+}
+
+func storageKey(scriptHash helper.UInt160, key []byte) ([]byte, error) {
+	sKey := blockchain.Storagekey{
+		ScriptHash: scriptHash,
+		Key:        key,
+	}
+	return nio.ToArray(&sKey)
+}
+
 //ResolveProof get key and proofs from proofdata
 func ResolveProof(proofBytes []byte) (scriptHash helper.UInt160, key []byte, proof [][]byte, err error) {
 	buffer := bytes.NewBuffer(proofBytes)
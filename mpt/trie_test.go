@@ -3,11 +3,17 @@ package mpt
 import (
 	"testing"
 
+	"github.com/joeqian10/neo-gogogo/blockchain"
+	"github.com/joeqian10/neo-gogogo/crypto"
 	"github.com/joeqian10/neo-gogogo/helper"
+	nio "github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/stretchr/testify/assert"
 )
 
+const testProof = "36a5f9b4f70c841154060b132ff1e253eecaa7db8a61737365740034c1e9f7413e8a5eb3c500ffd06acd41644d5d5a96000000000000060772000000000000000000000020dbe90d0674546fd0e6dc879013ab743b5f171cc1f4e97caab98be714bb7f125400208837fe543b1bfcd58d480ea9988cb7acf58c5f4cf0518a987f70924320ceab390020ff409ca133d8a3a5ea8b72d5b82214d6190d3dd44d19d67062681af2b4b8302000004b0128050f090b040f07000c0804010105040006000b0103020f0f010e0205030e0e0c0a0a070d0b080a06202da36d644929144a9869d0ffb581c645f4d29b7b38a068ca1cc3bbd34b0771e252000020a41c8cc1e18bcbe0f237fa00544455ab92d5a86bc8fa6cd0f0ba294e9f02202d002002ad9e7adebf3057801f6918a97efd49741949f8a538b58b4fae31700b976e7d000000000000000000000000002d010a0703070306050704000020afb2d68b4ae87095c68d113f42234c3e36e15597ec1c11ecce4261f3a9169b0a5200207c00cb1580a95b55ecf7e82829997ebf4176cf95df65712a0b14b37eb13c1c5f0000207e9c938de1d3f1f95753c0867e3326bdb16b2caccefb6f490810bf13ccc7440e000000000000000000000000005a0137040c010e090f070401030e080a050e0b030c0500000f0f0d00060a0c0d04010604040d050d050a090600000000000000000000000000062046a12d0bfc2f3f1d9e18a51f55d32ba4855578c1954d277180addb5b69210c970903070004c071b50400"
+
 func TestVerifyProof(t *testing.T) {
-	proofStr := "36a5f9b4f70c841154060b132ff1e253eecaa7db8a61737365740034c1e9f7413e8a5eb3c500ffd06acd41644d5d5a96000000000000060772000000000000000000000020dbe90d0674546fd0e6dc879013ab743b5f171cc1f4e97caab98be714bb7f125400208837fe543b1bfcd58d480ea9988cb7acf58c5f4cf0518a987f70924320ceab390020ff409ca133d8a3a5ea8b72d5b82214d6190d3dd44d19d67062681af2b4b8302000004b0128050f090b040f07000c0804010105040006000b0103020f0f010e0205030e0e0c0a0a070d0b080a06202da36d644929144a9869d0ffb581c645f4d29b7b38a068ca1cc3bbd34b0771e252000020a41c8cc1e18bcbe0f237fa00544455ab92d5a86bc8fa6cd0f0ba294e9f02202d002002ad9e7adebf3057801f6918a97efd49741949f8a538b58b4fae31700b976e7d000000000000000000000000002d010a0703070306050704000020afb2d68b4ae87095c68d113f42234c3e36e15597ec1c11ecce4261f3a9169b0a5200207c00cb1580a95b55ecf7e82829997ebf4176cf95df65712a0b14b37eb13c1c5f0000207e9c938de1d3f1f95753c0867e3326bdb16b2caccefb6f490810bf13ccc7440e000000000000000000000000005a0137040c010e090f070401030e080a050e0b030c0500000f0f0d00060a0c0d04010604040d050d050a090600000000000000000000000000062046a12d0bfc2f3f1d9e18a51f55d32ba4855578c1954d277180addb5b69210c970903070004c071b50400"
+	proofStr := testProof
 	proofdata := helper.HexToBytes(proofStr)
 
 	root, _ := helper.UInt256FromString("34db5a993a95e0db79efe8220bf142e5952056bb59834fe3b91fc1611ed4385e")
@@ -29,3 +35,155 @@ func TestVerifyProof(t *testing.T) {
 		t.Error("wrong nep5")
 	}
 }
+
+func TestTrie_PutExisting(t *testing.T) {
+	proofdata := helper.HexToBytes(testProof)
+	root, _ := helper.UInt256FromString("34db5a993a95e0db79efe8220bf142e5952056bb59834fe3b91fc1611ed4385e")
+	scriptHash, key, proofs, _ := ResolveProof(proofdata)
+
+	// the nodes are encoded the same as the state root plugin
+	db := NewMemoryDb()
+	for _, p := range proofs {
+		n, err := decodeNode(p)
+		assert.Nil(t, err)
+		assert.Equal(t, p, encodeNode(n))
+		assert.Nil(t, db.Put(crypto.Hash256(p), p))
+	}
+
+	trie, err := NewTrie(root.Bytes(), db)
+	assert.Nil(t, err)
+	sKey, _ := nio.ToArray(&blockchain.Storagekey{ScriptHash: scriptHash, Key: key})
+	value, err := trie.Get(sKey)
+	assert.Nil(t, err)
+
+	// put the same value, only the nodes on the path are resolved
+	assert.Nil(t, trie.Put(sKey, value))
+	assert.Equal(t, root, trie.RootHash())
+
+	// change and restore
+	assert.Nil(t, trie.Put(sKey, []byte{0x00, 0x01, 0x01, 0x00}))
+	assert.NotEqual(t, root, trie.RootHash())
+	changed, err := trie.Commit()
+	assert.Nil(t, err)
+	assert.Equal(t, changed, trie.RootHash())
+	assert.Nil(t, trie.Put(sKey, value))
+	assert.Equal(t, root, trie.RootHash())
+
+	// insert and delete another key
+	other := append([]byte{}, sKey...)
+	other[len(other)-2] ^= 0xff
+	assert.Nil(t, trie.Put(other, []byte{0x01}))
+	assert.NotEqual(t, root, trie.RootHash())
+	assert.Nil(t, trie.Delete(other))
+	assert.Equal(t, root, trie.RootHash())
+	assert.Equal(t, ErrNotFound, trie.Delete(other))
+}
+
+func TestTrie_PutDelete(t *testing.T) {
+	keys := [][]byte{{0x01}, {0x01, 0x02}, {0x01, 0x03}, {0x02, 0x00}, {0x12, 0x34, 0x56}, {0x12, 0x35}, {0xff}}
+	db := NewMemoryDb()
+	trie, err := NewTrie(nil, db)
+	assert.Nil(t, err)
+	assert.Equal(t, helper.UInt256{}, trie.RootHash())
+	for i, k := range keys {
+		assert.Nil(t, trie.Put(k, []byte{byte(i)}))
+	}
+	root, err := trie.Commit()
+	assert.Nil(t, err)
+
+	// the root does not depend on the order
+	reversed, _ := NewTrie(nil, NewMemoryDb())
+	for i := len(keys) - 1; i >= 0; i-- {
+		assert.Nil(t, reversed.Put(keys[i], []byte{byte(i)}))
+	}
+	assert.Equal(t, root, reversed.RootHash())
+
+	// reopen from the db
+	trie, err = NewTrie(root.Bytes(), db)
+	assert.Nil(t, err)
+	for i, k := range keys {
+		v, err := trie.Get(k)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{byte(i)}, v)
+	}
+	_, err = trie.Get([]byte{0x12, 0x34})
+	assert.Equal(t, ErrNotFound, err)
+
+	// delete in another order ends with an empty trie
+	for _, i := range []int{3, 0, 6, 4, 1, 5, 2} {
+		assert.Nil(t, trie.Delete(keys[i]))
+		_, err = trie.Get(keys[i])
+		assert.NotNil(t, err)
+		partial, _ := NewTrie(nil, NewMemoryDb())
+		for j, k := range keys {
+			if _, err := trie.Get(k); err == nil {
+				_ = partial.Put(k, []byte{byte(j)})
+			}
+		}
+		assert.Equal(t, partial.RootHash(), trie.RootHash())
+	}
+	assert.Equal(t, helper.UInt256{}, trie.RootHash())
+
+	// read only db
+	trie, _ = NewTrie(nil, NewProofDb(nil))
+	_ = trie.Put(keys[0], []byte{0x01})
+	_, err = trie.Commit()
+	assert.NotNil(t, err)
+}
+
+func TestTrie_GetStorageProof(t *testing.T) {
+	proofdata := helper.HexToBytes(testProof)
+	root, _ := helper.UInt256FromString("34db5a993a95e0db79efe8220bf142e5952056bb59834fe3b91fc1611ed4385e")
+	scriptHash, key, proofs, _ := ResolveProof(proofdata)
+
+	trie, err := NewTrie(root.Bytes(), NewProofDb(proofs))
+	assert.Nil(t, err)
+	data, err := trie.GetStorageProof(scriptHash, key)
+	assert.Nil(t, err)
+	assert.Equal(t, proofdata, data)
+
+	// another key diverges from the path in the proof
+	data, err = trie.GetStorageProof(scriptHash, []byte{0x01})
+	assert.Nil(t, err)
+	_, _, exclusion, _ := ResolveProof(data)
+	assert.Nil(t, VerifyExclusionProof(root.Bytes(), scriptHash, []byte{0x01}, exclusion))
+}
+
+func TestVerifyExclusionProof(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("8adba7caee53e2f12f130b065411840cf7b4f9a5")
+	trie, _ := NewTrie(nil, NewMemoryDb())
+	for _, k := range []string{"01", "0102", "0103", "1234", "ff"} {
+		sKey, _ := nio.ToArray(&blockchain.Storagekey{ScriptHash: scriptHash, Key: helper.HexToBytes(k)})
+		value, _ := nio.ToArray(&blockchain.StorageItem{Value: helper.HexToBytes(k)})
+		assert.Nil(t, trie.Put(sKey, value))
+	}
+	root, err := trie.Commit()
+	assert.Nil(t, err)
+
+	// present
+	sKey, _ := nio.ToArray(&blockchain.Storagekey{ScriptHash: scriptHash, Key: []byte{0x01, 0x02}})
+	proof, err := trie.GetProof(sKey)
+	assert.Nil(t, err)
+	value, err := VerifyProof(root.Bytes(), scriptHash, []byte{0x01, 0x02}, proof)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x01, 0x02}, value)
+	assert.NotNil(t, VerifyExclusionProof(root.Bytes(), scriptHash, []byte{0x01, 0x02}, proof))
+
+	// absent
+	for _, k := range [][]byte{{0x01, 0x04}, {0x12}, {0x02}} {
+		sKey, _ = nio.ToArray(&blockchain.Storagekey{ScriptHash: scriptHash, Key: k})
+		proof, err = trie.GetProof(sKey)
+		assert.Nil(t, err)
+		assert.Nil(t, VerifyExclusionProof(root.Bytes(), scriptHash, k, proof))
+		_, err = VerifyProof(root.Bytes(), scriptHash, k, proof)
+		assert.NotNil(t, err)
+		// a missing node does not prove the absence
+		assert.NotNil(t, VerifyExclusionProof(root.Bytes(), scriptHash, k, proof[:len(proof)-1]))
+	}
+
+	// another contract
+	other, _ := helper.UInt160FromString("0000000000000000000000000000000000000001")
+	sKey, _ = nio.ToArray(&blockchain.Storagekey{ScriptHash: other, Key: []byte{0x01}})
+	proof, _ = trie.GetProof(sKey)
+	assert.Nil(t, VerifyExclusionProof(root.Bytes(), other, []byte{0x01}, proof))
+}
@@ -1,10 +1,22 @@
 package mpt
 
+import (
+	"errors"
+
+	"github.com/joeqian10/neo-gogogo/crypto"
+)
+
 //IKVReadOnlyDb to store data
 type IKVReadOnlyDb interface {
 	Get(key []byte) ([]byte, error)
 }
 
+//IKVDb writable db to commit a Trie, nodes are stored with their hashes as keys
+type IKVDb interface {
+	IKVReadOnlyDb
+	Put(key []byte, value []byte) error
+}
+
 type trieDb struct {
 	db IKVReadOnlyDb
 }
@@ -23,3 +35,32 @@ func (t *trieDb) node(hash hashNode) (node, error) {
 	node, err := decodeNode(data)
 	return node, err
 }
+
+// commit stores n and its resolved descendants, and returns the hash of n
+func (t *trieDb) commit(n node) (hashNode, error) {
+	db, ok := t.db.(IKVDb)
+	if !ok {
+		return nil, errors.New("failed commit Trie, db is read only")
+	}
+	return t.store(db, n)
+}
+
+func (t *trieDb) store(db IKVDb, n node) (hashNode, error) {
+	switch n := n.(type) {
+	case fullNode:
+		for _, child := range n.children {
+			if _, err := t.store(db, child); err != nil {
+				return nil, err
+			}
+		}
+	case shortNode:
+		if _, err := t.store(db, n.next); err != nil {
+			return nil, err
+		}
+	case hashNode, nil:
+		return nodeHash(n), nil
+	}
+	data := encodeNode(n)
+	hash := hashNode(crypto.Hash256(data))
+	return hash, db.Put(hash, data)
+}
@@ -0,0 +1,185 @@
+package nep5
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
+)
+
+const (
+	// DefaultMaxCallsPerScript is the max number of the balanceOf calls in one script
+	DefaultMaxCallsPerScript = 50
+	// DefaultMaxScriptSize is the max size of the script of an InvocationTransaction
+	DefaultMaxScriptSize = 65536
+)
+
+// DefaultMaxGasPerScript is the GAS given to invokescript by a node with the default settings
+var DefaultMaxGasPerScript = helper.Fixed8FromInt64(10)
+
+// BalanceQuery is an address whose balance of a token is queried, it is the key of the balances returned
+type BalanceQuery struct {
+	AssetHash helper.UInt160
+	Address   helper.UInt160
+}
+
+// BatchBalanceOptions limit the scripts run by BatchBalanceOf, the default is used for a zero value
+type BatchBalanceOptions struct {
+	MaxCallsPerScript int
+	MaxScriptSize     int
+	// MaxGasPerScript is the GAS limit of invokescript of the node
+	MaxGasPerScript helper.Fixed8
+}
+
+// BatchBalanceOf gets the balances of many addresses of many tokens by running the balanceOf calls together in a
+// few invokescript requests. The queries are split into scripts within the limits of options, the number of the
+// calls per script is lowered once the GAS consumed by a call is known. A script which faults, such as when it runs
+// out of GAS, is split in two and run again, and an error is returned if a single call faults.
+// The balances are in the smallest unit of each token.
+func BatchBalanceOf(client rpc.IRpcClient, queries []BalanceQuery, options *BatchBalanceOptions) (map[BalanceQuery]*big.Int, error) {
+	b := newBatch(client, options)
+	calls := []balanceCall{}
+	seen := make(map[BalanceQuery]bool)
+	for _, q := range queries {
+		if seen[q] {
+			continue
+		}
+		seen[q] = true
+		sb := sc.NewScriptBuilder()
+		err := sb.MakeInvocationScript(q.AssetHash.Bytes(), "balanceOf", []sc.ContractParameter{{Type: sc.Hash160, Value: q.Address.Bytes()}})
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, balanceCall{query: q, script: sb.ToArray()})
+	}
+
+	balances := make(map[BalanceQuery]*big.Int, len(calls))
+	for start := 0; start < len(calls); {
+		end := b.chunkEnd(calls, start)
+		if err := b.run(calls[start:end], balances); err != nil {
+			return nil, err
+		}
+		start = end
+	}
+	return balances, nil
+}
+
+// BalancesOf gets the balances of addresses of the token in the decimals of the token, see BatchBalanceOf
+func (n *Nep5Helper) BalancesOf(addresses []helper.UInt160, options *BatchBalanceOptions) (map[helper.UInt160]TokenAmount, error) {
+	decimals, err := n.Decimals()
+	if err != nil {
+		return nil, err
+	}
+	queries := make([]BalanceQuery, len(addresses))
+	for i, address := range addresses {
+		queries[i] = BalanceQuery{AssetHash: n.scriptHash, Address: address}
+	}
+	balances, err := BatchBalanceOf(n.Client, queries, options)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[helper.UInt160]TokenAmount, len(balances))
+	for q, balance := range balances {
+		result[q.Address] = TokenAmount{Value: balance, Decimals: decimals}
+	}
+	return result, nil
+}
+
+type balanceCall struct {
+	query  BalanceQuery
+	script []byte
+}
+
+type batch struct {
+	client   rpc.IRpcClient
+	maxCalls int
+	maxSize  int
+	maxGas   helper.Fixed8
+	// gasPerCall is the max GAS consumed by a call in the scripts run, zero until a script is run
+	gasPerCall helper.Fixed8
+}
+
+func newBatch(client rpc.IRpcClient, options *BatchBalanceOptions) *batch {
+	if options == nil {
+		options = &BatchBalanceOptions{}
+	}
+	b := &batch{
+		client:   client,
+		maxCalls: options.MaxCallsPerScript,
+		maxSize:  options.MaxScriptSize,
+		maxGas:   options.MaxGasPerScript,
+	}
+	if b.maxCalls <= 0 {
+		b.maxCalls = DefaultMaxCallsPerScript
+	}
+	if b.maxSize <= 0 {
+		b.maxSize = DefaultMaxScriptSize
+	}
+	if !b.maxGas.GreaterThan(helper.Zero) {
+		b.maxGas = DefaultMaxGasPerScript
+	}
+	return b
+}
+
+// chunkEnd returns the end of the next script starting from calls[start], which has at least one call
+func (b *batch) chunkEnd(calls []balanceCall, start int) int {
+	maxCalls := b.maxCalls
+	if b.gasPerCall.GreaterThan(helper.Zero) {
+		if n := int(b.maxGas.Value / b.gasPerCall.Value); n < maxCalls {
+			maxCalls = n
+		}
+	}
+	end := start + 1
+	size := len(calls[start].script)
+	for end < len(calls) && end-start < maxCalls && size+len(calls[end].script) <= b.maxSize {
+		size += len(calls[end].script)
+		end++
+	}
+	return end
+}
+
+// run runs the calls in one script and puts the results into balances, the calls are split if the script faults
+func (b *batch) run(calls []balanceCall, balances map[BalanceQuery]*big.Int) error {
+	script := []byte{}
+	for _, c := range calls {
+		script = append(script, c.script...)
+	}
+	response := b.client.InvokeScript(helper.BytesToHex(script), helper.ZeroScriptHashString)
+	if response.HasError() {
+		return response.GetError()
+	}
+	result := response.Result
+	if strings.Contains(result.State, "FAULT") {
+		if len(calls) == 1 {
+			q := calls[0].query
+			return fmt.Errorf("balanceOf %s of token %s faulted", helper.ScriptHashToAddress(q.Address), q.AssetHash.String())
+		}
+		half := len(calls) / 2
+		if err := b.run(calls[:half], balances); err != nil {
+			return err
+		}
+		return b.run(calls[half:], balances)
+	}
+	if len(result.Stack) != len(calls) {
+		return fmt.Errorf("%d stack items returned for %d balanceOf calls", len(result.Stack), len(calls))
+	}
+	for i, c := range calls {
+		balance, err := models.StackItem(result.Stack[i]).AsBigInt()
+		if err != nil {
+			return fmt.Errorf("balanceOf %s of token %s: %w", helper.ScriptHashToAddress(c.query.Address), c.query.AssetHash.String(), err)
+		}
+		balances[c.query] = balance
+	}
+	if gas, err := helper.Fixed8FromNumber(result.GasConsumed); err == nil {
+		// rounded up so that the next scripts stay below the limit
+		perCall := helper.NewFixed8(gas.Value/int64(len(calls)) + 1)
+		if perCall.GreaterThan(b.gasPerCall) {
+			b.gasPerCall = perCall
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package nep5
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// onBalanceOfCalls mocks the scripts of n balanceOf calls, the balances returned are 1, 2, ... n
+func onBalanceOfCalls(clientMock *rpc.RpcClientMock, n int, state string, gasConsumed string) {
+	stack := []sc.ContractParameter{}
+	for i := 1; i <= n; i++ {
+		stack = append(stack, sc.ContractParameter{Type: sc.Integer, Value: *big.NewInt(int64(i))})
+	}
+	clientMock.On("InvokeScript", mock.MatchedBy(func(script string) bool {
+		return strings.Count(script, helper.BytesToHex([]byte("balanceOf"))) == n
+	}), mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{State: state, GasConsumed: gasConsumed, Stack: stack},
+	})
+}
+
+func balanceQueries(n int) []BalanceQuery {
+	asset, _ := helper.UInt160FromString("0xb9d7ea3062e6aeeb3e8ad9548220c4ba1361d263")
+	queries := []BalanceQuery{}
+	for i := 0; i < n; i++ {
+		queries = append(queries, BalanceQuery{AssetHash: asset, Address: helper.UInt160{byte(i + 1)}})
+	}
+	return queries
+}
+
+func TestBatchBalanceOf(t *testing.T) {
+	clientMock := new(rpc.RpcClientMock)
+	onBalanceOfCalls(clientMock, 2, "HALT", "0.2")
+	onBalanceOfCalls(clientMock, 1, "HALT", "0.1")
+	queries := balanceQueries(5)
+
+	balances, err := BatchBalanceOf(clientMock, append(queries, queries[0]), &BatchBalanceOptions{MaxCallsPerScript: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(balances))
+	assert.Equal(t, big.NewInt(1), balances[queries[0]])
+	assert.Equal(t, big.NewInt(2), balances[queries[1]])
+	assert.Equal(t, big.NewInt(1), balances[queries[4]])
+	clientMock.AssertNumberOfCalls(t, "InvokeScript", 3)
+}
+
+func TestBatchBalanceOf_Fault(t *testing.T) {
+	clientMock := new(rpc.RpcClientMock)
+	onBalanceOfCalls(clientMock, 4, "FAULT, BREAK", "10")
+	onBalanceOfCalls(clientMock, 2, "HALT", "0.2")
+	queries := balanceQueries(4)
+
+	balances, err := BatchBalanceOf(clientMock, queries, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(1), balances[queries[2]])
+	assert.Equal(t, big.NewInt(2), balances[queries[3]])
+	clientMock.AssertNumberOfCalls(t, "InvokeScript", 3)
+
+	clientMock = new(rpc.RpcClientMock)
+	onBalanceOfCalls(clientMock, 1, "FAULT, BREAK", "0.1")
+	_, err = BatchBalanceOf(clientMock, queries[:1], nil)
+	assert.NotNil(t, err)
+}
+
+func TestBatchBalanceOf_Limits(t *testing.T) {
+	// the first script shows that a call takes 3 GAS, so the next ones have 3 calls
+	clientMock := new(rpc.RpcClientMock)
+	onBalanceOfCalls(clientMock, 4, "HALT", "12")
+	onBalanceOfCalls(clientMock, 3, "HALT", "9")
+	_, err := BatchBalanceOf(clientMock, balanceQueries(10), &BatchBalanceOptions{MaxCallsPerScript: 4, MaxGasPerScript: helper.Fixed8FromInt64(10)})
+	assert.Nil(t, err)
+	clientMock.AssertNumberOfCalls(t, "InvokeScript", 3)
+
+	// a call is 56 bytes, so a script has 2 calls
+	b := newBatch(nil, &BatchBalanceOptions{MaxScriptSize: 120})
+	calls := []balanceCall{{script: make([]byte, 56)}, {script: make([]byte, 56)}, {script: make([]byte, 56)}}
+	assert.Equal(t, 2, b.chunkEnd(calls, 0))
+	assert.Equal(t, 3, b.chunkEnd(calls, 2))
+}
+
+func TestNep5Helper_BalancesOf(t *testing.T) {
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("InvokeScript", mock.MatchedBy(func(script string) bool {
+		return strings.Contains(script, helper.BytesToHex([]byte("decimals")))
+	}), mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{
+			State: "HALT",
+			Stack: []sc.ContractParameter{{Type: sc.Integer, Value: *big.NewInt(8)}},
+		},
+	})
+	onBalanceOfCalls(clientMock, 2, "HALT", "0.2")
+	queries := balanceQueries(2)
+	nh := NewNep5HelperFromClient(queries[0].AssetHash, clientMock)
+
+	balances, err := nh.BalancesOf([]helper.UInt160{queries[0].Address, queries[1].Address}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.00000002", balances[queries[1].Address].String())
+}
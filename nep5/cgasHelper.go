@@ -1,11 +1,11 @@
 package nep5
 
 import (
-	"fmt"
 	"sort"
 	"time"
 
 	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc"
 	"github.com/joeqian10/neo-gogogo/sc"
 	"github.com/joeqian10/neo-gogogo/tx"
 	"github.com/joeqian10/neo-gogogo/wallet"
@@ -16,17 +16,21 @@ type CgasHelper Nep5Helper
 
 func NewCgasHelperFromNep5Helper(nep5Helper *Nep5Helper) *CgasHelper {
 	cgasHelper := CgasHelper{
-		scriptHash:nep5Helper.scriptHash,
-		EndPoint:nep5Helper.EndPoint,
-		Client:nep5Helper.Client,
+		scriptHash: nep5Helper.scriptHash,
+		EndPoint:   nep5Helper.EndPoint,
+		Client:     nep5Helper.Client,
 	}
 	return &cgasHelper
 }
 
 func (c *CgasHelper) MintTokens(from *wallet.Account, amount float64) (string, error) {
+	return c.MintTokensFixed8(from, helper.Fixed8FromFloat64(amount))
+}
+
+// MintTokensFixed8 is the same as MintTokens, but takes an exact amount
+func (c *CgasHelper) MintTokensFixed8(from *wallet.Account, a helper.Fixed8) (string, error) {
 	// A mintTokens method for CGAS users, who can transfer GAS to CGAS contract address by constructing InvocationTransaction and convert GAS to CGAS by invoking mintTokens method.
 	// Upon successful invocation, CGAS in the equal value of the GAS will be added to the user's asset account.
-	a := helper.Fixed8FromFloat64(amount)
 	f, err := helper.AddressToScriptHash(from.Address)
 	if err != nil {
 		return "", err
@@ -39,7 +43,7 @@ func (c *CgasHelper) MintTokens(from *wallet.Account, amount float64) (string, e
 	//script := helper.HexToBytes("000a6d696e74546f6b656e7367f3c7a1170d2e9cb33827210daedf257db0c38c2a") // 00  0a6d696e74546f6b656e7367 f3c7a1170d2e9cb33827210daedf257db0c38c2a
 
 	// Second, instantiate an object of InvocationTransaction
-	tb := tx.NewTransactionBuilder(c.EndPoint)
+	tb := &tx.TransactionBuilder{EndPoint: c.EndPoint, Client: c.Client}
 	gas, err := tb.GetGasConsumed(script, f.String())
 	if err != nil {
 		return "", err
@@ -51,7 +55,7 @@ func (c *CgasHelper) MintTokens(from *wallet.Account, amount float64) (string, e
 		return "", err
 	}
 	if !totalPay.GreaterThan(a) {
-		return "", fmt.Errorf("insufficient funds")
+		return "", rpc.ErrInsufficientFunds
 	}
 	myTx := tx.NewInvocationTransaction(script)
 	myTx.Gas = *gas // here gas = 0
@@ -70,14 +74,19 @@ func (c *CgasHelper) MintTokens(from *wallet.Account, amount float64) (string, e
 	// use RPC to send the tx
 	response := tb.Client.SendRawTransaction(myTx.RawTransactionString())
 	if response.HasError() {
-		return "", fmt.Errorf(response.ErrorResponse.Error.Message)
+		return "", response.GetError()
 	}
 	return myTx.HashString(), nil
 }
 
 // this method calls two sub methods inside, since refund from CGAS to gas needs two steps (transactions)
 func (c *CgasHelper) Refund(from *wallet.Account, txHash helper.UInt256, amount float64) (string, error) {
-	txId, err := c.Refund1(from, txHash, amount)
+	return c.RefundFixed8(from, txHash, helper.Fixed8FromFloat64(amount))
+}
+
+// RefundFixed8 is the same as Refund, but takes an exact amount
+func (c *CgasHelper) RefundFixed8(from *wallet.Account, txHash helper.UInt256, amount helper.Fixed8) (string, error) {
+	txId, err := c.Refund1Fixed8(from, txHash, amount)
 	if err != nil {
 		return "", err
 	}
@@ -86,11 +95,16 @@ func (c *CgasHelper) Refund(from *wallet.Account, txHash helper.UInt256, amount
 	if err != nil {
 		return "", err
 	}
-	return c.Refund2(from, txHash1, amount)
+	return c.Refund2Fixed8(from, txHash1, amount)
 }
 
 // make this method public so developers can call this separately
 func (c *CgasHelper) Refund1(from *wallet.Account, txHash helper.UInt256, amount float64) (string, error) {
+	return c.Refund1Fixed8(from, txHash, helper.Fixed8FromFloat64(amount))
+}
+
+// Refund1Fixed8 is the same as Refund1, but takes an exact amount
+func (c *CgasHelper) Refund1Fixed8(from *wallet.Account, txHash helper.UInt256, amount helper.Fixed8) (string, error) {
 	// --------------------------------------------------------
 	// STEP 1
 	// --------------------------------------------------------
@@ -102,9 +116,9 @@ func (c *CgasHelper) Refund1(from *wallet.Account, txHash helper.UInt256, amount
 
 	// build outputs
 	output0 := tx.TransactionOutput{
-		AssetId:    tx.GasToken,                      // must be GAS
-		Value:      helper.Fixed8FromFloat64(amount), // if large than the amount you mint, this will fail
-		ScriptHash: c.scriptHash,                     // must be the CGAS contract script hash
+		AssetId:    tx.GasToken,  // must be GAS
+		Value:      amount,       // if large than the amount you mint, this will fail
+		ScriptHash: c.scriptHash, // must be the CGAS contract script hash
 	}
 
 	// build script
@@ -163,13 +177,18 @@ func (c *CgasHelper) Refund1(from *wallet.Account, txHash helper.UInt256, amount
 	// use RPC to send the tx
 	response := c.Client.SendRawTransaction(t.RawTransactionString())
 	if response.HasError() {
-		return "", fmt.Errorf(response.ErrorResponse.Error.Message)
+		return "", response.GetError()
 	}
 	return t.HashString(), nil
 }
 
 // make this method public so developers can call this separately
 func (c *CgasHelper) Refund2(from *wallet.Account, txHash helper.UInt256, amount float64) (string, error) {
+	return c.Refund2Fixed8(from, txHash, helper.Fixed8FromFloat64(amount))
+}
+
+// Refund2Fixed8 is the same as Refund2, but takes an exact amount
+func (c *CgasHelper) Refund2Fixed8(from *wallet.Account, txHash helper.UInt256, amount helper.Fixed8) (string, error) {
 	// --------------------------------------------------------
 	// STEP 2
 	// --------------------------------------------------------
@@ -182,9 +201,9 @@ func (c *CgasHelper) Refund2(from *wallet.Account, txHash helper.UInt256, amount
 	// build outputs
 	user, _ := helper.AddressToScriptHash(from.Address)
 	output0 := tx.TransactionOutput{
-		AssetId:    tx.GasToken,                      // must be GAS
-		Value:      helper.Fixed8FromFloat64(amount), // if large than the amount you mint, this will fail
-		ScriptHash: user,                             // must be the CGAS contract script hash
+		AssetId:    tx.GasToken, // must be GAS
+		Value:      amount,      // if large than the amount you mint, this will fail
+		ScriptHash: user,        // must be the CGAS contract script hash
 	}
 
 	sb := sc.NewScriptBuilder()
@@ -204,7 +223,7 @@ func (c *CgasHelper) Refund2(from *wallet.Account, txHash helper.UInt256, amount
 	// use RPC to send the tx
 	response := c.Client.SendRawTransaction(t.RawTransactionString())
 	if response.HasError() {
-		return "", fmt.Errorf(response.ErrorResponse.Error.Message)
+		return "", response.GetError()
 	}
 
 	return t.HashString(), nil
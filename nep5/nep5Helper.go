@@ -1,13 +1,14 @@
 package nep5
 
 import (
-	"encoding/binary"
 	"fmt"
-	"strconv"
+	"math/big"
 
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
 	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/joeqian10/neo-gogogo/wallet"
 )
 
 // nep5 wrapper class, api reference: https://github.com/neo-project/proposals/blob/master/nep-5.mediawiki#name
@@ -29,27 +30,23 @@ func NewNep5Helper(scriptHash helper.UInt160, endPoint string) *Nep5Helper {
 	}
 }
 
+// NewNep5HelperFromClient uses an existing client, such as a rpc.RpcClientPool
+func NewNep5HelperFromClient(scriptHash helper.UInt160, client rpc.IRpcClient) *Nep5Helper {
+	return &Nep5Helper{
+		scriptHash: scriptHash,
+		Client:     client,
+	}
+}
+
 func (n *Nep5Helper) TotalSupply() (uint64, error) {
 	sb := sc.NewScriptBuilder()
 	sb.MakeInvocationScript(n.scriptHash.Bytes(), "totalSupply", []sc.ContractParameter{})
 	script := sb.ToArray()
 	response := n.Client.InvokeScript(helper.BytesToHex(script), helper.ZeroScriptHashString)
 	if response.HasError() {
-		return 0, fmt.Errorf(response.ErrorResponse.Error.Message)
-	}
-	if response.Result.State == "FAULT" {
-		return 0, fmt.Errorf("engine faulted")
+		return 0, response.GetError()
 	}
-	if len(response.Result.Stack) == 0 {
-		return 0, fmt.Errorf("no stack result returned")
-	}
-	stack := response.Result.Stack[0]
-	bytes := helper.HexToBytes(stack.Value.(string))
-	for len(bytes) < 8 {
-		bytes = append(bytes, byte(0x00))
-	}
-	ts := binary.LittleEndian.Uint64(bytes)
-	return ts, nil
+	return toUint64(response.Result)
 }
 
 func (n *Nep5Helper) Name() (string, error) {
@@ -58,17 +55,9 @@ func (n *Nep5Helper) Name() (string, error) {
 	script := sb.ToArray()
 	response := n.Client.InvokeScript(helper.BytesToHex(script), helper.ZeroScriptHashString)
 	if response.HasError() {
-		return "", fmt.Errorf(response.ErrorResponse.Error.Message)
-	}
-	if response.Result.State == "FAULT" {
-		return "", fmt.Errorf("engine faulted")
+		return "", response.GetError()
 	}
-	if len(response.Result.Stack) == 0 {
-		return "", fmt.Errorf("no stack result returned")
-	}
-	stack := response.Result.Stack[0]
-	name := string(helper.HexToBytes(stack.Value.(string)))
-	return name, nil
+	return response.Result.AsString()
 }
 
 func (n *Nep5Helper) Symbol() (string, error) {
@@ -77,17 +66,9 @@ func (n *Nep5Helper) Symbol() (string, error) {
 	script := sb.ToArray()
 	response := n.Client.InvokeScript(helper.BytesToHex(script), helper.ZeroScriptHashString)
 	if response.HasError() {
-		return "", fmt.Errorf(response.ErrorResponse.Error.Message)
-	}
-	if response.Result.State == "FAULT" {
-		return "", fmt.Errorf("engine faulted")
-	}
-	if len(response.Result.Stack) == 0 {
-		return "", fmt.Errorf("no stack result returned")
+		return "", response.GetError()
 	}
-	stack := response.Result.Stack[0]
-	symbol := string(helper.HexToBytes(stack.Value.(string)))
-	return symbol, nil
+	return response.Result.AsString()
 }
 
 func (n *Nep5Helper) Decimals() (uint8, error) {
@@ -96,20 +77,16 @@ func (n *Nep5Helper) Decimals() (uint8, error) {
 	script := sb.ToArray()
 	response := n.Client.InvokeScript(helper.BytesToHex(script), helper.ZeroScriptHashString)
 	if response.HasError() {
-		return 0, fmt.Errorf(response.ErrorResponse.Error.Message)
+		return 0, response.GetError()
 	}
-	if response.Result.State == "FAULT" {
-		return 0, fmt.Errorf("engine faulted")
-	}
-	if len(response.Result.Stack) == 0 {
-		return 0, fmt.Errorf("no stack result returned")
-	}
-	stack := response.Result.Stack[0]
-	decimals, err := strconv.ParseUint(stack.Value.(string), 10, 8)
+	decimals, err := response.Result.AsBigInt()
 	if err != nil {
-		return 0, fmt.Errorf("conversion failed")
+		return 0, err
 	}
-	return uint8(decimals), nil
+	if decimals.Sign() < 0 || decimals.Cmp(big.NewInt(255)) > 0 {
+		return 0, fmt.Errorf("decimals %s out of range", decimals.String())
+	}
+	return uint8(decimals.Uint64()), nil
 }
 
 func (n *Nep5Helper) BalanceOf(address helper.UInt160) (uint64, error) {
@@ -122,21 +99,98 @@ func (n *Nep5Helper) BalanceOf(address helper.UInt160) (uint64, error) {
 	script := sb.ToArray()
 	response := n.Client.InvokeScript(helper.BytesToHex(script), helper.ZeroScriptHashString)
 	if response.HasError() {
-		return 0, fmt.Errorf(response.ErrorResponse.Error.Message)
+		return 0, response.GetError()
+	}
+	return toUint64(response.Result)
+}
+
+// TotalSupplyAmount returns the total supply in the decimals of the token, which may exceed uint64
+func (n *Nep5Helper) TotalSupplyAmount() (TokenAmount, error) {
+	decimals, err := n.Decimals()
+	if err != nil {
+		return TokenAmount{}, err
+	}
+	result, err := n.invoke("totalSupply")
+	if err != nil {
+		return TokenAmount{}, err
+	}
+	return toTokenAmount(result, decimals)
+}
+
+// BalanceOfAmount returns the balance of address in the decimals of the token, which may exceed uint64
+func (n *Nep5Helper) BalanceOfAmount(address helper.UInt160) (TokenAmount, error) {
+	decimals, err := n.Decimals()
+	if err != nil {
+		return TokenAmount{}, err
+	}
+	result, err := n.invoke("balanceOf", sc.ContractParameter{Type: sc.Hash160, Value: address.Bytes()})
+	if err != nil {
+		return TokenAmount{}, err
+	}
+	return toTokenAmount(result, decimals)
+}
+
+// ParseAmount parses a decimal string such as "1.5" in the decimals of the token
+func (n *Nep5Helper) ParseAmount(s string) (TokenAmount, error) {
+	decimals, err := n.Decimals()
+	if err != nil {
+		return TokenAmount{}, err
+	}
+	return ParseTokenAmount(s, decimals)
+}
+
+// Transfer sends amount of the token from one address to another by the WalletHelper, the amount is converted to
+// the decimals of the token, and an error is returned if it has more decimals than the token. Returns the txid.
+func (n *Nep5Helper) Transfer(w *wallet.WalletHelper, from string, to string, amount TokenAmount) (string, error) {
+	decimals, err := n.Decimals()
+	if err != nil {
+		return "", err
+	}
+	a, err := amount.ToDecimals(decimals)
+	if err != nil {
+		return "", err
+	}
+	if a.Sign() <= 0 {
+		return "", fmt.Errorf("amount %s is not positive", a.String())
+	}
+	return w.TransferNep5BigInt(n.scriptHash, from, to, a.Value)
+}
+
+// invoke runs method of the token by invokescript
+func (n *Nep5Helper) invoke(method string, args ...sc.ContractParameter) (models.InvokeResult, error) {
+	if args == nil {
+		args = []sc.ContractParameter{}
+	}
+	sb := sc.NewScriptBuilder()
+	if err := sb.MakeInvocationScript(n.scriptHash.Bytes(), method, args); err != nil {
+		return models.InvokeResult{}, err
+	}
+	response := n.Client.InvokeScript(helper.BytesToHex(sb.ToArray()), helper.ZeroScriptHashString)
+	if response.HasError() {
+		return models.InvokeResult{}, response.GetError()
 	}
-	if response.Result.State == "FAULT" {
-		return 0, fmt.Errorf("engine faulted")
+	return response.Result, nil
+}
+
+// toTokenAmount converts the result to an amount of a token with decimals
+func toTokenAmount(result models.InvokeResult, decimals uint8) (TokenAmount, error) {
+	i, err := result.AsBigInt()
+	if err != nil {
+		return TokenAmount{}, err
 	}
-	if len(response.Result.Stack) == 0 {
-		return 0, fmt.Errorf("no stack result returned")
+	return TokenAmount{Value: i, Decimals: decimals}, nil
+}
+
+// toUint64 converts the result to an amount, an error is returned if it does not fit in uint64
+func toUint64(result models.InvokeResult) (uint64, error) {
+	i, err := result.AsBigInt()
+	if err != nil {
+		return 0, err
 	}
-	stack := response.Result.Stack[0]
-	bytes := helper.HexToBytes(stack.Value.(string))
-	for len(bytes) < 8 {
-		bytes = append(bytes, byte(0x00))
+	if !i.IsUint64() {
+		return 0, fmt.Errorf("amount %s out of the range of uint64", i.String())
 	}
-	balance := binary.LittleEndian.Uint64(bytes)
-	return balance, nil
+	return i.Uint64(), nil
 }
 
 // This method is deprecated
@@ -159,7 +213,7 @@ func (n *Nep5Helper) BalanceOf(address helper.UInt160) (uint64, error) {
 //	script := sb.ToArray()
 //	response := n.Client.InvokeScript(helper.BytesToHex(script))
 //	if response.HasError() {
-//		return false, []byte{}, fmt.Errorf(response.ErrorResponse.Error.Message)
+//		return false, []byte{}, response.GetError()
 //	}
 //	if response.Result.State == "FAULT" {
 //		return false, []byte{}, fmt.Errorf("engine faulted")
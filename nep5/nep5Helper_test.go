@@ -1,11 +1,14 @@
 package nep5
 
 import (
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/rpc"
 	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -16,6 +19,13 @@ func TestNewNep5Helper(t *testing.T) {
 	assert.NotNil(t, nep5helper)
 }
 
+func TestNewNep5HelperFromClient(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("0xb9d7ea3062e6aeeb3e8ad9548220c4ba1361d263")
+	pool := rpc.NewClientPool("http://seed1.ngd.network:20332", "http://seed2.ngd.network:20332")
+	nep5helper := NewNep5HelperFromClient(scriptHash, pool)
+	assert.Equal(t, pool, nep5helper.Client)
+}
+
 func TestNep5Helper_BalanceOf(t *testing.T) {
 	var clientMock = new(rpc.RpcClientMock)
 	scriptHash, _ := helper.UInt160FromString("0xb9d7ea3062e6aeeb3e8ad9548220c4ba1361d263")
@@ -38,10 +48,10 @@ func TestNep5Helper_BalanceOf(t *testing.T) {
 			Script:      "148f6c5be89c0cb6579e44a8bf9bfd2ecbcc11dfdc51c10962616c616e63654f666763d26113bac4208254d98a3eebaee66230ead7b9",
 			State:       "HALT",
 			GasConsumed: "0.383",
-			Stack: []models.InvokeStack{
+			Stack: []sc.ContractParameter{
 				{
-					Type:  "ByteArray",
-					Value: "004eaca7902c",
+					Type:  sc.ByteArray,
+					Value: helper.HexToBytes("004eaca7902c"),
 				},
 			},
 		},
@@ -75,10 +85,10 @@ func TestNep5Helper_Decimals(t *testing.T) {
 			Script:      "00c108646563696d616c736763d26113bac4208254d98a3eebaee66230ead7b9",
 			State:       "HALT",
 			GasConsumed: "0.246",
-			Stack: []models.InvokeStack{
+			Stack: []sc.ContractParameter{
 				{
-					Type:  "Integer",
-					Value: "8",
+					Type:  sc.Integer,
+					Value: *big.NewInt(8),
 				},
 			},
 		},
@@ -111,10 +121,10 @@ func TestNep5Helper_Name(t *testing.T) {
 			Script:      "00c1046e616d656763d26113bac4208254d98a3eebaee66230ead7b9",
 			State:       "HALT",
 			GasConsumed: "0.126",
-			Stack: []models.InvokeStack{
+			Stack: []sc.ContractParameter{
 				{
-					Type:  "ByteArray",
-					Value: "516c696e6b20546f6b656e",
+					Type:  sc.ByteArray,
+					Value: helper.HexToBytes("516c696e6b20546f6b656e"),
 				},
 			},
 		},
@@ -148,10 +158,10 @@ func TestNep5Helper_Symbol(t *testing.T) {
 			Script:      "00c10673796d626f6c6763d26113bac4208254d98a3eebaee66230ead7b9",
 			State:       "HALT",
 			GasConsumed: "0.141",
-			Stack: []models.InvokeStack{
+			Stack: []sc.ContractParameter{
 				{
-					Type:  "ByteArray",
-					Value: "514c43",
+					Type:  sc.ByteArray,
+					Value: helper.HexToBytes("514c43"),
 				},
 			},
 		},
@@ -182,10 +192,10 @@ func TestNep5Helper_TotalSupply(t *testing.T) {
 			Script:      "00c10b746f74616c537570706c796763d26113bac4208254d98a3eebaee66230ead7b9",
 			State:       "HALT",
 			GasConsumed: "0.223",
-			Stack: []models.InvokeStack{
+			Stack: []sc.ContractParameter{
 				{
-					Type:  "ByteArray",
-					Value: "00803dafbe50d300",
+					Type:  sc.ByteArray,
+					Value: helper.HexToBytes("00803dafbe50d300"),
 				},
 			},
 		},
@@ -196,6 +206,76 @@ func TestNep5Helper_TotalSupply(t *testing.T) {
 	assert.Equal(t, uint64(59480000000000000), s)
 }
 
+func TestNep5Helper_TotalSupply_Overflow(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	var nh = Nep5Helper{
+		Client: clientMock,
+	}
+	clientMock.On("InvokeScript", mock.Anything, mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{
+			State: "HALT",
+			Stack: []sc.ContractParameter{
+				{
+					Type:  sc.ByteArray,
+					Value: helper.HexToBytes("000000000000000001"),
+				},
+			},
+		},
+	})
+
+	_, e := nh.TotalSupply()
+	assert.NotNil(t, e)
+}
+
+func TestNep5Helper_BalanceOfAmount(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	var nh = Nep5Helper{
+		Client: clientMock,
+	}
+	isMethod := func(method string) interface{} {
+		return mock.MatchedBy(func(script string) bool {
+			return strings.Contains(script, helper.BytesToHex([]byte(method)))
+		})
+	}
+	clientMock.On("InvokeScript", isMethod("decimals"), mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{
+			State: "HALT",
+			Stack: []sc.ContractParameter{{Type: sc.Integer, Value: *big.NewInt(18)}},
+		},
+	})
+	balance, _ := new(big.Int).SetString("123456789012345678901234567890", 10) // above 2^64
+	clientMock.On("InvokeScript", isMethod("balanceOf"), mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{
+			State: "HALT",
+			Stack: []sc.ContractParameter{{Type: sc.ByteArray, Value: helper.BigIntToNeoBytes(balance)}},
+		},
+	})
+	clientMock.On("InvokeScript", isMethod("totalSupply"), mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{
+			State: "HALT",
+			Stack: []sc.ContractParameter{{Type: sc.Integer, Value: *balance}},
+		},
+	})
+
+	address, _ := helper.AddressToScriptHash("AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+	a, err := nh.BalanceOfAmount(address)
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(18), a.Decimals)
+	assert.Equal(t, "123456789012.34567890123456789", a.String())
+	_, err = nh.BalanceOf(address)
+	assert.NotNil(t, err)
+
+	s, err := nh.TotalSupplyAmount()
+	assert.Nil(t, err)
+	assert.True(t, s.Equal(a))
+
+	p, err := nh.ParseAmount("0.000000000000000001")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), p.Value.Int64())
+	_, err = nh.ParseAmount("0.0000000000000000001")
+	assert.NotNil(t, err)
+}
+
 //func TestNep5Helper_Transfer(t *testing.T) {
 //	var clientMock = new(rpc.RpcClientMock)
 //	var nh = Nep5Helper{
@@ -0,0 +1,109 @@
+package nep5
+
+import (
+	"fmt"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/joeqian10/neo-gogogo/tx"
+)
+
+// TransferRequest is a transfer of a NEP-5 token from one address to another
+type TransferRequest struct {
+	From   helper.UInt160
+	To     helper.UInt160
+	Amount TokenAmount
+}
+
+// TransferOptions are the optional settings of a transfer transaction
+type TransferOptions struct {
+	// NetworkFee is the GAS paid to the nodes, zero for a free transaction
+	NetworkFee helper.Fixed8
+	// Remark is added as a Remark attribute if it is not empty
+	Remark string
+	// Payer pays the network fee, the sender of the first transfer pays if it is empty
+	Payer helper.UInt160
+	// ChangeAddress receives the change of the fee, the payer receives it if it is empty
+	ChangeAddress helper.UInt160
+	// Selector selects the GAS inputs of the fee, tx.LargestFirstSelector is used if it is nil
+	Selector tx.CoinSelector
+}
+
+// MakeTransferTransaction builds an unsigned InvocationTransaction transferring amount of the token, see
+// MakeMultiTransferTransaction
+func (n *Nep5Helper) MakeTransferTransaction(from helper.UInt160, to helper.UInt160, amount TokenAmount, options *TransferOptions) (*tx.InvocationTransaction, error) {
+	return n.MakeMultiTransferTransaction([]TransferRequest{{From: from, To: to, Amount: amount}}, options)
+}
+
+// MakeMultiTransferTransaction builds an unsigned InvocationTransaction running all the transfers in a single script,
+// the transaction fails if any of them returns false. Every sender is added as a Script attribute so that it must
+// sign the transaction. The script is test run with the witnesses of all the senders to get the gas, and an error
+// is returned if it faults. The transaction is returned for signing offline, it is not sent.
+func (n *Nep5Helper) MakeMultiTransferTransaction(transfers []TransferRequest, options *TransferOptions) (*tx.InvocationTransaction, error) {
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("no transfer")
+	}
+	if options == nil {
+		options = &TransferOptions{}
+	}
+	script, err := n.MakeTransferScript(transfers)
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes []*tx.TransactionAttribute
+	var senders []helper.UInt160
+	added := make(map[helper.UInt160]bool)
+	for _, t := range transfers {
+		if !added[t.From] {
+			added[t.From] = true
+			senders = append(senders, t.From)
+			attributes = append(attributes, &tx.TransactionAttribute{Usage: tx.Script, Data: t.From.Bytes()})
+		}
+	}
+	if options.Remark != "" {
+		attributes = append(attributes, &tx.TransactionAttribute{Usage: tx.Remark, Data: []byte(options.Remark)})
+	}
+
+	payer := options.Payer
+	if payer == (helper.UInt160{}) {
+		payer = transfers[0].From
+	}
+	tb := tx.NewTransactionBuilderFromClient(n.Client)
+	gas, err := tb.GetGasConsumedWithWitnesses(script, senders)
+	if err != nil {
+		return nil, err
+	}
+	return tb.MakeInvocationTransactionWithGas(script, payer, attributes, options.ChangeAddress, gas, options.NetworkFee, options.Selector)
+}
+
+// MakeTransferScript returns a script calling transfer for each of the transfers followed by THROWIFNOT,
+// the amounts are converted to the decimals of the token
+func (n *Nep5Helper) MakeTransferScript(transfers []TransferRequest) ([]byte, error) {
+	decimals, err := n.Decimals()
+	if err != nil {
+		return nil, err
+	}
+	sb := sc.NewScriptBuilder()
+	for i, t := range transfers {
+		amount, err := t.Amount.ToDecimals(decimals)
+		if err != nil {
+			return nil, fmt.Errorf("transfer %d: %w", i, err)
+		}
+		if amount.Sign() <= 0 {
+			return nil, fmt.Errorf("transfer %d: amount %s is not positive", i, amount.String())
+		}
+		args := []sc.ContractParameter{
+			{Type: sc.Hash160, Value: t.From.Bytes()},
+			{Type: sc.Hash160, Value: t.To.Bytes()},
+			{Type: sc.Integer, Value: *amount.Value},
+		}
+		if err = sb.MakeInvocationScript(n.scriptHash.Bytes(), "transfer", args); err != nil {
+			return nil, err
+		}
+		if err = sb.Emit(sc.THROWIFNOT); err != nil {
+			return nil, err
+		}
+	}
+	return sb.ToArray(), nil
+}
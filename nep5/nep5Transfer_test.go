@@ -0,0 +1,182 @@
+package nep5
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/joeqian10/neo-gogogo/tx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTransferClientMock() *rpc.RpcClientMock {
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("InvokeScript", mock.MatchedBy(func(script string) bool {
+		return strings.Contains(script, helper.BytesToHex([]byte("decimals")))
+	}), mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{
+			State: "HALT",
+			Stack: []sc.ContractParameter{{Type: sc.Integer, Value: *big.NewInt(18)}},
+		},
+	})
+	clientMock.On("InvokeScript", mock.MatchedBy(func(script string) bool {
+		return strings.Contains(script, helper.BytesToHex([]byte("transfer")))
+	}), mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{
+			State:       "HALT",
+			GasConsumed: "0.988",
+			Stack:       []sc.ContractParameter{{Type: sc.Boolean, Value: true}},
+		},
+	})
+	// the transfers of d run only with the witness of d, and the transfers of e always fault
+	d, _ := helper.AddressToScriptHash("AKeLhhHm4hEUfLWVBCYRNjio9xhGJAom5G")
+	e, _ := helper.AddressToScriptHash("AK2nJJpJr6o664CWJKi1QRXjqeic2zRp8y")
+	hasHash := func(h helper.UInt160) interface{} {
+		return mock.MatchedBy(func(hashes []string) bool {
+			for _, s := range hashes {
+				if s == h.String() {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	inScript := func(h helper.UInt160) interface{} {
+		return mock.MatchedBy(func(script string) bool {
+			return strings.Contains(script, helper.BytesToHex(h.Bytes()))
+		})
+	}
+	clientMock.On("InvokeScriptWithWitnesses", inScript(e), mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{State: "FAULT, BREAK", GasConsumed: "0.1"},
+	})
+	clientMock.On("InvokeScriptWithWitnesses", inScript(d), hasHash(d)).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{State: "HALT", GasConsumed: "10.5"},
+	})
+	clientMock.On("InvokeScriptWithWitnesses", inScript(d), mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{State: "FAULT, BREAK", GasConsumed: "0.1"},
+	})
+	clientMock.On("InvokeScriptWithWitnesses", mock.Anything, mock.Anything).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{State: "HALT", GasConsumed: "0.988"},
+	})
+	clientMock.On("GetUnspents", mock.Anything).Return(rpc.GetUnspentsResponse{
+		Result: models.RpcUnspent{
+			Balances: []models.UnspentBalance{
+				{
+					Unspents: []models.Unspent{
+						{
+							Txid:  "0a99ebd286931375c2ec828603e88392e3a40e9cecd4b228bd6be206fdb21005",
+							N:     0,
+							Value: helper.Fixed8FromInt64(1),
+						},
+					},
+					AssetHash: tx.GasTokenId,
+					Amount:    helper.Fixed8FromInt64(1),
+				},
+			},
+		},
+	})
+	return clientMock
+}
+
+func TestNep5Helper_MakeTransferScript(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("0xb9d7ea3062e6aeeb3e8ad9548220c4ba1361d263")
+	nh := NewNep5HelperFromClient(scriptHash, newTransferClientMock())
+	from, _ := helper.AddressToScriptHash("AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+	to, _ := helper.AddressToScriptHash("AdQk428wVzpkHTxc4MP5UMdsgNdrm36dyV")
+	amount, _ := ParseTokenAmount("1.5", 8)
+
+	script, err := nh.MakeTransferScript([]TransferRequest{{From: from, To: to, Amount: amount}})
+	assert.Nil(t, err)
+	expected := sc.NewScriptBuilder()
+	_ = expected.MakeInvocationScript(scriptHash.Bytes(), "transfer", []sc.ContractParameter{
+		{Type: sc.Hash160, Value: from.Bytes()},
+		{Type: sc.Hash160, Value: to.Bytes()},
+		{Type: sc.Integer, Value: *big.NewInt(1500000000000000000)}, // in 18 decimals
+	})
+	_ = expected.Emit(sc.THROWIFNOT)
+	assert.Equal(t, expected.ToArray(), script)
+
+	_, err = nh.MakeTransferScript([]TransferRequest{{From: from, To: to, Amount: TokenAmount{}}})
+	assert.NotNil(t, err)
+	tooPrecise, _ := ParseTokenAmount("0.0000000000000000001", 19)
+	_, err = nh.MakeTransferScript([]TransferRequest{{From: from, To: to, Amount: tooPrecise}})
+	assert.NotNil(t, err)
+}
+
+func TestNep5Helper_MakeMultiTransferTransaction(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("0xb9d7ea3062e6aeeb3e8ad9548220c4ba1361d263")
+	clientMock := newTransferClientMock()
+	nh := NewNep5HelperFromClient(scriptHash, clientMock)
+	a, _ := helper.AddressToScriptHash("AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+	b, _ := helper.AddressToScriptHash("AdQk428wVzpkHTxc4MP5UMdsgNdrm36dyV")
+	c, _ := helper.AddressToScriptHash("APPmjituYcgfNxjuQDy9vP73R2PmhFsYJR")
+	amount, _ := ParseTokenAmount("1", 0)
+
+	itx, err := nh.MakeMultiTransferTransaction([]TransferRequest{
+		{From: a, To: b, Amount: amount},
+		{From: a, To: c, Amount: amount},
+		{From: b, To: c, Amount: amount},
+	}, &TransferOptions{NetworkFee: helper.NewFixed8(1000000), Remark: "payroll"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, bytes.Count(itx.Script, []byte("transfer")))
+	assert.Equal(t, byte(sc.THROWIFNOT), itx.Script[len(itx.Script)-1])
+	assert.Equal(t, helper.Zero, itx.Gas)
+
+	// both senders must sign
+	assert.Equal(t, 3, len(itx.Attributes))
+	assert.Equal(t, tx.Script, itx.Attributes[0].Usage)
+	assert.Equal(t, a.Bytes(), itx.Attributes[0].Data)
+	assert.Equal(t, b.Bytes(), itx.Attributes[1].Data)
+	assert.Equal(t, tx.Remark, itx.Attributes[2].Usage)
+	assert.Equal(t, []byte("payroll"), itx.Attributes[2].Data)
+
+	// the fee is paid by the first sender, and the change goes back
+	assert.Equal(t, 1, len(itx.Inputs))
+	assert.Equal(t, 1, len(itx.Outputs))
+	assert.Equal(t, int64(99000000), itx.Outputs[0].Value.Value)
+	assert.Equal(t, a, itx.Outputs[0].ScriptHash)
+	assert.Equal(t, 0, len(itx.Witnesses))
+	clientMock.AssertNotCalled(t, "SendRawTransaction", mock.Anything)
+
+	// no fee, no input
+	itx, err = nh.MakeTransferTransaction(a, b, amount, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(itx.Attributes))
+	assert.Equal(t, 0, len(itx.Inputs))
+
+	_, err = nh.MakeMultiTransferTransaction(nil, nil)
+	assert.NotNil(t, err)
+}
+
+func TestNep5Helper_MakeMultiTransferTransaction_Gas(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("0xb9d7ea3062e6aeeb3e8ad9548220c4ba1361d263")
+	nh := NewNep5HelperFromClient(scriptHash, newTransferClientMock())
+	a, _ := helper.AddressToScriptHash("AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+	b, _ := helper.AddressToScriptHash("AdQk428wVzpkHTxc4MP5UMdsgNdrm36dyV")
+	d, _ := helper.AddressToScriptHash("AKeLhhHm4hEUfLWVBCYRNjio9xhGJAom5G")
+	e, _ := helper.AddressToScriptHash("AK2nJJpJr6o664CWJKi1QRXjqeic2zRp8y")
+	amount, _ := ParseTokenAmount("1", 0)
+
+	// d is not the payer, its transfer passes only if the test run has its witness
+	itx, err := nh.MakeMultiTransferTransaction([]TransferRequest{
+		{From: a, To: b, Amount: amount},
+		{From: d, To: b, Amount: amount},
+	}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, helper.Fixed8FromInt64(1), itx.Gas)
+	assert.Equal(t, 1, len(itx.Inputs))
+	assert.Equal(t, 0, len(itx.Outputs))
+
+	// a faulted test run is an error instead of free gas
+	_, err = nh.MakeMultiTransferTransaction([]TransferRequest{
+		{From: a, To: b, Amount: amount},
+		{From: e, To: b, Amount: amount},
+	}, nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,164 @@
+package nep5
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// TokenAmount is an amount of a NEP-5 token, Value is the integer amount in the smallest unit of the token,
+// so 1.5 of a token with 8 decimals is 150000000
+type TokenAmount struct {
+	Value    *big.Int
+	Decimals uint8
+}
+
+// NewTokenAmount returns an amount of value in the smallest unit of a token with decimals
+func NewTokenAmount(value *big.Int, decimals uint8) TokenAmount {
+	return TokenAmount{Value: new(big.Int).Set(value), Decimals: decimals}
+}
+
+// ParseTokenAmount parses a decimal string such as "1.5", an error is returned if it has more than decimals digits
+// after the point
+func ParseTokenAmount(s string, decimals uint8) (TokenAmount, error) {
+	str := strings.TrimSpace(s)
+	negative := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "-"), "+")
+	parts := strings.SplitN(str, ".", 2)
+	if len(parts) == 2 {
+		if len(parts[1]) > int(decimals) {
+			return TokenAmount{}, fmt.Errorf("amount %s has more than %d decimals", s, decimals)
+		}
+		parts[1] += strings.Repeat("0", int(decimals)-len(parts[1]))
+	} else {
+		parts = append(parts, strings.Repeat("0", int(decimals)))
+	}
+	digits := parts[0] + parts[1]
+	if len(parts[0]) == 0 || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return TokenAmount{}, fmt.Errorf("invalid amount %s", s)
+	}
+	value, _ := new(big.Int).SetString(digits, 10)
+	if negative {
+		value.Neg(value)
+	}
+	return TokenAmount{Value: value, Decimals: decimals}, nil
+}
+
+// String formats the amount as a decimal string without trailing zeros
+func (a TokenAmount) String() string {
+	value := a.value()
+	s := new(big.Int).Abs(value).String()
+	if len(s) <= int(a.Decimals) {
+		s = strings.Repeat("0", int(a.Decimals)-len(s)+1) + s
+	}
+	point := len(s) - int(a.Decimals)
+	result := s[:point]
+	if fraction := strings.TrimRight(s[point:], "0"); fraction != "" {
+		result += "." + fraction
+	}
+	if value.Sign() < 0 {
+		result = "-" + result
+	}
+	return result
+}
+
+// ToDecimals converts the amount to a token with decimals, an error is returned if the precision is lost
+func (a TokenAmount) ToDecimals(decimals uint8) (TokenAmount, error) {
+	value := a.value()
+	if decimals >= a.Decimals {
+		return TokenAmount{Value: new(big.Int).Mul(value, pow10(decimals-a.Decimals)), Decimals: decimals}, nil
+	}
+	q, r := new(big.Int).QuoRem(value, pow10(a.Decimals-decimals), new(big.Int))
+	if r.Sign() != 0 {
+		return TokenAmount{}, fmt.Errorf("amount %s has more than %d decimals", a.String(), decimals)
+	}
+	return TokenAmount{Value: q, Decimals: decimals}, nil
+}
+
+// Add returns a + b, the result has the larger decimals of the two
+func (a TokenAmount) Add(b TokenAmount) TokenAmount {
+	x, y := align(a, b)
+	return TokenAmount{Value: new(big.Int).Add(x.Value, y.Value), Decimals: x.Decimals}
+}
+
+// Sub returns a - b, the result has the larger decimals of the two
+func (a TokenAmount) Sub(b TokenAmount) TokenAmount {
+	x, y := align(a, b)
+	return TokenAmount{Value: new(big.Int).Sub(x.Value, y.Value), Decimals: x.Decimals}
+}
+
+// Cmp returns -1, 0 or 1 if a is less than, equal to or greater than b
+func (a TokenAmount) Cmp(b TokenAmount) int {
+	x, y := align(a, b)
+	return x.Value.Cmp(y.Value)
+}
+
+// Equal implements TokenAmount == operator.
+func (a TokenAmount) Equal(b TokenAmount) bool {
+	return a.Cmp(b) == 0
+}
+
+// GreaterThan implements TokenAmount > operator.
+func (a TokenAmount) GreaterThan(b TokenAmount) bool {
+	return a.Cmp(b) > 0
+}
+
+// LessThan implements TokenAmount < operator.
+func (a TokenAmount) LessThan(b TokenAmount) bool {
+	return a.Cmp(b) < 0
+}
+
+// Sign returns -1, 0 or 1 if the amount is negative, zero or positive
+func (a TokenAmount) Sign() int {
+	return a.value().Sign()
+}
+
+// MarshalJSON implements the json marshaller interface, the amount is a decimal string.
+func (a TokenAmount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"value":    a.String(),
+		"decimals": a.Decimals,
+	})
+}
+
+// UnmarshalJSON implements the json unmarshaller interface.
+func (a *TokenAmount) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Value    string `json:"value"`
+		Decimals uint8  `json:"decimals"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	amount, err := ParseTokenAmount(v.Value, v.Decimals)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// value returns the integer amount, which is zero for the zero TokenAmount
+func (a TokenAmount) value() *big.Int {
+	if a.Value == nil {
+		return new(big.Int)
+	}
+	return a.Value
+}
+
+// align converts a and b to the larger decimals of the two
+func align(a, b TokenAmount) (TokenAmount, TokenAmount) {
+	decimals := a.Decimals
+	if b.Decimals > decimals {
+		decimals = b.Decimals
+	}
+	// never fails since the decimals do not decrease
+	x, _ := a.ToDecimals(decimals)
+	y, _ := b.ToDecimals(decimals)
+	return x, y
+}
+
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
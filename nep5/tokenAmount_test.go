@@ -0,0 +1,87 @@
+package nep5
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokenAmount(t *testing.T) {
+	a, err := ParseTokenAmount("1.5", 18)
+	assert.Nil(t, err)
+	assert.Equal(t, "1500000000000000000", a.Value.String())
+	assert.Equal(t, uint8(18), a.Decimals)
+
+	a, err = ParseTokenAmount("-0.00000001", 8)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), a.Value.Int64())
+
+	a, err = ParseTokenAmount("42", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), a.Value.Int64())
+
+	_, err = ParseTokenAmount("0.001", 2)
+	assert.NotNil(t, err)
+	_, err = ParseTokenAmount("1.2.3", 8)
+	assert.NotNil(t, err)
+	_, err = ParseTokenAmount(".5", 8)
+	assert.NotNil(t, err)
+	_, err = ParseTokenAmount("1e5", 8)
+	assert.NotNil(t, err)
+}
+
+func TestTokenAmount_String(t *testing.T) {
+	supply, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.Equal(t, "123456789012.34567890123456789", NewTokenAmount(supply, 18).String())
+	assert.Equal(t, "0.000000000000000001", NewTokenAmount(big.NewInt(1), 18).String())
+	assert.Equal(t, "-1.5", NewTokenAmount(big.NewInt(-15), 1).String())
+	assert.Equal(t, "100", NewTokenAmount(big.NewInt(100), 0).String())
+	assert.Equal(t, "0", TokenAmount{}.String())
+}
+
+func TestTokenAmount_ToDecimals(t *testing.T) {
+	a, _ := ParseTokenAmount("1.5", 8)
+	b, err := a.ToDecimals(18)
+	assert.Nil(t, err)
+	assert.Equal(t, "1500000000000000000", b.Value.String())
+
+	c, err := b.ToDecimals(1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(15), c.Value.Int64())
+
+	_, err = b.ToDecimals(0)
+	assert.NotNil(t, err)
+}
+
+func TestTokenAmount_Arithmetic(t *testing.T) {
+	a, _ := ParseTokenAmount("1.5", 8)
+	b, _ := ParseTokenAmount("0.25", 18)
+
+	sum := a.Add(b)
+	assert.Equal(t, uint8(18), sum.Decimals)
+	assert.Equal(t, "1.75", sum.String())
+	assert.Equal(t, "1.25", a.Sub(b).String())
+	assert.Equal(t, "-1.25", b.Sub(a).String())
+
+	assert.True(t, a.GreaterThan(b))
+	assert.True(t, b.LessThan(a))
+	assert.False(t, a.Equal(b))
+	c, _ := ParseTokenAmount("1.50", 2)
+	assert.True(t, a.Equal(c))
+	assert.Equal(t, 0, TokenAmount{}.Sign())
+	assert.Equal(t, "1.5", a.String()) // the operands are not changed
+}
+
+func TestTokenAmount_JSON(t *testing.T) {
+	a, _ := ParseTokenAmount("1.5", 18)
+	b, err := json.Marshal(a)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"decimals":18,"value":"1.5"}`, string(b))
+
+	var c TokenAmount
+	assert.Nil(t, json.Unmarshal(b, &c))
+	assert.True(t, a.Equal(c))
+	assert.Equal(t, uint8(18), c.Decimals)
+}
@@ -0,0 +1,197 @@
+package nep5
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+)
+
+// TransferIndexer finds the transfers of NEP-5 tokens in the application logs of the blocks, so that the history
+// of an address is known without the RpcNep5Tracker plugin. The node must have the ApplicationLogs plugin.
+type TransferIndexer struct {
+	Client rpc.IRpcClient
+	Store  ITransferStore
+	// AssetHashes are the tokens to index, all the tokens are indexed if it is empty
+	AssetHashes []helper.UInt160
+}
+
+func NewTransferIndexer(client rpc.IRpcClient, store ITransferStore) *TransferIndexer {
+	return &TransferIndexer{
+		Client: client,
+		Store:  store,
+	}
+}
+
+// Sync indexes the blocks from the height of the store to the latest block of the node, and returns the new height
+func (x *TransferIndexer) Sync() (uint32, error) {
+	response := x.Client.GetBlockCount()
+	if response.HasError() {
+		return 0, response.GetError()
+	}
+	return x.SyncTo(uint32(response.Result))
+}
+
+// SyncTo indexes the blocks from the height of the store until height, and returns the new height of the store
+func (x *TransferIndexer) SyncTo(height uint32) (uint32, error) {
+	index, err := x.Store.Height()
+	if err != nil {
+		return 0, err
+	}
+	for ; index < height; index++ {
+		transfers, err := x.IndexBlock(index)
+		if err != nil {
+			return index, err
+		}
+		if err = x.Store.PutBlock(index, transfers); err != nil {
+			return index, err
+		}
+	}
+	return index, nil
+}
+
+// IndexBlock returns the transfers of the block at index without storing them. Only the executions which
+// end in HALT are taken, the notifications of a faulted execution have no effect.
+// NotifyIndex counts all the notifications of the transaction.
+func (x *TransferIndexer) IndexBlock(index uint32) ([]TransferEvent, error) {
+	response := x.Client.GetBlockByIndex(index)
+	if response.HasError() {
+		return nil, response.GetError()
+	}
+	b := response.Result
+	transfers := []TransferEvent{}
+	for _, t := range b.Tx {
+		if t.Type != "InvocationTransaction" {
+			continue
+		}
+		txHash, err := helper.UInt256FromString(t.Txid)
+		if err != nil {
+			return nil, err
+		}
+		logResponse := x.Client.GetApplicationLog(t.Txid)
+		if logResponse.HasError() {
+			return nil, fmt.Errorf("application log of %s: %w", t.Txid, logResponse.GetError())
+		}
+		notifyIndex := 0
+		for _, execution := range logResponse.Result.Executions {
+			if !strings.Contains(execution.VMState, "HALT") {
+				notifyIndex += len(execution.Notifications)
+				continue
+			}
+			for _, notification := range execution.Notifications {
+				// any contract can notify "transfer", the malformed ones are not NEP-5 transfers
+				e, err := DecodeTransfer(notification)
+				if err == nil && e != nil && x.indexes(e.AssetHash) {
+					e.TxHash = txHash
+					e.BlockIndex = index
+					e.Timestamp = uint32(b.Time)
+					e.NotifyIndex = notifyIndex
+					transfers = append(transfers, *e)
+				}
+				notifyIndex++
+			}
+		}
+	}
+	return transfers, nil
+}
+
+// History returns the transfers of address of the token in the order of the chain, all the tokens for a zero assetHash
+func (x *TransferIndexer) History(address helper.UInt160, assetHash helper.UInt160) ([]TransferEvent, error) {
+	return x.Store.GetTransfers(TransferFilter{Address: address, AssetHash: assetHash})
+}
+
+// BalanceAt reconstructs the balance of address of the token before the block at height from the transfers,
+// the balance is correct only if the store is indexed from the deployment of the token
+func (x *TransferIndexer) BalanceAt(address helper.UInt160, assetHash helper.UInt160, height uint32) (*big.Int, error) {
+	transfers, err := x.Store.GetTransfers(TransferFilter{Address: address, AssetHash: assetHash, EndBlock: height})
+	if err != nil {
+		return nil, err
+	}
+	if balance, ok := balances(address, transfers)[assetHash]; ok {
+		return balance, nil
+	}
+	return new(big.Int), nil
+}
+
+// Balances reconstructs the balances of address of all the tokens indexed, see BalanceAt
+func (x *TransferIndexer) Balances(address helper.UInt160) (map[helper.UInt160]*big.Int, error) {
+	transfers, err := x.History(address, helper.UInt160{})
+	if err != nil {
+		return nil, err
+	}
+	return balances(address, transfers), nil
+}
+
+func (x *TransferIndexer) indexes(assetHash helper.UInt160) bool {
+	if len(x.AssetHashes) == 0 {
+		return true
+	}
+	for _, h := range x.AssetHashes {
+		if h == assetHash {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeTransfer decodes a notification ["transfer", from, to, amount] of a NEP-5 token, nil is returned for the
+// other notifications. A null from or to is decoded as zero, which is minting or burning.
+func DecodeTransfer(n models.RpcNotification) (*TransferEvent, error) {
+	items, err := models.StackItem(n.State).AsArray()
+	if err != nil || len(items) != 4 {
+		return nil, nil
+	}
+	if name, err := items[0].AsString(); err != nil || name != "transfer" {
+		return nil, nil
+	}
+	assetHash, err := helper.UInt160FromString(n.Contract)
+	if err != nil {
+		return nil, err
+	}
+	from, err := transferAddress(items[1])
+	if err != nil {
+		return nil, err
+	}
+	to, err := transferAddress(items[2])
+	if err != nil {
+		return nil, err
+	}
+	amount, err := items[3].AsBigInt()
+	if err != nil {
+		return nil, err
+	}
+	return &TransferEvent{AssetHash: assetHash, From: from, To: to, Amount: amount}, nil
+}
+
+func transferAddress(item models.StackItem) (helper.UInt160, error) {
+	b, err := item.AsBytes()
+	if err != nil {
+		return helper.UInt160{}, err
+	}
+	if len(b) == 0 {
+		return helper.UInt160{}, nil
+	}
+	return helper.UInt160FromBytes(b)
+}
+
+// balances sums the amounts received minus the amounts sent by address of each token
+func balances(address helper.UInt160, transfers []TransferEvent) map[helper.UInt160]*big.Int {
+	result := make(map[helper.UInt160]*big.Int)
+	for _, t := range transfers {
+		balance, ok := result[t.AssetHash]
+		if !ok {
+			balance = new(big.Int)
+			result[t.AssetHash] = balance
+		}
+		if t.To == address {
+			balance.Add(balance, t.Amount)
+		}
+		if t.From == address {
+			balance.Sub(balance, t.Amount)
+		}
+	}
+	return result
+}
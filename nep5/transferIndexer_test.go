@@ -0,0 +1,212 @@
+package nep5
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testAsset      = "0xb9d7ea3062e6aeeb3e8ad9548220c4ba1361d263"
+	testOtherAsset = "0x9aff1e08aea2048a26a3d2ddbb3df495b932b1e7"
+	testTx0        = "0x0a99ebd286931375c2ec828603e88392e3a40e9cecd4b228bd6be206fdb21005"
+	testTx1        = "0x1a99ebd286931375c2ec828603e88392e3a40e9cecd4b228bd6be206fdb21005"
+	testTx2        = "0x2a99ebd286931375c2ec828603e88392e3a40e9cecd4b228bd6be206fdb21005"
+)
+
+func transferNotification(contract string, from []byte, to []byte, amount int64) models.RpcNotification {
+	return models.RpcNotification{
+		Contract: contract,
+		State: sc.ContractParameter{
+			Type: sc.Array,
+			Value: []sc.ContractParameter{
+				{Type: sc.ByteArray, Value: []byte("transfer")},
+				{Type: sc.ByteArray, Value: from},
+				{Type: sc.ByteArray, Value: to},
+				{Type: sc.Integer, Value: *big.NewInt(amount)},
+			},
+		},
+	}
+}
+
+func newIndexerClientMock(a, b helper.UInt160) *rpc.RpcClientMock {
+	clientMock := new(rpc.RpcClientMock)
+	clientMock.On("GetBlockCount").Return(rpc.GetBlockCountResponse{Result: 2})
+	clientMock.On("GetBlockByIndex", uint32(0)).Return(rpc.GetBlockResponse{
+		Result: models.RpcBlock{
+			RpcBlockHeader: models.RpcBlockHeader{Index: 0, Time: 1000},
+			Tx: []models.RpcTransaction{
+				{Txid: "0x3a99ebd286931375c2ec828603e88392e3a40e9cecd4b228bd6be206fdb21005", Type: "MinerTransaction"},
+				{Txid: testTx0, Type: "InvocationTransaction"},
+			},
+		},
+	})
+	clientMock.On("GetBlockByIndex", uint32(1)).Return(rpc.GetBlockResponse{
+		Result: models.RpcBlock{
+			RpcBlockHeader: models.RpcBlockHeader{Index: 1, Time: 1015},
+			Tx: []models.RpcTransaction{
+				{Txid: testTx1, Type: "InvocationTransaction"},
+				{Txid: testTx2, Type: "InvocationTransaction"},
+			},
+		},
+	})
+	// minting 100 to a
+	clientMock.On("GetApplicationLog", testTx0).Return(rpc.GetApplicationLogResponse{
+		Result: models.RpcApplicationLog{
+			TxId: testTx0,
+			Executions: []models.RpcExecution{
+				{
+					VMState: "HALT",
+					Notifications: []models.RpcNotification{
+						transferNotification(testAsset, []byte{}, a.Bytes(), 100),
+					},
+				},
+			},
+		},
+	})
+	// a sends 30 to b, and 5 of another token, the notification of another event is ignored
+	clientMock.On("GetApplicationLog", testTx1).Return(rpc.GetApplicationLogResponse{
+		Result: models.RpcApplicationLog{
+			TxId: testTx1,
+			Executions: []models.RpcExecution{
+				{
+					VMState: "HALT, BREAK",
+					Notifications: []models.RpcNotification{
+						{
+							Contract: testAsset,
+							State: sc.ContractParameter{
+								Type:  sc.Array,
+								Value: []sc.ContractParameter{{Type: sc.ByteArray, Value: []byte("approve")}},
+							},
+						},
+						transferNotification(testAsset, a.Bytes(), b.Bytes(), 30),
+						transferNotification(testOtherAsset, a.Bytes(), b.Bytes(), 5),
+					},
+				},
+			},
+		},
+	})
+	// the faulted transfer of b has no effect
+	clientMock.On("GetApplicationLog", testTx2).Return(rpc.GetApplicationLogResponse{
+		Result: models.RpcApplicationLog{
+			TxId: testTx2,
+			Executions: []models.RpcExecution{
+				{
+					VMState: "FAULT, BREAK",
+					Notifications: []models.RpcNotification{
+						transferNotification(testAsset, b.Bytes(), a.Bytes(), 30),
+					},
+				},
+			},
+		},
+	})
+	return clientMock
+}
+
+func TestDecodeTransfer(t *testing.T) {
+	asset, _ := helper.UInt160FromString(testAsset)
+	a, _ := helper.AddressToScriptHash("AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+
+	e, err := DecodeTransfer(transferNotification(testAsset, a.Bytes(), []byte{}, 7))
+	assert.Nil(t, err)
+	assert.Equal(t, asset, e.AssetHash)
+	assert.Equal(t, a, e.From)
+	assert.Equal(t, helper.UInt160{}, e.To)
+	assert.Equal(t, big.NewInt(7), e.Amount)
+
+	e, err = DecodeTransfer(models.RpcNotification{
+		Contract: testAsset,
+		State:    sc.ContractParameter{Type: sc.ByteArray, Value: []byte("transfer")},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, e)
+
+	_, err = DecodeTransfer(transferNotification(testAsset, []byte{1, 2, 3}, a.Bytes(), 7))
+	assert.NotNil(t, err)
+}
+
+func TestTransferIndexer_Sync(t *testing.T) {
+	asset, _ := helper.UInt160FromString(testAsset)
+	otherAsset, _ := helper.UInt160FromString(testOtherAsset)
+	a, _ := helper.AddressToScriptHash("AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+	b, _ := helper.AddressToScriptHash("AKeLhhHm4hEUfLWVBCYRNjio9xhGJAom5G")
+	x := NewTransferIndexer(newIndexerClientMock(a, b), NewMemoryTransferStore(0))
+
+	height, err := x.Sync()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), height)
+
+	history, err := x.History(b, asset)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(history))
+	tx1, _ := helper.UInt256FromString(testTx1)
+	assert.Equal(t, tx1, history[0].TxHash)
+	assert.Equal(t, uint32(1), history[0].BlockIndex)
+	assert.Equal(t, uint32(1015), history[0].Timestamp)
+	assert.Equal(t, 1, history[0].NotifyIndex)
+
+	history, err = x.History(a, helper.UInt160{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(history))
+
+	balance, err := x.BalanceAt(a, asset, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(100), balance)
+	balance, err = x.BalanceAt(a, asset, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(70), balance)
+	balance, err = x.BalanceAt(b, asset, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(0), balance)
+
+	balances, err := x.Balances(b)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(30), balances[asset])
+	assert.Equal(t, big.NewInt(5), balances[otherAsset])
+
+	// the store is up to date
+	height, err = x.Sync()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), height)
+}
+
+func TestTransferIndexer_AssetHashes(t *testing.T) {
+	otherAsset, _ := helper.UInt160FromString(testOtherAsset)
+	a, _ := helper.AddressToScriptHash("AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+	b, _ := helper.AddressToScriptHash("AKeLhhHm4hEUfLWVBCYRNjio9xhGJAom5G")
+	x := NewTransferIndexer(newIndexerClientMock(a, b), NewMemoryTransferStore(0))
+	x.AssetHashes = []helper.UInt160{otherAsset}
+
+	transfers, err := x.IndexBlock(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(transfers))
+	assert.Equal(t, otherAsset, transfers[0].AssetHash)
+	assert.Equal(t, 2, transfers[0].NotifyIndex)
+}
+
+func TestMemoryTransferStore(t *testing.T) {
+	a := helper.UInt160{1}
+	b := helper.UInt160{2}
+	s := NewMemoryTransferStore(10)
+
+	assert.NotNil(t, s.PutBlock(11, nil))
+	assert.Nil(t, s.PutBlock(10, []TransferEvent{
+		{From: a, To: a, Amount: big.NewInt(1), BlockIndex: 10},
+		{From: a, To: b, Amount: big.NewInt(2), BlockIndex: 10},
+	}))
+	assert.Nil(t, s.PutBlock(11, []TransferEvent{{From: b, To: a, Amount: big.NewInt(3), BlockIndex: 11}}))
+	height, _ := s.Height()
+	assert.Equal(t, uint32(12), height)
+
+	transfers, _ := s.GetTransfers(TransferFilter{Address: a})
+	assert.Equal(t, 3, len(transfers))
+	transfers, _ = s.GetTransfers(TransferFilter{Address: b, EndBlock: 11})
+	assert.Equal(t, 1, len(transfers))
+	transfers, _ = s.GetTransfers(TransferFilter{StartBlock: 11})
+	assert.Equal(t, big.NewInt(3), transfers[0].Amount)
+}
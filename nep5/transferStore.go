@@ -0,0 +1,112 @@
+package nep5
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+)
+
+// TransferEvent is a "transfer" notification of a NEP-5 token in an application log
+type TransferEvent struct {
+	AssetHash   helper.UInt160 // the script hash of the token
+	From        helper.UInt160 // zero when the tokens are minted
+	To          helper.UInt160 // zero when the tokens are burnt
+	Amount      *big.Int       // in the smallest unit of the token
+	TxHash      helper.UInt256
+	BlockIndex  uint32
+	Timestamp   uint32 // the time of the block
+	NotifyIndex int    // the index of the notification in the transaction
+}
+
+// TransferFilter selects the transfers returned by an ITransferStore, the zero values select all
+type TransferFilter struct {
+	Address   helper.UInt160 // the sender or the receiver
+	AssetHash helper.UInt160
+	// the transfers of the blocks from StartBlock until EndBlock are returned, EndBlock is excluded and zero for no limit
+	StartBlock uint32
+	EndBlock   uint32
+}
+
+// Match returns true if the filter selects e
+func (f TransferFilter) Match(e TransferEvent) bool {
+	if f.Address != (helper.UInt160{}) && e.From != f.Address && e.To != f.Address {
+		return false
+	}
+	if f.AssetHash != (helper.UInt160{}) && e.AssetHash != f.AssetHash {
+		return false
+	}
+	return e.BlockIndex >= f.StartBlock && (f.EndBlock == 0 || e.BlockIndex < f.EndBlock)
+}
+
+// ITransferStore persists the transfers found by a TransferIndexer, which indexes the blocks in order
+type ITransferStore interface {
+	// Height returns the number of the blocks indexed, which is the index of the next block to index
+	Height() (uint32, error)
+	// PutBlock stores the transfers of the block at index, which must be Height, and increases Height by one
+	PutBlock(index uint32, transfers []TransferEvent) error
+	// GetTransfers returns the transfers selected by filter in the order of the chain
+	GetTransfers(filter TransferFilter) ([]TransferEvent, error)
+}
+
+// MemoryTransferStore keeps the transfers in memory
+type MemoryTransferStore struct {
+	mu        sync.RWMutex
+	height    uint32
+	transfers []TransferEvent
+	// indexes of the transfers by address, an address sending to itself is indexed once
+	byAddress map[helper.UInt160][]int
+}
+
+// NewMemoryTransferStore returns a store which starts indexing from the block at height
+func NewMemoryTransferStore(height uint32) *MemoryTransferStore {
+	return &MemoryTransferStore{
+		height:    height,
+		byAddress: make(map[helper.UInt160][]int),
+	}
+}
+
+func (s *MemoryTransferStore) Height() (uint32, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.height, nil
+}
+
+func (s *MemoryTransferStore) PutBlock(index uint32, transfers []TransferEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index != s.height {
+		return fmt.Errorf("block %d does not follow the height %d", index, s.height)
+	}
+	for _, t := range transfers {
+		i := len(s.transfers)
+		s.transfers = append(s.transfers, t)
+		s.byAddress[t.From] = append(s.byAddress[t.From], i)
+		if t.To != t.From {
+			s.byAddress[t.To] = append(s.byAddress[t.To], i)
+		}
+	}
+	s.height++
+	return nil
+}
+
+func (s *MemoryTransferStore) GetTransfers(filter TransferFilter) ([]TransferEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := []TransferEvent{}
+	if filter.Address != (helper.UInt160{}) {
+		for _, i := range s.byAddress[filter.Address] {
+			if filter.Match(s.transfers[i]) {
+				result = append(result, s.transfers[i])
+			}
+		}
+		return result, nil
+	}
+	for _, t := range s.transfers {
+		if filter.Match(t) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,211 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Batch queues json-rpc calls and sends them to the node in a single http request,
+// as a json-rpc 2.0 array. Every queued call gets its own response struct, which
+// is filled when Send returns.
+//
+//	batch := client.NewBatch()
+//	r1 := batch.GetBlockByIndex(100)
+//	r2 := batch.GetApplicationLog(txId)
+//	err := batch.Send()
+type Batch struct {
+	client *RpcClient
+	calls  []batchCall
+}
+
+type batchCall struct {
+	request RpcRequest
+	out     interface{}
+}
+
+// errorSetter is implemented by all the responses through the embedded ErrorResponse
+type errorSetter interface {
+	setError(err error)
+}
+
+func (r *ErrorResponse) setError(err error) {
+	*r = NewErrorResponse(err)
+}
+
+// NewBatch creates an empty Batch sent through this client
+func (n *RpcClient) NewBatch() *Batch {
+	return &Batch{client: n}
+}
+
+// Len returns the number of queued calls
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Add queues a call of method, the result will be decoded into out, which must be
+// a pointer to one of the response structs of this package
+func (b *Batch) Add(method string, params []interface{}, out interface{}) {
+	request := NewRequest(method, params)
+	request.ID = len(b.calls) + 1 // ids must be unique inside a batch
+	b.calls = append(b.calls, batchCall{request: request, out: out})
+}
+
+// Send posts all the queued calls and dispatches the replies by id. The returned error
+// is only for failures of the whole batch, in which case it is also set on every
+// response. Errors of a single call are in its own response. The queue is emptied.
+func (b *Batch) Send() error {
+	calls := b.calls
+	b.calls = nil
+	if len(calls) == 0 {
+		return nil
+	}
+	requests := make([]RpcRequest, len(calls))
+	for i, c := range calls {
+		requests[i] = c.request
+	}
+	var raw json.RawMessage
+	err := b.client.post("batch", requests, &raw)
+	if err == nil {
+		err = dispatch(calls, raw)
+	}
+	if err != nil {
+		for _, c := range calls {
+			setError(c.out, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func dispatch(calls []batchCall, raw json.RawMessage) error {
+	var replies []json.RawMessage
+	if err := json.Unmarshal(raw, &replies); err != nil {
+		// the node rejected the whole batch with a single response
+		single := ErrorResponse{}
+		if e := json.Unmarshal(raw, &single); e == nil && single.HasError() {
+			return fmt.Errorf("batch rejected: %w", single.GetError())
+		}
+		return err
+	}
+	byId := make(map[int]json.RawMessage, len(replies))
+	for _, reply := range replies {
+		var r RpcResponse
+		if err := json.Unmarshal(reply, &r); err != nil {
+			return err
+		}
+		byId[r.ID] = reply
+	}
+	for _, c := range calls {
+		reply, ok := byId[c.request.ID]
+		if !ok {
+			setError(c.out, fmt.Errorf("no response for %s with id %d", c.request.Method, c.request.ID))
+			continue
+		}
+		if err := json.Unmarshal(reply, c.out); err != nil {
+			setError(c.out, err)
+		}
+	}
+	return nil
+}
+
+func setError(out interface{}, err error) {
+	if s, ok := out.(errorSetter); ok {
+		s.setError(err)
+	}
+}
+
+func (b *Batch) GetAccountState(address string) *GetAccountStateResponse {
+	response := &GetAccountStateResponse{}
+	b.Add("getaccountstate", []interface{}{address}, response)
+	return response
+}
+
+func (b *Batch) GetApplicationLog(txId string) *GetApplicationLogResponse {
+	response := &GetApplicationLogResponse{}
+	b.Add("getapplicationlog", []interface{}{txId}, response)
+	return response
+}
+
+func (b *Batch) GetAssetState(assetId string) *GetAssetStateResponse {
+	response := &GetAssetStateResponse{}
+	b.Add("getassetstate", []interface{}{assetId}, response)
+	return response
+}
+
+func (b *Batch) GetBlockByHash(blockHash string) *GetBlockResponse {
+	response := &GetBlockResponse{}
+	b.Add("getblock", []interface{}{blockHash, 1}, response)
+	return response
+}
+
+func (b *Batch) GetBlockByIndex(index uint32) *GetBlockResponse {
+	response := &GetBlockResponse{}
+	b.Add("getblock", []interface{}{index, 1}, response)
+	return response
+}
+
+func (b *Batch) GetBlockCount() *GetBlockCountResponse {
+	response := &GetBlockCountResponse{}
+	b.Add("getblockcount", []interface{}{}, response)
+	return response
+}
+
+func (b *Batch) GetBlockHeaderByHash(blockHash string) *GetBlockHeaderResponse {
+	response := &GetBlockHeaderResponse{}
+	b.Add("getblockheader", []interface{}{blockHash, 1}, response)
+	return response
+}
+
+func (b *Batch) GetBlockHash(index uint32) *GetBlockHashResponse {
+	response := &GetBlockHashResponse{}
+	b.Add("getblockhash", []interface{}{index}, response)
+	return response
+}
+
+func (b *Batch) GetContractState(scriptHash string) *GetContractStateResponse {
+	response := &GetContractStateResponse{}
+	b.Add("getcontractstate", []interface{}{scriptHash}, response)
+	return response
+}
+
+func (b *Batch) GetRawTransaction(txid string) *GetRawTransactionResponse {
+	response := &GetRawTransactionResponse{}
+	b.Add("getrawtransaction", []interface{}{txid, 1}, response)
+	return response
+}
+
+func (b *Batch) GetStorage(scripthash string, key string) *GetStorageResponse {
+	response := &GetStorageResponse{}
+	b.Add("getstorage", []interface{}{scripthash, key}, response)
+	return response
+}
+
+func (b *Batch) GetTransactionHeight(txid string) *GetTransactionHeightResponse {
+	response := &GetTransactionHeightResponse{}
+	b.Add("gettransactionheight", []interface{}{txid}, response)
+	return response
+}
+
+func (b *Batch) GetTxOut(txid string, index int) *GetTxOutResponse {
+	response := &GetTxOutResponse{}
+	b.Add("gettxout", []interface{}{txid, index}, response)
+	return response
+}
+
+func (b *Batch) GetUnspents(address string) *GetUnspentsResponse {
+	response := &GetUnspentsResponse{}
+	b.Add("getunspents", []interface{}{address}, response)
+	return response
+}
+
+func (b *Batch) InvokeScript(scriptInHex string, checkWitnessHashes string) *InvokeScriptResponse {
+	response := &InvokeScriptResponse{}
+	b.Add("invokescript", []interface{}{scriptInHex, checkWitnessHashes}, response)
+	return response
+}
+
+func (b *Batch) GetStateRootByIndex(blockHeight uint32) *StateRootResponse {
+	response := &StateRootResponse{}
+	b.Add("getstateroot", []interface{}{blockHeight}, response)
+	return response
+}
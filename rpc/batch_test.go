@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBatch_Send(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	var ids []int
+	client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		var requests []RpcRequest
+		_ = json.NewDecoder(req.Body).Decode(&requests)
+		for _, r := range requests {
+			ids = append(ids, r.ID)
+		}
+		return len(requests) == 3
+	})).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`[
+			{
+				"jsonrpc": "2.0",
+				"id": 3,
+				"error": {
+					"code": -100,
+					"message": "Unknown transaction"
+				}
+			},
+			{
+				"jsonrpc": "2.0",
+				"id": 1,
+				"result": 2023
+			},
+			{
+				"jsonrpc": "2.0",
+				"id": 2,
+				"result": {
+					"hash": "0xd42561e3d30e15be6400b6df2f328e02d2bf6354c41dce433bc57687c82144bf",
+					"index": 0,
+					"tx": []
+				}
+			}
+		]`))),
+	}, nil)
+
+	batch := rpc.NewBatch()
+	r1 := batch.GetBlockCount()
+	r2 := batch.GetBlockByIndex(0)
+	r3 := batch.GetRawTransaction("0x00")
+	assert.Equal(t, 3, batch.Len())
+	err := batch.Send()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, batch.Len())
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	assert.False(t, r1.HasError())
+	assert.Equal(t, 2023, r1.Result)
+	assert.False(t, r2.HasError())
+	assert.Equal(t, "0xd42561e3d30e15be6400b6df2f328e02d2bf6354c41dce433bc57687c82144bf", r2.Result.Hash)
+	assert.True(t, r3.HasError())
+	assert.Equal(t, -100, r3.Error.Code)
+	assert.Equal(t, "Unknown transaction", r3.Error.Message)
+}
+
+func TestBatch_Send_MissingReply(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`[
+			{
+				"jsonrpc": "2.0",
+				"id": 1,
+				"result": 2023
+			}
+		]`))),
+	}, nil)
+
+	batch := rpc.NewBatch()
+	r1 := batch.GetBlockCount()
+	r2 := batch.GetBlockHash(1)
+	err := batch.Send()
+	assert.Nil(t, err)
+	assert.False(t, r1.HasError())
+	assert.True(t, r2.HasError())
+	assert.NotNil(t, r2.Err)
+}
+
+func TestBatch_Send_Failed(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return((*http.Response)(nil), fmt.Errorf("connection refused"))
+
+	batch := rpc.NewBatch()
+	r1 := batch.GetBlockCount()
+	r2 := batch.GetApplicationLog("0x00")
+	err := batch.Send()
+	assert.NotNil(t, err)
+	assert.Equal(t, err, r1.Err)
+	assert.Equal(t, err, r2.Err)
+}
+
+func TestBatch_Send_Rejected(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": null,
+			"error": {"code": -32600, "message": "Invalid Request"}
+		}`))),
+	}, nil)
+
+	batch := rpc.NewBatch()
+	r1 := batch.GetBlockCount()
+	err := batch.Send()
+	assert.True(t, errors.Is(err, ErrInvalidRequest))
+	assert.True(t, errors.Is(r1.GetError(), ErrInvalidRequest))
+}
@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by a neo node, matched by RpcError through errors.Is
+var (
+	ErrParse             = errors.New("parse error")                                   // -32700
+	ErrInvalidRequest    = errors.New("invalid request")                               // -32600
+	ErrMethodNotFound    = errors.New("method not found")                              // -32601
+	ErrInvalidParams     = errors.New("invalid params")                                // -32602
+	ErrInternal          = errors.New("internal error")                                // -32603
+	ErrUnknownItem       = errors.New("unknown block, transaction, asset or contract") // -100
+	ErrInsufficientFunds = errors.New("insufficient funds")                            // -300
+	ErrAccessDenied      = errors.New("access denied, no wallet opened")               // -400
+	ErrRelayFailed       = errors.New("relay failed")                                  // -500
+	ErrAlreadyExists     = errors.New("block or transaction already exists")           // -501
+	ErrMemPoolFull       = errors.New("memory pool is full")                           // -502
+	ErrUnableToVerify    = errors.New("block or transaction cannot be verified")       // -503
+	ErrInvalid           = errors.New("block or transaction validation failed")        // -504
+	ErrPolicyFail        = errors.New("transaction rejected by a policy filter")       // -505
+)
+
+var codeErrors = map[int]error{
+	-32700: ErrParse,
+	-32600: ErrInvalidRequest,
+	-32601: ErrMethodNotFound,
+	-32602: ErrInvalidParams,
+	-32603: ErrInternal,
+	-100:   ErrUnknownItem,
+	-300:   ErrInsufficientFunds,
+	-400:   ErrAccessDenied,
+	-500:   ErrRelayFailed,
+	-501:   ErrAlreadyExists,
+	-502:   ErrMemPoolFull,
+	-503:   ErrUnableToVerify,
+	-504:   ErrInvalid,
+	-505:   ErrPolicyFail,
+}
+
+// Error implements the error interface, so an error replied by the node can be returned as is
+func (e *RpcError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// Is makes errors.Is(err, ErrAlreadyExists) work for the known error codes
+func (e *RpcError) Is(target error) bool {
+	sentinel, ok := codeErrors[e.Code]
+	return ok && sentinel == target
+}
+
+// TransportError is returned when the request cannot be sent or the reply cannot be read
+type TransportError struct {
+	Method string
+	Err    error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("rpc call %s failed: %v", e.Method, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned when the reply of the node is not the expected json
+type DecodeError struct {
+	Method string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("rpc call %s returned an invalid reply: %v", e.Method, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// ContextError is returned when a call is aborted by its context,
+// either cancelled or past its deadline. It is never sent by the node,
+// so it can be told apart from a json-rpc error in ErrorResponse.Error.
+type ContextError struct {
+	Method string
+	Err    error // context.Canceled or context.DeadlineExceeded
+}
+
+func (e *ContextError) Error() string {
+	return fmt.Sprintf("rpc call %s aborted: %v", e.Method, e.Err)
+}
+
+// Unwrap makes errors.Is(err, context.Canceled) work
+func (e *ContextError) Unwrap() error {
+	return e.Err
+}
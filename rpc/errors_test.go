@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRpcError_Is(t *testing.T) {
+	var err error = &RpcError{Code: -501, Message: "Block or transaction already exists and cannot be sent repeatedly."}
+	assert.True(t, errors.Is(err, ErrAlreadyExists))
+	assert.False(t, errors.Is(err, ErrInvalid))
+
+	wrapped := fmt.Errorf("send tx: %w", err)
+	assert.True(t, errors.Is(wrapped, ErrAlreadyExists))
+	var re *RpcError
+	assert.True(t, errors.As(wrapped, &re))
+	assert.Equal(t, -501, re.Code)
+
+	err = &RpcError{Code: -1, Message: "unknown"}
+	assert.False(t, errors.Is(err, ErrAlreadyExists))
+}
+
+func TestErrorResponse_GetError(t *testing.T) {
+	response := SendRawTransactionResponse{Result: true}
+	assert.Nil(t, response.GetError())
+	assert.False(t, response.HasError())
+
+	// an error without a message is still an error
+	response = SendRawTransactionResponse{
+		ErrorResponse: ErrorResponse{Error: RpcError{Code: -500}},
+	}
+	assert.NotNil(t, response.GetError())
+	assert.True(t, response.HasError())
+
+	response = SendRawTransactionResponse{
+		ErrorResponse: ErrorResponse{Error: RpcError{Code: -502, Message: "Out of memory"}},
+	}
+	err := response.GetError()
+	assert.True(t, errors.Is(err, ErrMemPoolFull))
+	assert.Equal(t, "rpc error -502: Out of memory", err.Error())
+
+	cause := &TransportError{Method: "sendrawtransaction", Err: fmt.Errorf("connection refused")}
+	response = SendRawTransactionResponse{ErrorResponse: NewErrorResponse(cause)}
+	assert.Equal(t, cause, response.GetError())
+}
+
+func TestRpcClient_TransportError(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	cause := fmt.Errorf("connection refused")
+	client.On("Do", mock.Anything).Return((*http.Response)(nil), cause)
+
+	response := rpc.GetBlockCount()
+	assert.True(t, response.HasError())
+	var te *TransportError
+	assert.True(t, errors.As(response.GetError(), &te))
+	assert.Equal(t, "getblockcount", te.Method)
+	assert.True(t, errors.Is(response.GetError(), cause))
+}
+
+func TestRpcClient_DecodeError(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`<html>502 Bad Gateway</html>`))),
+	}, nil)
+
+	response := rpc.GetBlockCount()
+	assert.True(t, response.HasError())
+	var de *DecodeError
+	assert.True(t, errors.As(response.GetError(), &de))
+	assert.Equal(t, "getblockcount", de.Method)
+}
+
+func TestRpcClient_NodeError(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"error": {
+				"code": -100,
+				"message": "Unknown transaction"
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.GetRawTransaction("0x00")
+	assert.True(t, response.HasError())
+	assert.Nil(t, response.Err)
+	assert.True(t, errors.Is(response.GetError(), ErrUnknownItem))
+}
@@ -42,3 +42,23 @@ type IRpcClient interface {
 	SubmitBlock(s string) SubmitBlockResponse
 	ValidateAddress(s string) ValidateAddressResponse
 }
+
+// IWitnessInvoker is implemented by the clients which test run a script with many check-witness hashes,
+// RpcClient, RpcClientPool and RpcClientMock implement it. It is not a part of IRpcClient so that the
+// existing implementations of IRpcClient still work, see InvokeScriptWithWitnesses.
+type IWitnessInvoker interface {
+	InvokeScriptWithWitnesses(s string, hashes []string) InvokeScriptResponse
+}
+
+// InvokeScriptWithWitnesses test runs the script by client with all of checkWitnessHashes if it is an
+// IWitnessInvoker, otherwise by InvokeScript with the first hash only
+func InvokeScriptWithWitnesses(client IRpcClient, scriptInHex string, checkWitnessHashes []string) InvokeScriptResponse {
+	if c, ok := client.(IWitnessInvoker); ok {
+		return c.InvokeScriptWithWitnesses(scriptInHex, checkWitnessHashes)
+	}
+	hash := ""
+	if len(checkWitnessHashes) > 0 {
+		hash = checkWitnessHashes[0]
+	}
+	return client.InvokeScript(scriptInHex, hash)
+}
@@ -1,5 +1,7 @@
 package models
 
+import "github.com/joeqian10/neo-gogogo/sc"
+
 type RpcApplicationLog struct {
 	TxId       string         `json:"txid"`
 	Executions []RpcExecution `json:"executions"`
@@ -10,21 +12,11 @@ type RpcExecution struct {
 	Contract      string                 `json:"contract"`
 	VMState       string                 `json:"vmstate"`
 	GasConsumed   string                 `json:"gas_consumed"`
-	Stack         []RpcContractParameter `json:"stack"`
+	Stack         []sc.ContractParameter `json:"stack"`
 	Notifications []RpcNotification      `json:"notifications"`
 }
 
-type RpcContractParameter struct {
-	Type  string `json:"type"`
-	Value string `json:"value"`
-}
-
-type RpcState struct {
-	Type  string                 `json:"type"`
-	Value []RpcContractParameter `json:"value"`
-}
-
 type RpcNotification struct {
-	Contract string   `json:"contract"`
-	State    RpcState `json:"state"`
+	Contract string               `json:"contract"`
+	State    sc.ContractParameter `json:"state"`
 }
@@ -1,18 +1,20 @@
 package models
 
+import "github.com/joeqian10/neo-gogogo/helper"
+
 type RpcClaimable struct {
-	Claimables []Claimable `json:"claimable"`
-	Address    string      `json:"address"`
-	Unclaimed  float64     `json:"unclaimed"`
+	Claimables []Claimable   `json:"claimable"`
+	Address    string        `json:"address"`
+	Unclaimed  helper.Fixed8 `json:"unclaimed"`
 }
 
 type Claimable struct {
-	TxId        string  `json:"txid"`
-	N           int     `json:"n"`
-	Value       int     `json:"value"`
-	StartHeight int     `json:"start_height"`
-	EndHeight   int     `json:"end_height"`
-	Generated   float64 `json:"generated"`
-	SysFee      float64 `json:"sys_fee"`
-	Unclaimed   float64 `json:"unclaimed"`
+	TxId        string        `json:"txid"`
+	N           int           `json:"n"`
+	Value       int           `json:"value"`
+	StartHeight int           `json:"start_height"`
+	EndHeight   int           `json:"end_height"`
+	Generated   helper.Fixed8 `json:"generated"`
+	SysFee      helper.Fixed8 `json:"sys_fee"`
+	Unclaimed   helper.Fixed8 `json:"unclaimed"`
 }
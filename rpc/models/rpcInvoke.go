@@ -1,34 +1,269 @@
 package models
 
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/sc"
+)
+
+// MaxSizeForBigInteger is the max size of a byte array which can be converted to an integer by the NeoVM
+const MaxSizeForBigInteger = 32
+
 type InvokeResult struct {
-	Script      string        `json:"script"`
-	State       string        `json:"state"`
-	GasConsumed string        `json:"gas_consumed"`
-	Stack       []InvokeStack `json:"stack"`
-	Tx          string        `json:"tx"`
-}
-
-type InvokeStack struct {
-	Type  string      `json:"type"`
-	Value interface{} `json:"value"`
-}
-
-// Convert converts interface{} "Value" to string or []InvokeStack depending on the "Type"
-func (s *InvokeStack) Convert()  {
-	if s.Type != "Array" {
-		s.Value = s.Value.(string)
-	} else {
-		vs := s.Value.([]interface{})
-		result := make([]InvokeStack, len(vs))
-		for i, v := range vs {
-			m := v.(map[string]interface{})
-			s2 := InvokeStack{
-				Type:  m["type"].(string),
-				Value: m["value"],
+	Script      string                 `json:"script"`
+	State       string                 `json:"state"`
+	GasConsumed string                 `json:"gas_consumed"`
+	Stack       []sc.ContractParameter `json:"stack"`
+	Tx          string                 `json:"tx"`
+}
+
+// StackItem is an item of the result stack, it is converted to the other types the same as the NeoVM
+type StackItem sc.ContractParameter
+
+// Item returns the item at index of the stack, an error is returned if the engine faulted
+func (r InvokeResult) Item(index int) (StackItem, error) {
+	if strings.Contains(r.State, "FAULT") {
+		return StackItem{}, fmt.Errorf("engine faulted")
+	}
+	if len(r.Stack) == 0 {
+		return StackItem{}, fmt.Errorf("no stack result returned")
+	}
+	if index < 0 || index >= len(r.Stack) {
+		return StackItem{}, fmt.Errorf("stack index %d out of range", index)
+	}
+	return StackItem(r.Stack[index]), nil
+}
+
+// AsBigInt converts the first item of the stack to an integer
+func (r InvokeResult) AsBigInt() (*big.Int, error) {
+	item, err := r.Item(0)
+	if err != nil {
+		return nil, err
+	}
+	return item.AsBigInt()
+}
+
+// AsBool converts the first item of the stack to a boolean
+func (r InvokeResult) AsBool() (bool, error) {
+	item, err := r.Item(0)
+	if err != nil {
+		return false, err
+	}
+	return item.AsBool()
+}
+
+// AsBytes converts the first item of the stack to a byte array
+func (r InvokeResult) AsBytes() ([]byte, error) {
+	item, err := r.Item(0)
+	if err != nil {
+		return nil, err
+	}
+	return item.AsBytes()
+}
+
+// AsString converts the first item of the stack to an utf8 string
+func (r InvokeResult) AsString() (string, error) {
+	item, err := r.Item(0)
+	if err != nil {
+		return "", err
+	}
+	return item.AsString()
+}
+
+// AsUInt160 converts the first item of the stack to a script hash
+func (r InvokeResult) AsUInt160() (helper.UInt160, error) {
+	item, err := r.Item(0)
+	if err != nil {
+		return helper.UInt160{}, err
+	}
+	return item.AsUInt160()
+}
+
+// AsArray returns the items of the array at the top of the stack
+func (r InvokeResult) AsArray() ([]StackItem, error) {
+	item, err := r.Item(0)
+	if err != nil {
+		return nil, err
+	}
+	return item.AsArray()
+}
+
+// AsMap returns the pairs of the map at the top of the stack
+func (r InvokeResult) AsMap() (map[string]StackItem, error) {
+	item, err := r.Item(0)
+	if err != nil {
+		return nil, err
+	}
+	return item.AsMap()
+}
+
+// AsBigInt converts a ByteArray of at most 32 bytes, an Integer or a Boolean to an integer
+func (s StackItem) AsBigInt() (*big.Int, error) {
+	switch s.Type {
+	case sc.Integer:
+		switch i := s.Value.(type) {
+		case big.Int:
+			return new(big.Int).Set(&i), nil
+		case *big.Int:
+			if i != nil {
+				return new(big.Int).Set(i), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid Integer value %T", s.Value)
+	case sc.Boolean:
+		b, err := s.AsBool()
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return big.NewInt(1), nil
+		}
+		return big.NewInt(0), nil
+	case sc.Array, sc.Map, sc.InteropInterface:
+		return nil, fmt.Errorf("%s can not be converted to an integer", s.Type.String())
+	default:
+		b, err := s.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > MaxSizeForBigInteger {
+			return nil, fmt.Errorf("byte array of size %d is too long for an integer", len(b))
+		}
+		return helper.BigIntFromNeoBytes(b), nil
+	}
+}
+
+// AsBool is false for an empty or all zero byte array and a zero integer, an Array, a Map and an InteropInterface
+// are true unless they are null
+func (s StackItem) AsBool() (bool, error) {
+	switch s.Type {
+	case sc.Boolean:
+		b, ok := s.Value.(bool)
+		if !ok {
+			return false, fmt.Errorf("invalid Boolean value %T", s.Value)
+		}
+		return b, nil
+	case sc.Integer:
+		i, err := s.AsBigInt()
+		if err != nil {
+			return false, err
+		}
+		return i.Sign() != 0, nil
+	case sc.Array, sc.Map, sc.InteropInterface:
+		return s.Value != nil, nil
+	default:
+		b, err := s.AsBytes()
+		if err != nil {
+			return false, err
+		}
+		for _, v := range b {
+			if v != 0 {
+				return true, nil
 			}
-			s2.Convert()
-			result[i] = s2
 		}
-		s.Value = result
+		return false, nil
+	}
+}
+
+// AsBytes converts a primitive item to a byte array, the hashes are little endian
+func (s StackItem) AsBytes() ([]byte, error) {
+	switch s.Type {
+	case sc.ByteArray, sc.Signature, sc.PublicKey, sc.Hash160, sc.Hash256:
+		b, ok := s.Value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value %T", s.Type.String(), s.Value)
+		}
+		return b, nil
+	case sc.String:
+		str, ok := s.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid String value %T", s.Value)
+		}
+		return []byte(str), nil
+	case sc.Integer:
+		i, err := s.AsBigInt()
+		if err != nil {
+			return nil, err
+		}
+		return helper.BigIntToNeoBytes(i), nil
+	case sc.Boolean:
+		b, err := s.AsBool()
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return []byte{1}, nil
+		}
+		return []byte{}, nil
+	default:
+		return nil, fmt.Errorf("%s can not be converted to a byte array", s.Type.String())
+	}
+}
+
+// AsString converts the byte array of the item to an utf8 string
+func (s StackItem) AsString() (string, error) {
+	if s.Type == sc.String {
+		str, ok := s.Value.(string)
+		if !ok {
+			return "", fmt.Errorf("invalid String value %T", s.Value)
+		}
+		return str, nil
+	}
+	b, err := s.AsBytes()
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", fmt.Errorf("byte array %s is not an utf8 string", helper.BytesToHex(b))
+	}
+	return string(b), nil
+}
+
+// AsUInt160 converts a byte array of 20 bytes to a script hash
+func (s StackItem) AsUInt160() (helper.UInt160, error) {
+	b, err := s.AsBytes()
+	if err != nil {
+		return helper.UInt160{}, err
+	}
+	return helper.UInt160FromBytes(b)
+}
+
+// AsArray returns the items of an Array
+func (s StackItem) AsArray() ([]StackItem, error) {
+	if s.Type != sc.Array {
+		return nil, fmt.Errorf("%s can not be converted to an array", s.Type.String())
+	}
+	a, ok := s.Value.([]sc.ContractParameter)
+	if !ok {
+		return nil, fmt.Errorf("invalid Array value %T", s.Value)
+	}
+	items := make([]StackItem, len(a))
+	for i := range a {
+		items[i] = StackItem(a[i])
+	}
+	return items, nil
+}
+
+// AsMap returns the pairs of a Map, which are keyed by the byte arrays of the keys as the NeoVM compares them
+func (s StackItem) AsMap() (map[string]StackItem, error) {
+	if s.Type != sc.Map {
+		return nil, fmt.Errorf("%s can not be converted to a map", s.Type.String())
+	}
+	pairs, ok := s.Value.([]sc.ContractParameterPair)
+	if !ok {
+		return nil, fmt.Errorf("invalid Map value %T", s.Value)
+	}
+	m := make(map[string]StackItem, len(pairs))
+	for _, pair := range pairs {
+		key, err := StackItem(pair.Key).AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		m[string(key)] = StackItem(pair.Value)
 	}
+	return m, nil
 }
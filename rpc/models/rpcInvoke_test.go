@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvokeResult_AsBigInt(t *testing.T) {
+	r := InvokeResult{State: "HALT", Stack: []sc.ContractParameter{{Type: sc.ByteArray, Value: helper.HexToBytes("00803dafbe50d300")}}}
+	i, err := r.AsBigInt()
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(59480000000000000), i)
+
+	// more than 8 bytes are not truncated
+	r.Stack[0].Value = helper.HexToBytes("0000000000000000000100")
+	i, err = r.AsBigInt()
+	assert.Nil(t, err)
+	assert.Equal(t, "4722366482869645213696", i.String())
+
+	r.Stack[0] = sc.ContractParameter{Type: sc.Integer, Value: *big.NewInt(-8)}
+	i, err = r.AsBigInt()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-8), i.Int64())
+
+	r.Stack[0] = sc.ContractParameter{Type: sc.Boolean, Value: true}
+	i, err = r.AsBigInt()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), i.Int64())
+
+	r.Stack[0] = sc.ContractParameter{Type: sc.ByteArray, Value: make([]byte, 33)}
+	_, err = r.AsBigInt()
+	assert.NotNil(t, err)
+	r.Stack[0] = sc.ContractParameter{Type: sc.Array, Value: []sc.ContractParameter{}}
+	_, err = r.AsBigInt()
+	assert.NotNil(t, err)
+	r.Stack[0] = sc.ContractParameter{Type: sc.Integer, Value: "1"}
+	_, err = r.AsBigInt()
+	assert.NotNil(t, err)
+
+	r.State = "FAULT"
+	_, err = r.AsBigInt()
+	assert.NotNil(t, err)
+	r = InvokeResult{State: "HALT"}
+	_, err = r.AsBigInt()
+	assert.NotNil(t, err)
+}
+
+func TestInvokeResult_AsBool(t *testing.T) {
+	r := InvokeResult{State: "HALT", Stack: []sc.ContractParameter{{Type: sc.ByteArray, Value: []byte{0, 0}}}}
+	b, err := r.AsBool()
+	assert.Nil(t, err)
+	assert.False(t, b)
+
+	r.Stack[0].Value = []byte{0, 1}
+	b, err = r.AsBool()
+	assert.Nil(t, err)
+	assert.True(t, b)
+
+	r.Stack[0] = sc.ContractParameter{Type: sc.Integer, Value: *big.NewInt(0)}
+	b, err = r.AsBool()
+	assert.Nil(t, err)
+	assert.False(t, b)
+
+	r.Stack[0] = sc.ContractParameter{Type: sc.Array, Value: []sc.ContractParameter{}}
+	b, err = r.AsBool()
+	assert.Nil(t, err)
+	assert.True(t, b)
+}
+
+func TestInvokeResult_AsString(t *testing.T) {
+	r := InvokeResult{State: "HALT", Stack: []sc.ContractParameter{{Type: sc.ByteArray, Value: helper.HexToBytes("516c696e6b20546f6b656e")}}}
+	s, err := r.AsString()
+	assert.Nil(t, err)
+	assert.Equal(t, "Qlink Token", s)
+
+	r.Stack[0].Value = []byte{0xff}
+	_, err = r.AsString()
+	assert.NotNil(t, err)
+}
+
+func TestInvokeResult_AsUInt160(t *testing.T) {
+	u, _ := helper.UInt160FromString("b9d7ea3062e6aeeb3e8ad9548220c4ba1361d263")
+	r := InvokeResult{State: "HALT", Stack: []sc.ContractParameter{{Type: sc.ByteArray, Value: u.Bytes()}}}
+	v, err := r.AsUInt160()
+	assert.Nil(t, err)
+	assert.Equal(t, u, v)
+
+	r.Stack[0].Value = []byte{1}
+	_, err = r.AsUInt160()
+	assert.NotNil(t, err)
+}
+
+func TestInvokeResult_AsArray(t *testing.T) {
+	r := InvokeResult{}
+	err := json.Unmarshal([]byte(`{
+		"state": "HALT",
+		"stack": [{"type": "Array", "value": [
+			{"type": "ByteArray", "value": "90aaf35d"},
+			{"type": "Integer", "value": "1607668937"},
+			{"type": "Map", "value": [
+				{"key": {"type": "ByteArray", "value": "61"}, "value": {"type": "Boolean", "value": true}}
+			]}
+		]}]
+	}`), &r)
+	assert.Nil(t, err)
+	items, err := r.AsArray()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(items))
+	i, err := items[1].AsBigInt()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1607668937), i.Int64())
+	m, err := items[2].AsMap()
+	assert.Nil(t, err)
+	b, err := m["a"].AsBool()
+	assert.Nil(t, err)
+	assert.True(t, b)
+
+	_, err = items[0].AsArray()
+	assert.NotNil(t, err)
+	_, err = r.AsMap()
+	assert.NotNil(t, err)
+}
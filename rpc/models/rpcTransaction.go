@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 type RpcTransaction struct {
 	Txid          string                     `json:"txid"`
 	Size          int                        `json:"size"`
@@ -18,6 +20,10 @@ type RpcTransaction struct {
 	Script        string                     `json:"script"`
 	Gas           string                     `json:"gas"`
 	Claims        []RpcClaim                 `json:"claims"`
+	Descriptors   []RpcStateDescriptor       `json:"descriptors"`
+	PubKey        string                     `json:"pubkey"`
+	Asset         *RpcRegisterAsset          `json:"asset"`
+	Contract      *RpcPublishContract        `json:"contract"`
 }
 
 type RpcTransactionAttribute struct {
@@ -41,3 +47,38 @@ type RpcClaim struct {
 	Txid string `json:"txid"`
 	Vout int    `json:"vout"`
 }
+
+type RpcStateDescriptor struct {
+	Type  string `json:"type"`
+	Key   string `json:"key"`
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// RpcRegisterAsset is the asset of a RegisterTransaction
+type RpcRegisterAsset struct {
+	Type      string          `json:"type"`
+	Name      json.RawMessage `json:"name"` // the json of the asset names, or a plain string
+	Amount    string          `json:"amount"`
+	Precision int             `json:"precision"`
+	Owner     string          `json:"owner"`
+	Admin     string          `json:"admin"`
+}
+
+// RpcPublishContract is the contract of a PublishTransaction
+type RpcPublishContract struct {
+	Code        RpcPublishCode `json:"code"`
+	NeedStorage bool           `json:"needstorage"`
+	Name        string         `json:"name"`
+	Version     string         `json:"version"`
+	Author      string         `json:"author"`
+	Email       string         `json:"email"`
+	Description string         `json:"description"`
+}
+
+type RpcPublishCode struct {
+	Hash       string   `json:"hash"`
+	Script     string   `json:"script"`
+	Parameters []string `json:"parameters"`
+	ReturnType string   `json:"returntype"`
+}
@@ -1,12 +1,14 @@
 package models
 
+import "github.com/joeqian10/neo-gogogo/helper"
+
 type UnclaimedGasInWallet struct {
 	Available   string `json:"available"`
 	Unavailable string `json:"unavailable"`
 }
 
 type UnclaimedGasInAddress struct {
-	Available   float64 `json:"available"`
-	Unavailable float64 `json:"unavailable"`
-	Unclaimed   float64 `json:"unclaimed"`
+	Available   helper.Fixed8 `json:"available"`
+	Unavailable helper.Fixed8 `json:"unavailable"`
+	Unclaimed   helper.Fixed8 `json:"unclaimed"`
 }
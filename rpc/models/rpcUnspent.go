@@ -1,16 +1,18 @@
 package models
 
+import "github.com/joeqian10/neo-gogogo/helper"
+
 type RpcUnspent struct {
 	Balances []UnspentBalance `json:"balance"`
-	Address  string            `json:"address"`
+	Address  string           `json:"address"`
 }
 
 type UnspentBalance struct {
-	Unspents    []Unspent `json:"unspent"`
-	AssetHash   string     `json:"asset_hash"`
-	Asset       string     `json:"asset"`
-	AssetSymbol string     `json:"asset_symbol"`
-	Amount      float64    `json:"amount"`
+	Unspents    []Unspent     `json:"unspent"`
+	AssetHash   string        `json:"asset_hash"`
+	Asset       string        `json:"asset"`
+	AssetSymbol string        `json:"asset_symbol"`
+	Amount      helper.Fixed8 `json:"amount"`
 }
 
 type UnspentSlice []Unspent
@@ -20,7 +22,7 @@ func (us UnspentSlice) Len() int {
 }
 
 func (us UnspentSlice) Less(i, j int) bool {
-	return us[i].Value < us[j].Value
+	return us[i].Value.LessThan(us[j].Value)
 }
 
 func (us UnspentSlice) Swap(i, j int) {
@@ -29,16 +31,16 @@ func (us UnspentSlice) Swap(i, j int) {
 	us[j] = t
 }
 
-func (us UnspentSlice) Sum() float64 {
-	var s float64 = 0
+func (us UnspentSlice) Sum() helper.Fixed8 {
+	s := helper.Zero
 	for _, u := range us {
-		s += u.Value
+		s = s.Add(u.Value)
 	}
 	return s
 }
 
 type Unspent struct {
-	Txid  string  `json:"txid"`
-	N     int     `json:"n"`
-	Value float64 `json:"value"`
+	Txid  string        `json:"txid"`
+	N     int           `json:"n"`
+	Value helper.Fixed8 `json:"value"`
 }
@@ -9,13 +9,38 @@ type RpcResponse struct {
 
 type ErrorResponse struct {
 	Error RpcError `json:"error"`
+	Err   error    `json:"-"` // the client side error that failed the call, nil if the node replied
 }
 
+// NewErrorResponse wraps a client side error, the message is kept in Error for compatibility
+func NewErrorResponse(err error) ErrorResponse {
+	return ErrorResponse{
+		Error: RpcError{
+			Message: err.Error(),
+		},
+		Err: err,
+	}
+}
+
+// HasError tells whether the call failed, it is the same as GetError() != nil
 func (r *ErrorResponse) HasError() bool {
-	if len(r.Error.Message) == 0 {
-		return false
+	return r.GetError() != nil
+}
+
+// GetError returns the error that failed the call, nil if it succeeded. It is the client side
+// error (*TransportError, *DecodeError or *ContextError) if any, else the *RpcError replied by
+// the node, which matches the sentinel errors of this package with errors.Is:
+//
+//	if err := response.GetError(); errors.Is(err, rpc.ErrAlreadyExists) { ... }
+func (r *ErrorResponse) GetError() error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if r.Error.Code == 0 && len(r.Error.Message) == 0 {
+		return nil
 	}
-	return true
+	e := r.Error
+	return &e
 }
 
 type RpcError struct {
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/url"
@@ -16,6 +17,7 @@ type IHttpClient interface {
 type RpcClient struct {
 	Endpoint   *url.URL
 	httpClient IHttpClient
+	ctx        context.Context
 }
 
 func NewClient(endpoint string) *RpcClient {
@@ -29,24 +31,59 @@ func NewClient(endpoint string) *RpcClient {
 	return &RpcClient{Endpoint: u, httpClient: netClient}
 }
 
+// WithContext returns a shallow copy of the client whose requests are bound to ctx,
+// so cancelling ctx or reaching its deadline aborts the in-flight http request.
+// The 60s timeout of the underlying http client still applies.
+func (n *RpcClient) WithContext(ctx context.Context) *RpcClient {
+	if ctx == nil {
+		panic("nil context")
+	}
+	n2 := new(RpcClient)
+	*n2 = *n
+	n2.ctx = ctx
+	return n2
+}
+
+// Context returns the context bound to the client, context.Background() if none
+func (n *RpcClient) Context() context.Context {
+	if n.ctx != nil {
+		return n.ctx
+	}
+	return context.Background()
+}
+
 func (n *RpcClient) makeRequest(method string, params []interface{}, out interface{}) error {
 	request := NewRequest(method, params)
-	jsonValue, _ := json.Marshal(request)
+	return n.post(method, request, out)
+}
+
+// post sends body as json to the endpoint and decodes the reply into out,
+// name is only used to describe the call in a ContextError
+func (n *RpcClient) post(name string, body interface{}, out interface{}) error {
+	ctx := n.Context()
+	jsonValue, _ := json.Marshal(body)
 	req, err := http.NewRequest("POST", n.Endpoint.String(), bytes.NewBuffer(jsonValue))
 	if err != nil {
-		return err
+		return &TransportError{Method: name, Err: err}
 	}
+	req = req.WithContext(ctx)
 	req.Header.Add("content-type", "application/json")
 	req.Header.Set("Connection", "close")
 	req.Close = true
 	res, err := n.httpClient.Do(req)
 	if err != nil {
-		return err
+		if ctx.Err() != nil {
+			return &ContextError{Method: name, Err: ctx.Err()}
+		}
+		return &TransportError{Method: name, Err: err}
 	}
 	defer res.Body.Close()
 	err = json.NewDecoder(res.Body).Decode(&out)
 	if err != nil {
-		return err
+		if ctx.Err() != nil {
+			return &ContextError{Method: name, Err: ctx.Err()}
+		}
+		return &DecodeError{Method: name, Err: err}
 	}
 	return nil
 }
@@ -56,18 +93,7 @@ func (n *RpcClient) ClaimGas(address string) ClaimGasResponse {
 	params := []interface{}{address}
 	err := n.makeRequest("claimgas", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
-	}
-		if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -78,11 +104,7 @@ func (n *RpcClient) GetAccountState(address string) GetAccountStateResponse {
 	params := []interface{}{address}
 	err := n.makeRequest("getaccountstate", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -93,11 +115,7 @@ func (n *RpcClient) GetApplicationLog(txId string) GetApplicationLogResponse {
 	params := []interface{}{txId}
 	err := n.makeRequest("getapplicationlog", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -107,11 +125,7 @@ func (n *RpcClient) GetAssetState(assetId string) GetAssetStateResponse {
 	params := []interface{}{assetId}
 	err := n.makeRequest("getassetstate", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -122,11 +136,7 @@ func (n *RpcClient) GetBalance(assetId string) GetBalanceResponse {
 	params := []interface{}{assetId}
 	err := n.makeRequest("getbalance", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -136,11 +146,7 @@ func (n *RpcClient) GetBestBlockHash() GetBestBlockHashResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getbestblockhash", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -150,11 +156,7 @@ func (n *RpcClient) GetBlockByHash(blockHash string) GetBlockResponse {
 	params := []interface{}{blockHash, 1}
 	err := n.makeRequest("getblock", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -164,11 +166,7 @@ func (n *RpcClient) GetBlockByIndex(index uint32) GetBlockResponse {
 	params := []interface{}{index, 1}
 	err := n.makeRequest("getblock", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -178,11 +176,7 @@ func (n *RpcClient) GetBlockCount() GetBlockCountResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getblockcount", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -192,11 +186,7 @@ func (n *RpcClient) GetBlockHeaderByHash(blockHash string) GetBlockHeaderRespons
 	params := []interface{}{blockHash, 1}
 	err := n.makeRequest("getblockheader", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -211,11 +201,7 @@ func (n *RpcClient) GetBlockHash(index uint32) GetBlockHashResponse {
 	params := []interface{}{index}
 	err := n.makeRequest("getblockhash", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -225,11 +211,7 @@ func (n *RpcClient) GetClaimable(address string) GetClaimableResponse {
 	params := []interface{}{address}
 	err := n.makeRequest("getclaimable", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -239,11 +221,7 @@ func (n *RpcClient) GetConnectionCount() GetConnectionCountResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getconnectioncount", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -252,11 +230,7 @@ func (n *RpcClient) GetContractState(scriptHash string) GetContractStateResponse
 	params := []interface{}{scriptHash}
 	err := n.makeRequest("getcontractstate", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -267,11 +241,7 @@ func (n *RpcClient) GetNep5Balances(address string) GetNep5BalancesResponse {
 	params := []interface{}{address}
 	err := n.makeRequest("getnep5balances", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -282,11 +252,7 @@ func (n *RpcClient) GetNep5Transfers(address string) GetNep5TransfersResponse {
 	params := []interface{}{address}
 	err := n.makeRequest("getnep5balances", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -297,11 +263,7 @@ func (n *RpcClient) GetNewAddress() GetNewAddressResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getnewaddress", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -311,11 +273,7 @@ func (n *RpcClient) GetRawMemPool() GetRawMemPoolResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getrawmempool", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -325,11 +283,7 @@ func (n *RpcClient) GetRawTransaction(txid string) GetRawTransactionResponse {
 	params := []interface{}{txid, 1}
 	err := n.makeRequest("getrawtransaction", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -339,11 +293,7 @@ func (n *RpcClient) GetStorage(scripthash string, key string) GetStorageResponse
 	params := []interface{}{scripthash, key}
 	err := n.makeRequest("getstorage", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -353,11 +303,7 @@ func (n *RpcClient) GetTransactionHeight(txid string) GetTransactionHeightRespon
 	params := []interface{}{txid}
 	err := n.makeRequest("gettransactionheight", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -367,11 +313,7 @@ func (n *RpcClient) GetTxOut(txid string, index int) GetTxOutResponse {
 	params := []interface{}{txid, index}
 	err := n.makeRequest("gettxout", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -381,11 +323,7 @@ func (n *RpcClient) GetPeers() GetPeersResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getpeers", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -395,11 +333,7 @@ func (n *RpcClient) GetUnclaimedGas() GetUnclaimedGasResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getunclaimedgas", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -409,11 +343,7 @@ func (n *RpcClient) GetUnclaimed(address string) GetUnclaimedResponse {
 	params := []interface{}{address}
 	err := n.makeRequest("getunclaimed", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -423,11 +353,7 @@ func (n *RpcClient) GetUnspents(adddress string) GetUnspentsResponse {
 	params := []interface{}{adddress}
 	err := n.makeRequest("getunspents", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -437,11 +363,7 @@ func (n *RpcClient) GetValidators() GetValidatorsResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getvalidators", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -451,11 +373,7 @@ func (n *RpcClient) GetVersion() GetVersionResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getversion", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -465,11 +383,7 @@ func (n *RpcClient) GetWalletHeight() GetWalletHeightResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getwalletheight", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -480,11 +394,7 @@ func (n *RpcClient) ImportPrivKey(wif string) ImportPrivKeyResponse {
 	params := []interface{}{wif}
 	err := n.makeRequest("importprivkey", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -499,11 +409,7 @@ func (n *RpcClient) InvokeFunction(scriptHash string, method string, checkWitnes
 	}
 	err := n.makeRequest("invokefunction", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -513,11 +419,22 @@ func (n *RpcClient) InvokeScript(scriptInHex string, checkWitnessHashes string)
 	params := []interface{}{scriptInHex, checkWitnessHashes}
 	err := n.makeRequest("invokescript", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
+	}
+	return response
+}
+
+// InvokeScriptWithWitnesses test runs the script, CheckWitness returns true for each of checkWitnessHashes,
+// which are passed to invokescript as separate parameters
+func (n *RpcClient) InvokeScriptWithWitnesses(scriptInHex string, checkWitnessHashes []string) InvokeScriptResponse {
+	response := InvokeScriptResponse{}
+	params := []interface{}{scriptInHex}
+	for _, h := range checkWitnessHashes {
+		params = append(params, h)
+	}
+	err := n.makeRequest("invokescript", params, &response)
+	if err != nil {
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -527,11 +444,7 @@ func (n *RpcClient) ListPlugins() ListPluginsResponse {
 	params := []interface{}{}
 	err := n.makeRequest("listplugins", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -542,11 +455,7 @@ func (n *RpcClient) ListAddress() ListAddressResponse {
 	params := []interface{}{}
 	err := n.makeRequest("listaddress", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -557,11 +466,7 @@ func (n *RpcClient) SendFrom(assetId string, from string, to string, amount uint
 	params := []interface{}{assetId, from, to, amount, fee, changeAddress}
 	err := n.makeRequest("sendfrom", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -571,11 +476,7 @@ func (n *RpcClient) SendRawTransaction(rawTransactionInHex string) SendRawTransa
 	params := []interface{}{rawTransactionInHex, 1}
 	err := n.makeRequest("sendrawtransaction", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -586,11 +487,7 @@ func (n *RpcClient) SendToAddress(assetId string, to string, amount uint32, fee
 	params := []interface{}{assetId, to, amount, fee, changeAddress}
 	err := n.makeRequest("sendtoaddress", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -602,11 +499,7 @@ func (n *RpcClient) SubmitBlock(blockHex string) SubmitBlockResponse {
 	params := []interface{}{blockHex}
 	err := n.makeRequest("submitblock", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -616,11 +509,7 @@ func (n *RpcClient) ValidateAddress(address string) ValidateAddressResponse {
 	params := []interface{}{address}
 	err := n.makeRequest("validateaddress", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -630,11 +519,7 @@ func (n *RpcClient) GetProof(stateroot, contractScriptHash, storeKey string) Cro
 	params := []interface{}{stateroot, contractScriptHash, storeKey}
 	err := n.makeRequest("getproof", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -644,11 +529,7 @@ func (n *RpcClient) GetStateHeight() StateHeightResponse {
 	params := []interface{}{}
 	err := n.makeRequest("getstateheight", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -658,11 +539,7 @@ func (n *RpcClient) GetStateRootByIndex(blockHeight uint32) StateRootResponse {
 	params := []interface{}{blockHeight}
 	err := n.makeRequest("getstateroot", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -672,11 +549,7 @@ func (n *RpcClient) GetStateRootByHash(blockHash string) StateRootResponse {
 	params := []interface{}{blockHash}
 	err := n.makeRequest("getstateroot", params, &response)
 	if err != nil {
-		response.ErrorResponse = ErrorResponse{
-			Error: RpcError{
-				Message: err.Error(),
-			},
-		}
+		response.ErrorResponse = NewErrorResponse(err)
 	}
 	return response
 }
@@ -139,6 +139,10 @@ func (r *RpcClientMock) InvokeScript(s1 string, s2 string) InvokeScriptResponse
 	args := r.Called(s1, s2)
 	return args.Get(0).(InvokeScriptResponse)
 }
+func (r *RpcClientMock) InvokeScriptWithWitnesses(s string, hashes []string) InvokeScriptResponse {
+	args := r.Called(s, hashes)
+	return args.Get(0).(InvokeScriptResponse)
+}
 func (r *RpcClientMock) ListPlugins() ListPluginsResponse {
 	args := r.Called()
 	return args.Get(0).(ListPluginsResponse)
@@ -0,0 +1,619 @@
+package rpc
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxLag          = 2
+	DefaultRefreshInterval = 15 * time.Second // about one block
+	DefaultCooldown        = 30 * time.Second
+)
+
+// RpcClientPool implements IRpcClient over several neo nodes.
+//
+// Reads are routed to the fastest node that is not lagging behind the best known height,
+// and are retried on the next node when the call fails on the client side (transport, decode,
+// timeout). Errors replied by a node are returned as is. A failed node is skipped for Cooldown.
+//
+// SendRawTransaction and SubmitBlock are sent to every node of the broadcast set, which defaults
+// to the whole pool. Calls of the RpcWallet plugin always go to the first endpoint since the
+// wallet is opened there, and are never retried.
+type RpcClientPool struct {
+	MaxLag          uint32        // blocks a node may lag behind the best height before being skipped
+	RefreshInterval time.Duration // how often the heights are refreshed, 0 to refresh only manually
+	Cooldown        time.Duration // how long a failed node is skipped
+
+	nodes       []*poolNode
+	broadcast   []*poolNode
+	lastRefresh time.Time
+	mu          sync.Mutex
+}
+
+type poolNode struct {
+	endpoint  string
+	client    IRpcClient
+	latency   time.Duration // moving average of the call duration
+	height    uint32        // last block count returned by the node
+	failures  int           // consecutive failures
+	downUntil time.Time
+}
+
+// NodeStatus is a snapshot of the health of one node in the pool
+type NodeStatus struct {
+	Endpoint string
+	Latency  time.Duration
+	Height   uint32
+	Failures int
+	Healthy  bool
+}
+
+// NewClientPool creates a pool over the endpoints, it returns nil if any endpoint is invalid
+func NewClientPool(endpoints ...string) *RpcClientPool {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	clients := make([]IRpcClient, len(endpoints))
+	for i, endpoint := range endpoints {
+		client := NewClient(endpoint)
+		if client == nil {
+			return nil
+		}
+		clients[i] = client
+	}
+	return newClientPool(endpoints, clients)
+}
+
+func newClientPool(endpoints []string, clients []IRpcClient) *RpcClientPool {
+	p := &RpcClientPool{
+		MaxLag:          DefaultMaxLag,
+		RefreshInterval: DefaultRefreshInterval,
+		Cooldown:        DefaultCooldown,
+	}
+	for i := range clients {
+		p.nodes = append(p.nodes, &poolNode{endpoint: endpoints[i], client: clients[i]})
+	}
+	p.broadcast = p.nodes
+	return p
+}
+
+// SetBroadcastEndpoints pins SendRawTransaction and SubmitBlock to the endpoints,
+// which do not need to be part of the pool
+func (p *RpcClientPool) SetBroadcastEndpoints(endpoints ...string) error {
+	var broadcast []*poolNode
+	for _, endpoint := range endpoints {
+		n := p.find(endpoint)
+		if n == nil {
+			client := NewClient(endpoint)
+			if client == nil {
+				return fmt.Errorf("invalid endpoint: %s", endpoint)
+			}
+			n = &poolNode{endpoint: endpoint, client: client}
+		}
+		broadcast = append(broadcast, n)
+	}
+	if len(broadcast) == 0 {
+		return fmt.Errorf("no broadcast endpoint")
+	}
+	p.mu.Lock()
+	p.broadcast = broadcast
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *RpcClientPool) find(endpoint string) *poolNode {
+	for _, n := range p.nodes {
+		if n.endpoint == endpoint {
+			return n
+		}
+	}
+	return nil
+}
+
+// Refresh queries the block count of every node concurrently
+func (p *RpcClientPool) Refresh() {
+	p.mu.Lock()
+	p.lastRefresh = time.Now()
+	p.mu.Unlock()
+	var wg sync.WaitGroup
+	for _, n := range p.nodes {
+		wg.Add(1)
+		go func(n *poolNode) {
+			defer wg.Done()
+			start := time.Now()
+			response := n.client.GetBlockCount()
+			p.report(n, start, &response.ErrorResponse)
+			if !response.HasError() {
+				p.mu.Lock()
+				n.height = uint32(response.Result)
+				p.mu.Unlock()
+			}
+		}(n)
+	}
+	wg.Wait()
+}
+
+// Status returns the health of every node, in routing order
+func (p *RpcClientPool) Status() []NodeStatus {
+	nodes := p.route()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	best := p.bestHeight()
+	now := time.Now()
+	status := make([]NodeStatus, len(nodes))
+	for i, n := range nodes {
+		status[i] = NodeStatus{
+			Endpoint: n.endpoint,
+			Latency:  n.latency,
+			Height:   n.height,
+			Failures: n.failures,
+			Healthy:  p.healthy(n, best, now),
+		}
+	}
+	return status
+}
+
+// route returns the nodes ordered by preference, healthy ones first then by latency
+func (p *RpcClientPool) route() []*poolNode {
+	p.mu.Lock()
+	stale := p.RefreshInterval > 0 && time.Since(p.lastRefresh) > p.RefreshInterval
+	p.mu.Unlock()
+	if stale {
+		p.Refresh()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	best := p.bestHeight()
+	now := time.Now()
+	nodes := make([]*poolNode, len(p.nodes))
+	copy(nodes, p.nodes)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		hi, hj := p.healthy(nodes[i], best, now), p.healthy(nodes[j], best, now)
+		if hi != hj {
+			return hi
+		}
+		return nodes[i].latency < nodes[j].latency
+	})
+	return nodes
+}
+
+func (p *RpcClientPool) bestHeight() uint32 {
+	var best uint32
+	for _, n := range p.nodes {
+		if n.height > best {
+			best = n.height
+		}
+	}
+	return best
+}
+
+func (p *RpcClientPool) healthy(n *poolNode, best uint32, now time.Time) bool {
+	return now.After(n.downUntil) && n.height+p.MaxLag >= best
+}
+
+// report updates the statistics of n after a call started at start
+func (p *RpcClientPool) report(n *poolNode, start time.Time, r *ErrorResponse) {
+	elapsed := time.Since(start)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if r.Err != nil {
+		n.failures++
+		n.downUntil = time.Now().Add(p.Cooldown)
+		return
+	}
+	n.failures = 0
+	if n.latency == 0 {
+		n.latency = elapsed
+	} else {
+		n.latency = (n.latency*7 + elapsed*3) / 10
+	}
+}
+
+// read calls f on the nodes by preference until one does not fail on the client side
+func (p *RpcClientPool) read(f func(c IRpcClient) *ErrorResponse) {
+	for _, n := range p.route() {
+		start := time.Now()
+		r := f(n.client)
+		p.report(n, start, r)
+		if r.Err == nil {
+			return
+		}
+	}
+}
+
+// primary calls f on the first endpoint only
+func (p *RpcClientPool) primary(f func(c IRpcClient) *ErrorResponse) {
+	n := p.nodes[0]
+	start := time.Now()
+	p.report(n, start, f(n.client))
+}
+
+func (p *RpcClientPool) broadcastNodes() []*poolNode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.broadcast
+}
+
+// send calls f on every node of broadcast, and returns the index of the first
+// successful reply, or 0 if all failed
+func (p *RpcClientPool) send(broadcast []*poolNode, f func(i int, c IRpcClient) *ErrorResponse) int {
+	success := -1
+	for i, n := range broadcast {
+		start := time.Now()
+		r := f(i, n.client)
+		p.report(n, start, r)
+		if success < 0 && !r.HasError() {
+			success = i
+		}
+	}
+	if success < 0 {
+		return 0
+	}
+	return success
+}
+
+func (p *RpcClientPool) ClaimGas(address string) ClaimGasResponse {
+	var response ClaimGasResponse
+	p.primary(func(c IRpcClient) *ErrorResponse {
+		response = c.ClaimGas(address)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetAccountState(address string) GetAccountStateResponse {
+	var response GetAccountStateResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetAccountState(address)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetApplicationLog(txId string) GetApplicationLogResponse {
+	var response GetApplicationLogResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetApplicationLog(txId)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetAssetState(assetId string) GetAssetStateResponse {
+	var response GetAssetStateResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetAssetState(assetId)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetBalance(assetId string) GetBalanceResponse {
+	var response GetBalanceResponse
+	p.primary(func(c IRpcClient) *ErrorResponse {
+		response = c.GetBalance(assetId)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetBestBlockHash() GetBestBlockHashResponse {
+	var response GetBestBlockHashResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetBestBlockHash()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetBlockByHash(blockHash string) GetBlockResponse {
+	var response GetBlockResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetBlockByHash(blockHash)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetBlockByIndex(index uint32) GetBlockResponse {
+	var response GetBlockResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetBlockByIndex(index)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetBlockCount() GetBlockCountResponse {
+	var response GetBlockCountResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetBlockCount()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetBlockHeaderByHash(blockHash string) GetBlockHeaderResponse {
+	var response GetBlockHeaderResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetBlockHeaderByHash(blockHash)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetBlockHash(index uint32) GetBlockHashResponse {
+	var response GetBlockHashResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetBlockHash(index)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetClaimable(address string) GetClaimableResponse {
+	var response GetClaimableResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetClaimable(address)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetConnectionCount() GetConnectionCountResponse {
+	var response GetConnectionCountResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetConnectionCount()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetContractState(scriptHash string) GetContractStateResponse {
+	var response GetContractStateResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetContractState(scriptHash)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetNep5Balances(address string) GetNep5BalancesResponse {
+	var response GetNep5BalancesResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetNep5Balances(address)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetNep5Transfers(address string) GetNep5TransfersResponse {
+	var response GetNep5TransfersResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetNep5Transfers(address)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetNewAddress() GetNewAddressResponse {
+	var response GetNewAddressResponse
+	p.primary(func(c IRpcClient) *ErrorResponse {
+		response = c.GetNewAddress()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetPeers() GetPeersResponse {
+	var response GetPeersResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetPeers()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetRawMemPool() GetRawMemPoolResponse {
+	var response GetRawMemPoolResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetRawMemPool()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetRawTransaction(txid string) GetRawTransactionResponse {
+	var response GetRawTransactionResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetRawTransaction(txid)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetStorage(scripthash string, key string) GetStorageResponse {
+	var response GetStorageResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetStorage(scripthash, key)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetTransactionHeight(txid string) GetTransactionHeightResponse {
+	var response GetTransactionHeightResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetTransactionHeight(txid)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetTxOut(txid string, index int) GetTxOutResponse {
+	var response GetTxOutResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetTxOut(txid, index)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetUnclaimed(address string) GetUnclaimedResponse {
+	var response GetUnclaimedResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetUnclaimed(address)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetUnclaimedGas() GetUnclaimedGasResponse {
+	var response GetUnclaimedGasResponse
+	p.primary(func(c IRpcClient) *ErrorResponse {
+		response = c.GetUnclaimedGas()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetUnspents(address string) GetUnspentsResponse {
+	var response GetUnspentsResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetUnspents(address)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetValidators() GetValidatorsResponse {
+	var response GetValidatorsResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetValidators()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetVersion() GetVersionResponse {
+	var response GetVersionResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.GetVersion()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) GetWalletHeight() GetWalletHeightResponse {
+	var response GetWalletHeightResponse
+	p.primary(func(c IRpcClient) *ErrorResponse {
+		response = c.GetWalletHeight()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) ImportPrivKey(wif string) ImportPrivKeyResponse {
+	var response ImportPrivKeyResponse
+	p.primary(func(c IRpcClient) *ErrorResponse {
+		response = c.ImportPrivKey(wif)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) InvokeFunction(scriptHash string, method string, checkWitnessHashes string, args ...interface{}) InvokeFunctionResponse {
+	var response InvokeFunctionResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.InvokeFunction(scriptHash, method, checkWitnessHashes, args...)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) InvokeScript(scriptInHex string, checkWitnessHashes string) InvokeScriptResponse {
+	var response InvokeScriptResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.InvokeScript(scriptInHex, checkWitnessHashes)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) InvokeScriptWithWitnesses(scriptInHex string, checkWitnessHashes []string) InvokeScriptResponse {
+	var response InvokeScriptResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = InvokeScriptWithWitnesses(c, scriptInHex, checkWitnessHashes)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) ListPlugins() ListPluginsResponse {
+	var response ListPluginsResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.ListPlugins()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) ListAddress() ListAddressResponse {
+	var response ListAddressResponse
+	p.primary(func(c IRpcClient) *ErrorResponse {
+		response = c.ListAddress()
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) SendFrom(assetId string, from string, to string, amount uint32, fee float32, changeAddress string) SendFromResponse {
+	var response SendFromResponse
+	p.primary(func(c IRpcClient) *ErrorResponse {
+		response = c.SendFrom(assetId, from, to, amount, fee, changeAddress)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) SendRawTransaction(rawTransactionInHex string) SendRawTransactionResponse {
+	broadcast := p.broadcastNodes()
+	responses := make([]SendRawTransactionResponse, len(broadcast))
+	i := p.send(broadcast, func(i int, c IRpcClient) *ErrorResponse {
+		responses[i] = c.SendRawTransaction(rawTransactionInHex)
+		return &responses[i].ErrorResponse
+	})
+	return responses[i]
+}
+
+func (p *RpcClientPool) SendToAddress(assetId string, to string, amount uint32, fee float32, changeAddress string) SendToAddressResponse {
+	var response SendToAddressResponse
+	p.primary(func(c IRpcClient) *ErrorResponse {
+		response = c.SendToAddress(assetId, to, amount, fee, changeAddress)
+		return &response.ErrorResponse
+	})
+	return response
+}
+
+func (p *RpcClientPool) SubmitBlock(blockHex string) SubmitBlockResponse {
+	broadcast := p.broadcastNodes()
+	responses := make([]SubmitBlockResponse, len(broadcast))
+	i := p.send(broadcast, func(i int, c IRpcClient) *ErrorResponse {
+		responses[i] = c.SubmitBlock(blockHex)
+		return &responses[i].ErrorResponse
+	})
+	return responses[i]
+}
+
+func (p *RpcClientPool) ValidateAddress(address string) ValidateAddressResponse {
+	var response ValidateAddressResponse
+	p.read(func(c IRpcClient) *ErrorResponse {
+		response = c.ValidateAddress(address)
+		return &response.ErrorResponse
+	})
+	return response
+}
@@ -0,0 +1,105 @@
+package rpc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPool(clients ...*RpcClientMock) *RpcClientPool {
+	endpoints := make([]string, len(clients))
+	iClients := make([]IRpcClient, len(clients))
+	for i, c := range clients {
+		endpoints[i] = fmt.Sprintf("http://node%d", i)
+		iClients[i] = c
+	}
+	return newClientPool(endpoints, iClients)
+}
+
+func TestNewClientPool(t *testing.T) {
+	pool := NewClientPool("http://seed1.ngd.network:20332", "http://seed2.ngd.network:20332")
+	assert.NotNil(t, pool)
+	var client IRpcClient = pool
+	assert.NotNil(t, client)
+	assert.Nil(t, NewClientPool())
+}
+
+func TestRpcClientPool_Failover(t *testing.T) {
+	c0, c1 := new(RpcClientMock), new(RpcClientMock)
+	pool := newTestPool(c0, c1)
+	pool.RefreshInterval = 0
+	c0.On("GetBlockHash", uint32(1)).Return(GetBlockHashResponse{
+		ErrorResponse: NewErrorResponse(fmt.Errorf("connection refused")),
+	})
+	c1.On("GetBlockHash", uint32(1)).Return(GetBlockHashResponse{
+		Result: "0xd782db8a38b0eea0d7394e0f007c61c71798867578c77c387c08113903946cc9",
+	})
+
+	response := pool.GetBlockHash(1)
+	assert.False(t, response.HasError())
+	assert.Equal(t, "0xd782db8a38b0eea0d7394e0f007c61c71798867578c77c387c08113903946cc9", response.Result)
+
+	status := pool.Status()
+	assert.Equal(t, "http://node1", status[0].Endpoint)
+	assert.True(t, status[0].Healthy)
+	assert.Equal(t, "http://node0", status[1].Endpoint)
+	assert.False(t, status[1].Healthy)
+	assert.Equal(t, 1, status[1].Failures)
+
+	// the failed node is skipped during its cooldown
+	response = pool.GetBlockHash(1)
+	assert.False(t, response.HasError())
+	c0.AssertNumberOfCalls(t, "GetBlockHash", 1)
+}
+
+func TestRpcClientPool_NodeError(t *testing.T) {
+	c0, c1 := new(RpcClientMock), new(RpcClientMock)
+	pool := newTestPool(c0, c1)
+	pool.RefreshInterval = 0
+	c0.On("GetRawTransaction", "00").Return(GetRawTransactionResponse{
+		ErrorResponse: ErrorResponse{Error: RpcError{Code: -100, Message: "Unknown transaction"}},
+	})
+
+	// errors replied by the node are not retried
+	response := pool.GetRawTransaction("00")
+	assert.True(t, response.HasError())
+	assert.Equal(t, -100, response.Error.Code)
+	c1.AssertNotCalled(t, "GetRawTransaction", "00")
+}
+
+func TestRpcClientPool_SkipLagging(t *testing.T) {
+	c0, c1 := new(RpcClientMock), new(RpcClientMock)
+	pool := newTestPool(c0, c1)
+	pool.RefreshInterval = time.Hour
+	c0.On("GetBlockCount").Return(GetBlockCountResponse{Result: 90})
+	c1.On("GetBlockCount").Return(GetBlockCountResponse{Result: 100})
+	c1.On("GetBlockByIndex", uint32(95)).Return(GetBlockResponse{})
+
+	response := pool.GetBlockByIndex(95)
+	assert.False(t, response.HasError())
+	c0.AssertNotCalled(t, "GetBlockByIndex", uint32(95))
+
+	status := pool.Status()
+	assert.Equal(t, uint32(100), status[0].Height)
+	assert.False(t, status[1].Healthy)
+}
+
+func TestRpcClientPool_SendRawTransaction(t *testing.T) {
+	c0, c1, c2 := new(RpcClientMock), new(RpcClientMock), new(RpcClientMock)
+	pool := newTestPool(c0, c1, c2)
+	pool.RefreshInterval = 0
+	err := pool.SetBroadcastEndpoints("http://node1", "http://node2")
+	assert.Nil(t, err)
+	c1.On("SendRawTransaction", "00").Return(SendRawTransactionResponse{
+		ErrorResponse: NewErrorResponse(fmt.Errorf("timeout")),
+	})
+	c2.On("SendRawTransaction", "00").Return(SendRawTransactionResponse{Result: true})
+
+	response := pool.SendRawTransaction("00")
+	assert.False(t, response.HasError())
+	assert.True(t, response.Result)
+	c0.AssertNotCalled(t, "SendRawTransaction", "00")
+	c1.AssertCalled(t, "SendRawTransaction", "00")
+}
@@ -233,7 +233,7 @@ func TestRpcClient_GetClaimable(t *testing.T) {
 	assert.Equal(t, "bc0fda55480440dbd5492de670d0fc44ecd336a20d55caeb43eedbe239ea3c65", c.TxId)
 	assert.Equal(t, 0, c.N)
 	assert.Equal(t, "APPmjituYcgfNxjuQDy9vP73R2PmhFsYJR", r.Address)
-	assert.Equal(t, 4104, r.Unclaimed)
+	assert.Equal(t, helper.Fixed8FromInt64(4104), r.Unclaimed)
 }
 
 //=== RUN   TestRpcClient_GetClaimable
@@ -458,7 +458,7 @@ func TestRpcClient_InvokeFunction(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "00c1046e616d656763d26113bac4208254d98a3eebaee66230ead7b9", r.Script)
 	assert.Equal(t, "HALT", r.State)
-	assert.Equal(t, "516c696e6b20546f6b656e", r.Stack[0].Value)
+	assert.Equal(t, helper.HexToBytes("516c696e6b20546f6b656e"), r.Stack[0].Value)
 }
 
 //=== RUN   TestRpcClient_InvokeFunction
@@ -472,7 +472,7 @@ func TestRpcClient_InvokeScript(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "00c1046e616d656763d26113bac4208254d98a3eebaee66230ead7b9", r.Script)
 	assert.Equal(t, "HALT", r.State)
-	assert.Equal(t, "516c696e6b20546f6b656e", r.Stack[0].Value)
+	assert.Equal(t, helper.HexToBytes("516c696e6b20546f6b656e"), r.Stack[0].Value)
 }
 
 //=== RUN   TestRpcClient_InvokeScript
@@ -2,10 +2,15 @@ package rpc
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/url"
 	"testing"
@@ -196,8 +201,13 @@ func TestRpcClient_GetApplicationLog(t *testing.T) {
 	assert.Equal(t, "Application", e.Trigger)
 	assert.Equal(t, "0x003bd113b3bc841657f3a84db8546daa6e4953c3", e.Contract)
 	assert.Equal(t, "HALT", e.VMState)
+	assert.Equal(t, []sc.ContractParameter{{Type: sc.Integer, Value: *big.NewInt(1)}}, e.Stack)
 	n := e.Notifications[0]
 	assert.Equal(t, "0xb9d7ea3062e6aeeb3e8ad9548220c4ba1361d263", n.Contract)
+	assert.Equal(t, sc.Array, n.State.Type)
+	state := n.State.Value.([]sc.ContractParameter)
+	assert.Equal(t, 4, len(state))
+	assert.Equal(t, []byte("transfer"), state[0].Value)
 }
 
 func TestRpcClient_GetAssetState(t *testing.T) {
@@ -718,7 +728,9 @@ func TestRpcClient_GetClaimable(t *testing.T) {
 	assert.Equal(t, "52ba70ef18e879785572c917795cd81422c3820b8cf44c24846a30ee7376fd77", c.TxId)
 	assert.Equal(t, 1, c.N)
 	assert.Equal(t, "AGofsxAUDwt52KjaB664GYsqVAkULYvKNt", r.Address)
-	assert.Equal(t, 750.032, r.Unclaimed)
+	assert.Equal(t, int64(75003200000), r.Unclaimed.Value)
+	assert.Equal(t, int64(74611200000), c.Generated.Value)
+	assert.Equal(t, int64(392000000), c.SysFee.Value)
 }
 
 func TestRpcClient_GetConnectionCount(t *testing.T) {
@@ -1037,8 +1049,8 @@ func TestRpcClient_GetUnclaimed(t *testing.T) {
 
 	response := rpc.GetUnclaimed("")
 	r := response.Result
-	assert.Equal(t, float64(4104), r.Available)
-	assert.Equal(t, float64(4096), r.Unavailable)
+	assert.Equal(t, helper.Fixed8FromInt64(4104), r.Available)
+	assert.Equal(t, helper.Fixed8FromInt64(4096), r.Unavailable)
 }
 
 func TestRpcClient_GetUnclaimedGas(t *testing.T) {
@@ -1128,6 +1140,10 @@ func TestRpcClient_GetUnspents(t *testing.T) {
 	assert.Equal(t, "GAS", r.Balances[0].Asset)
 	assert.Equal(t, "c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b", r.Balances[1].AssetHash)
 	assert.Equal(t, "NEO", r.Balances[1].Asset)
+	// the values are decoded exactly, so they add up to the amount
+	assert.Equal(t, int64(60041014479), r.Balances[0].Unspents[4].Value.Value)
+	assert.Equal(t, r.Balances[0].Amount, models.UnspentSlice(r.Balances[0].Unspents).Sum())
+	assert.Equal(t, helper.Fixed8FromInt64(800000), r.Balances[1].Amount)
 }
 
 func TestRpcClient_GetValidators(t *testing.T) {
@@ -1275,7 +1291,7 @@ func TestRpcClient_InvokeFunction(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "00c1046e616d656763d26113bac4208254d98a3eebaee66230ead7b9", r.Script)
 	assert.Equal(t, "HALT", r.State)
-	assert.Equal(t, "516c696e6b20546f6b656e", r.Stack[0].Value)
+	assert.Equal(t, helper.HexToBytes("516c696e6b20546f6b656e"), r.Stack[0].Value)
 }
 
 func TestRpcClient_InvokeScript(t *testing.T) {
@@ -1303,7 +1319,7 @@ func TestRpcClient_InvokeScript(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "00c1046e616d656763d26113bac4208254d98a3eebaee66230ead7b9", r.Script)
 	assert.Equal(t, "HALT", r.State)
-	assert.Equal(t, "516c696e6b20546f6b656e", r.Stack[0].Value)
+	assert.Equal(t, helper.HexToBytes("516c696e6b20546f6b656e"), r.Stack[0].Value)
 }
 
 func TestRpcClient_InvokeScript2(t *testing.T) {
@@ -1340,10 +1356,10 @@ func TestRpcClient_InvokeScript2(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "00c1046e616d656763d26113bac4208254d98a3eebaee66230ead7b9", r.Script)
 	assert.Equal(t, "HALT", r.State)
-	assert.Equal(t, "Array", r.Stack[0].Type)
-	r.Stack[0].Convert()
-	var a = r.Stack[0].Value.([]models.InvokeStack)
-	assert.Equal(t, "90aaf35d", a[0].Value)
+	assert.Equal(t, sc.Array, r.Stack[0].Type)
+	var a = r.Stack[0].Value.([]sc.ContractParameter)
+	assert.Equal(t, helper.HexToBytes("90aaf35d"), a[0].Value)
+	assert.Equal(t, *big.NewInt(1607668937), a[1].Value)
 }
 
 func TestRpcClient_ListAddress(t *testing.T) {
@@ -1604,6 +1620,44 @@ func TestRpcClient_ValidateAddress(t *testing.T) {
 	assert.Equal(t, true, r.IsValid)
 }
 
+func TestRpcClient_WithContext(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Context() == ctx
+	})).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": 2023
+		}`))),
+	}, nil)
+
+	c := rpc.WithContext(ctx)
+	assert.Equal(t, ctx, c.Context())
+	assert.Equal(t, context.Background(), rpc.Context())
+	response := c.GetBlockCount()
+	assert.False(t, response.HasError())
+	assert.Equal(t, 2023, response.Result)
+}
+
+func TestRpcClient_WithContext_Cancelled(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.On("Do", mock.Anything).Return((*http.Response)(nil), context.Canceled)
+
+	response := rpc.WithContext(ctx).GetBlockCount()
+	assert.True(t, response.HasError())
+	var ce *ContextError
+	assert.True(t, errors.As(response.Err, &ce))
+	assert.Equal(t, "getblockcount", ce.Method)
+	assert.True(t, errors.Is(response.Err, context.Canceled))
+}
+
 //var assets = []string{"8d51e5d75ec9adf8080213e0f310e78b0063f50d",
 //	"b6cb731f90cefebbd4f9cedd0cf56bd1e21967f4",
 //	"9a9db8a30a80951ec792effb9731af79781177c2",
@@ -1726,3 +1780,20 @@ func TestRpcClient_ValidateAddress(t *testing.T) {
 //
 //	log.Printf("balance: %s", amount.String())
 //}
+
+func TestInvokeScriptWithWitnesses(t *testing.T) {
+	clientMock := new(RpcClientMock)
+	clientMock.On("InvokeScriptWithWitnesses", "51", []string{"a", "b"}).Return(InvokeScriptResponse{
+		Result: models.InvokeResult{State: "HALT"},
+	})
+	clientMock.On("InvokeScript", "51", "a").Return(InvokeScriptResponse{
+		Result: models.InvokeResult{State: "FAULT"},
+	})
+	response := InvokeScriptWithWitnesses(clientMock, "51", []string{"a", "b"})
+	assert.Equal(t, "HALT", response.Result.State)
+
+	// a client implementing IRpcClient only is given the first hash
+	client := struct{ IRpcClient }{clientMock}
+	response = InvokeScriptWithWitnesses(client, "51", []string{"a", "b"})
+	assert.Equal(t, "FAULT", response.Result.State)
+}
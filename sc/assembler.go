@@ -0,0 +1,211 @@
+package sc
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+)
+
+// Assemble encodes the text of DisassembleToString to a script. There is one instruction per line,
+// the offsets before ":" and the comments after ";" are ignored. Besides the opcodes, PUSH emits
+// the shortest push of an integer, a boolean, a 0x prefixed byte array or a quoted string:
+//
+//	PUSH 100
+//	PUSH true
+//	PUSH 0x0102
+//	PUSH "transfer"
+//	APPCALL 0x14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26
+//	SYSCALL Neo.Runtime.Notify
+//	CALL_E 1 2 0x14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26
+func Assemble(text string) ([]byte, error) {
+	sb := NewScriptBuilder()
+	for n, line := range strings.Split(text, "\n") {
+		if err := assembleLine(&sb, line); err != nil {
+			return nil, fmt.Errorf("line %d: %v", n+1, err)
+		}
+	}
+	return sb.ToArray(), nil
+}
+
+func assembleLine(sb *ScriptBuilder, line string) error {
+	// quoted strings may contain ";" and ":"
+	if i := indexUnquoted(line, ';'); i >= 0 {
+		line = line[:i]
+	}
+	if i := indexUnquoted(line, ':'); i >= 0 {
+		line = line[i+1:]
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+	name, operand := line, ""
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		name, operand = line[:i], strings.TrimSpace(line[i+1:])
+	}
+	if name == "PUSH" {
+		return emitPush(sb, operand)
+	}
+	op, err := OpCodeFromString(name)
+	if err != nil {
+		return err
+	}
+	if op >= PUSHBYTES1 && op <= PUSHDATA4 {
+		data, err := parseHex(operand)
+		if err != nil {
+			return err
+		}
+		return emitPushData(sb, op, data)
+	}
+	switch op {
+	case JMP, JMPIF, JMPIFNOT, CALL:
+		offset, err := strconv.ParseInt(operand, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid jump offset %s", operand)
+		}
+		return sb.EmitJump(op, int16(offset))
+	case APPCALL, TAILCALL:
+		scriptHash, err := helper.UInt160FromString(operand)
+		if err != nil {
+			return err
+		}
+		return sb.EmitAppCall(scriptHash.Bytes(), op == TAILCALL)
+	case SYSCALL:
+		if strings.HasPrefix(operand, "0x") {
+			id, err := parseHex(operand)
+			if err != nil {
+				return err
+			}
+			if len(id) != 4 {
+				return fmt.Errorf("invalid syscall id %s", operand)
+			}
+			return sb.Emit(SYSCALL, append([]byte{4}, id...)...)
+		}
+		return sb.EmitVmSysCall(operand, false)
+	case CALL_I, CALL_E, CALL_ED, CALL_ET, CALL_EDT:
+		return emitCallWithCounts(sb, op, strings.Fields(operand))
+	}
+	if operand != "" {
+		return fmt.Errorf("unexpected operand %s of %s", operand, name)
+	}
+	return sb.Emit(op)
+}
+
+// emitPushData emits op with data, the size of data must match op
+func emitPushData(sb *ScriptBuilder, op OpCode, data []byte) error {
+	var prefix []byte
+	switch op {
+	case PUSHDATA1:
+		if len(data) > 0xff {
+			return fmt.Errorf("data too long for PUSHDATA1")
+		}
+		prefix = []byte{byte(len(data))}
+	case PUSHDATA2:
+		if len(data) > 0xffff {
+			return fmt.Errorf("data too long for PUSHDATA2")
+		}
+		prefix = make([]byte, 2)
+		binary.LittleEndian.PutUint16(prefix, uint16(len(data)))
+	case PUSHDATA4:
+		prefix = make([]byte, 4)
+		binary.LittleEndian.PutUint32(prefix, uint32(len(data)))
+	default:
+		if len(data) != int(op) {
+			return fmt.Errorf("%s expects %d bytes, got %d", op.String(), int(op), len(data))
+		}
+		return sb.EmitPushBytes(data)
+	}
+	return sb.Emit(op, append(prefix, data...)...)
+}
+
+// emitCallWithCounts emits the stack isolated calls, the fields are the numbers of the return values and the
+// parameters, followed by the jump offset of CALL_I or the script hash of CALL_E and CALL_ET
+func emitCallWithCounts(sb *ScriptBuilder, op OpCode, fields []string) error {
+	n := 2
+	if op == CALL_I || op == CALL_E || op == CALL_ET {
+		n = 3
+	}
+	if len(fields) != n {
+		return fmt.Errorf("%s expects %d operands, got %d", op.String(), n, len(fields))
+	}
+	arg := make([]byte, 2)
+	for i := range arg {
+		count, err := strconv.ParseUint(fields[i], 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid count %s", fields[i])
+		}
+		arg[i] = byte(count)
+	}
+	switch op {
+	case CALL_I:
+		offset, err := strconv.ParseInt(fields[2], 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid jump offset %s", fields[2])
+		}
+		arg = append(arg, byte(offset), byte(offset>>8))
+	case CALL_E, CALL_ET:
+		scriptHash, err := helper.UInt160FromString(fields[2])
+		if err != nil {
+			return err
+		}
+		arg = append(arg, scriptHash.Bytes()...)
+	}
+	return sb.Emit(op, arg...)
+}
+
+func emitPush(sb *ScriptBuilder, operand string) error {
+	switch {
+	case operand == "true" || operand == "false":
+		return sb.EmitPushBool(operand == "true")
+	case strings.HasPrefix(operand, "0x"):
+		data, err := parseHex(operand)
+		if err != nil {
+			return err
+		}
+		return sb.EmitPushBytes(data)
+	case strings.HasPrefix(operand, "\""):
+		s, err := strconv.Unquote(operand)
+		if err != nil {
+			return fmt.Errorf("invalid string %s", operand)
+		}
+		return sb.EmitPushString(s)
+	default:
+		i, ok := new(big.Int).SetString(operand, 10)
+		if !ok {
+			return fmt.Errorf("invalid push operand %s", operand)
+		}
+		return sb.EmitPushBigInt(*i)
+	}
+}
+
+func parseHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, fmt.Errorf("expected 0x prefixed hex, got %s", s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex %s", s)
+	}
+	return b, nil
+}
+
+// indexUnquoted returns the index of the first c out of the double quoted strings
+func indexUnquoted(s string, c byte) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quoted:
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == c && !quoted:
+			return i
+		}
+	}
+	return -1
+}
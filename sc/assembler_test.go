@@ -0,0 +1,82 @@
+package sc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssemble(t *testing.T) {
+	script, err := Assemble(`
+		PUSH 0x03b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe55444426619
+		CHECKSIG ; standard account
+	`)
+	assert.Nil(t, err)
+	assert.Equal(t, "2103b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe55444426619ac", helper.BytesToHex(script))
+}
+
+func TestAssemble_Invocation(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26")
+	sb := NewScriptBuilder()
+	sb.MakeInvocationScript(scriptHash.Bytes(), "transfer;a:b", []ContractParameter{
+		{Type: Integer, Value: *big.NewInt(1000)},
+		{Type: Boolean, Value: true},
+	})
+
+	script, err := Assemble(`
+		PUSH true
+		PUSH 1000
+		PUSH 2
+		PACK
+		PUSH "transfer;a:b"
+		APPCALL 0x14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26
+	`)
+	assert.Nil(t, err)
+	assert.Equal(t, sb.ToArray(), script)
+}
+
+func TestAssemble_RoundTrip(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26")
+	sb := NewScriptBuilder()
+	_ = sb.Emit(PUSHDATA2, 0x02, 0x00, 0xab, 0xcd) // not the shortest push
+	_ = sb.EmitPushBytes(make([]byte, 300))
+	_ = sb.EmitPushInt(-1)
+	_ = sb.EmitJump(JMP, -3)
+	_ = sb.EmitAppCall(make([]byte, 20), false)
+	_ = sb.EmitAppCall(scriptHash.Bytes(), false)
+	_ = sb.EmitVmSysCall("Neo.Storage.Put", false)
+	_ = sb.EmitVmSysCall("System.Storage.Get", true)
+	_ = sb.EmitVmSysCall("Unknown.Method", true)
+	_ = sb.Emit(VERIFY)
+	_ = sb.Emit(CALL_I, 1, 2, 0xfb, 0xff)
+	_ = sb.Emit(CALL_E, append([]byte{1, 2}, scriptHash.Bytes()...)...)
+	_ = sb.Emit(CALL_ET, append([]byte{0, 3}, scriptHash.Bytes()...)...)
+	_ = sb.Emit(CALL_ED, 1, 0)
+	_ = sb.Emit(CALL_EDT, 0, 1)
+	_ = sb.Emit(THROWIFNOT)
+
+	text, err := DisassembleToString(sb.ToArray())
+	assert.Nil(t, err)
+	script, err := Assemble(text)
+	assert.Nil(t, err)
+	assert.Equal(t, sb.ToArray(), script)
+}
+
+func TestAssemble_Invalid(t *testing.T) {
+	_, err := Assemble("FOO")
+	assert.NotNil(t, err)
+	_, err = Assemble("PUSHBYTES2 0x01")
+	assert.NotNil(t, err)
+	_, err = Assemble("ADD 1")
+	assert.NotNil(t, err)
+	_, err = Assemble("JMP 40000")
+	assert.NotNil(t, err)
+	_, err = Assemble("SYSCALL 0x0102")
+	assert.NotNil(t, err)
+	_, err = Assemble("CALL_E 1 2")
+	assert.NotNil(t, err)
+	_, err = Assemble("CALL_ED 1 256")
+	assert.NotNil(t, err)
+}
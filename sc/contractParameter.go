@@ -1,5 +1,11 @@
 package sc
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
 type ContractParameterType byte
 
 const (
@@ -25,3 +31,72 @@ type ContractParameter struct {
 	Type  ContractParameterType
 	Value interface{}
 }
+
+func (t ContractParameterType) String() string {
+	switch t {
+	case Signature:
+		return "Signature"
+	case Boolean:
+		return "Boolean"
+	case Integer:
+		return "Integer"
+	case Hash160:
+		return "Hash160"
+	case Hash256:
+		return "Hash256"
+	case ByteArray:
+		return "ByteArray"
+	case PublicKey:
+		return "PublicKey"
+	case String:
+		return "String"
+	case Array:
+		return "Array"
+	case Map:
+		return "Map"
+	case InteropInterface:
+		return "InteropInterface"
+	case Any:
+		return "Any"
+	case Void:
+		return "Void"
+	default:
+		return "ContractParameterType=" + strconv.FormatUint(uint64(t), 10)
+	}
+}
+
+// NewContractParameterTypeFromString parses the type name used in json, such as "Hash160"
+func NewContractParameterTypeFromString(s string) (ContractParameterType, error) {
+	for _, t := range []ContractParameterType{Signature, Boolean, Integer, Hash160, Hash256, ByteArray,
+		PublicKey, String, Array, Map, InteropInterface, Any, Void} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return Void, fmt.Errorf("unknown contract parameter type: %s", s)
+}
+
+// MarshalJSON encodes the type as its name, such as "Hash160"
+func (t ContractParameterType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON decodes the type from its name
+func (t *ContractParameterType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := NewContractParameterTypeFromString(s)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// ContractParameterPair is an entry of a Map parameter, the Value of a Map parameter is []ContractParameterPair
+type ContractParameterPair struct {
+	Key   ContractParameter `json:"key"`
+	Value ContractParameter `json:"value"`
+}
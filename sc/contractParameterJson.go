@@ -0,0 +1,253 @@
+package sc
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+)
+
+// contractParameterJson is the json format of neo-cli and neon-js, such as {"type": "Integer", "value": "1"}
+type contractParameterJson struct {
+	Type  ContractParameterType `json:"type"`
+	Value json.RawMessage       `json:"value,omitempty"`
+}
+
+// MarshalJSON encodes the parameter the same as neo-cli:
+//
+//	Signature, ByteArray, PublicKey    a hex string
+//	Boolean                            true or false
+//	Integer                            a decimal string
+//	Hash160, Hash256                   a big endian hex string with the 0x prefix
+//	String                             a string
+//	Array                              an array of parameters
+//	Map                                an array of {"key": parameter, "value": parameter}
+//
+// the value of the other types is omitted.
+func (p ContractParameter) MarshalJSON() ([]byte, error) {
+	var value interface{}
+	switch p.Type {
+	case Signature, ByteArray, PublicKey:
+		b, ok := p.Value.([]byte)
+		if !ok {
+			return nil, parameterValueError(p)
+		}
+		value = helper.BytesToHex(b)
+	case Boolean:
+		b, ok := p.Value.(bool)
+		if !ok {
+			return nil, parameterValueError(p)
+		}
+		value = b
+	case Integer:
+		i, ok := toBigInt(p.Value)
+		if !ok {
+			return nil, parameterValueError(p)
+		}
+		value = i.String()
+	case Hash160:
+		switch v := p.Value.(type) {
+		case helper.UInt160:
+			value = "0x" + v.String()
+		case []byte:
+			u, err := helper.UInt160FromBytes(v)
+			if err != nil {
+				return nil, err
+			}
+			value = "0x" + u.String()
+		default:
+			return nil, parameterValueError(p)
+		}
+	case Hash256:
+		switch v := p.Value.(type) {
+		case helper.UInt256:
+			value = "0x" + v.String()
+		case []byte:
+			u, err := helper.UInt256FromBytes(v)
+			if err != nil {
+				return nil, err
+			}
+			value = "0x" + u.String()
+		default:
+			return nil, parameterValueError(p)
+		}
+	case String:
+		s, ok := p.Value.(string)
+		if !ok {
+			return nil, parameterValueError(p)
+		}
+		value = s
+	case Array:
+		a, ok := p.Value.([]ContractParameter)
+		if !ok {
+			return nil, parameterValueError(p)
+		}
+		if a == nil {
+			a = []ContractParameter{}
+		}
+		value = a
+	case Map:
+		pairs, ok := p.Value.([]ContractParameterPair)
+		if !ok {
+			return nil, parameterValueError(p)
+		}
+		if pairs == nil {
+			pairs = []ContractParameterPair{}
+		}
+		value = pairs
+	}
+	cpj := contractParameterJson{Type: p.Type}
+	if value != nil {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		cpj.Value = raw
+	}
+	return json.Marshal(cpj)
+}
+
+// ByteEncoding is the encoding of the byte arrays in json, some strings are valid in both encodings
+// such as "AAAA", so it must be known by the decoder
+type ByteEncoding byte
+
+const (
+	HexEncoding    ByteEncoding = iota // neo-cli and the rpc of neo 2.x
+	Base64Encoding                     // neon-js and neo 3.x
+)
+
+// UnmarshalJSON decodes the parameter to the Go types of ContractParameter, the byte arrays are hex strings
+// of neo 2.x, and the integers can be strings or numbers. A Struct of the NeoVM is decoded as an Array.
+// Use UnmarshalContractParameter for the base64 strings.
+func (p *ContractParameter) UnmarshalJSON(data []byte) error {
+	cp, err := UnmarshalContractParameter(data, HexEncoding)
+	if err != nil {
+		return err
+	}
+	*p = cp
+	return nil
+}
+
+// UnmarshalContractParameter decodes a parameter the same as UnmarshalJSON with the byte arrays in encoding
+func UnmarshalContractParameter(data []byte, encoding ByteEncoding) (ContractParameter, error) {
+	var raw struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return ContractParameter{}, err
+	}
+	if raw.Type == "Struct" {
+		raw.Type = Array.String()
+	}
+	t, err := NewContractParameterTypeFromString(raw.Type)
+	if err != nil {
+		return ContractParameter{}, err
+	}
+	cp := ContractParameter{Type: t}
+	if len(raw.Value) == 0 || string(raw.Value) == "null" {
+		return cp, nil
+	}
+	switch t {
+	case Signature, ByteArray, PublicKey:
+		var s string
+		if err = json.Unmarshal(raw.Value, &s); err != nil {
+			return ContractParameter{}, err
+		}
+		b, err := decodeBytes(s, encoding)
+		if err != nil {
+			return ContractParameter{}, fmt.Errorf("invalid %s value %s", t.String(), s)
+		}
+		cp.Value = b
+	case Boolean:
+		var b bool
+		if err = json.Unmarshal(raw.Value, &b); err != nil {
+			// some nodes quote the booleans
+			var s string
+			if json.Unmarshal(raw.Value, &s) != nil || (s != "true" && s != "false") {
+				return ContractParameter{}, fmt.Errorf("invalid Boolean value %s", string(raw.Value))
+			}
+			b = s == "true"
+		}
+		cp.Value = b
+	case Integer:
+		s := strings.Trim(string(raw.Value), "\"")
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return ContractParameter{}, fmt.Errorf("invalid Integer value %s", string(raw.Value))
+		}
+		cp.Value = *i
+	case Hash160:
+		var s string
+		if err = json.Unmarshal(raw.Value, &s); err != nil {
+			return ContractParameter{}, err
+		}
+		u, err := helper.UInt160FromString(s)
+		if err != nil {
+			return ContractParameter{}, err
+		}
+		cp.Value = u.Bytes()
+	case Hash256:
+		var s string
+		if err = json.Unmarshal(raw.Value, &s); err != nil {
+			return ContractParameter{}, err
+		}
+		u, err := helper.UInt256FromString(s)
+		if err != nil {
+			return ContractParameter{}, err
+		}
+		cp.Value = u.Bytes()
+	case String:
+		var s string
+		if err = json.Unmarshal(raw.Value, &s); err != nil {
+			return ContractParameter{}, err
+		}
+		cp.Value = s
+	case Array:
+		var items []json.RawMessage
+		if err = json.Unmarshal(raw.Value, &items); err != nil {
+			return ContractParameter{}, err
+		}
+		a := make([]ContractParameter, len(items))
+		for i, item := range items {
+			if a[i], err = UnmarshalContractParameter(item, encoding); err != nil {
+				return ContractParameter{}, err
+			}
+		}
+		cp.Value = a
+	case Map:
+		var items []struct {
+			Key   json.RawMessage `json:"key"`
+			Value json.RawMessage `json:"value"`
+		}
+		if err = json.Unmarshal(raw.Value, &items); err != nil {
+			return ContractParameter{}, err
+		}
+		pairs := make([]ContractParameterPair, len(items))
+		for i, item := range items {
+			if pairs[i].Key, err = UnmarshalContractParameter(item.Key, encoding); err != nil {
+				return ContractParameter{}, err
+			}
+			if pairs[i].Value, err = UnmarshalContractParameter(item.Value, encoding); err != nil {
+				return ContractParameter{}, err
+			}
+		}
+		cp.Value = pairs
+	}
+	return cp, nil
+}
+
+func decodeBytes(s string, encoding ByteEncoding) ([]byte, error) {
+	switch encoding {
+	case HexEncoding:
+		return hex.DecodeString(s)
+	case Base64Encoding:
+		return base64.StdEncoding.DecodeString(s)
+	default:
+		return nil, fmt.Errorf("unknown byte encoding %d", encoding)
+	}
+}
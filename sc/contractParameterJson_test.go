@@ -0,0 +1,94 @@
+package sc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractParameter_MarshalJSON(t *testing.T) {
+	u, _ := helper.UInt160FromString("14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26")
+	cp := ContractParameter{Type: Array, Value: []ContractParameter{
+		{Type: ByteArray, Value: []byte{1, 2}},
+		{Type: Integer, Value: *big.NewInt(-5)},
+		{Type: Boolean, Value: true},
+		{Type: Hash160, Value: u.Bytes()},
+		{Type: String, Value: "a"},
+		{Type: Map, Value: []ContractParameterPair{
+			{Key: ContractParameter{Type: String, Value: "k"}, Value: ContractParameter{Type: Array, Value: []ContractParameter{}}},
+		}},
+		{Type: InteropInterface},
+	}}
+	b, err := json.Marshal(cp)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"Array","value":[`+
+		`{"type":"ByteArray","value":"0102"},`+
+		`{"type":"Integer","value":"-5"},`+
+		`{"type":"Boolean","value":true},`+
+		`{"type":"Hash160","value":"0x14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26"},`+
+		`{"type":"String","value":"a"},`+
+		`{"type":"Map","value":[{"key":{"type":"String","value":"k"},"value":{"type":"Array","value":[]}}]},`+
+		`{"type":"InteropInterface"}]}`, string(b))
+
+	var decoded ContractParameter
+	err = json.Unmarshal(b, &decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, cp, decoded)
+
+	_, err = json.Marshal(ContractParameter{Type: Integer, Value: "1"})
+	assert.NotNil(t, err)
+}
+
+func TestContractParameter_UnmarshalJSON(t *testing.T) {
+	var cp ContractParameter
+	err := json.Unmarshal([]byte(`{"type": "Struct", "value": [
+		{"type": "ByteArray", "value": "0102"},
+		{"type": "Integer", "value": 100},
+		{"type": "Boolean", "value": "true"},
+		{"type": "Hash256", "value": "c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b"}
+	]}`), &cp)
+	assert.Nil(t, err)
+	assert.Equal(t, Array, cp.Type)
+	items := cp.Value.([]ContractParameter)
+	assert.Equal(t, []byte{1, 2}, items[0].Value)
+	assert.Equal(t, *big.NewInt(100), items[1].Value)
+	assert.Equal(t, true, items[2].Value)
+	u, _ := helper.UInt256FromString("c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b")
+	assert.Equal(t, u.Bytes(), items[3].Value)
+
+	err = json.Unmarshal([]byte(`{"type": "Integer", "value": "1.5"}`), &cp)
+	assert.NotNil(t, err)
+	err = json.Unmarshal([]byte(`{"type": "Bytes", "value": "00"}`), &cp)
+	assert.NotNil(t, err)
+	err = json.Unmarshal([]byte(`{"type": "ByteArray", "value": "!"}`), &cp)
+	assert.NotNil(t, err)
+}
+
+func TestUnmarshalContractParameter(t *testing.T) {
+	// "AAAA" is valid hex and base64, it is decoded in the encoding given
+	data := []byte(`{"type": "Array", "value": [
+		{"type": "ByteArray", "value": "AAAA"},
+		{"type": "Map", "value": [{"key": {"type": "ByteArray", "value": "EAAA"}, "value": {"type": "Signature", "value": "AQI="}}]}
+	]}`)
+	cp, err := UnmarshalContractParameter(data, Base64Encoding)
+	assert.Nil(t, err)
+	items := cp.Value.([]ContractParameter)
+	assert.Equal(t, []byte{0, 0, 0}, items[0].Value)
+	pairs := items[1].Value.([]ContractParameterPair)
+	assert.Equal(t, []byte{0x10, 0, 0}, pairs[0].Key.Value)
+	assert.Equal(t, []byte{1, 2}, pairs[0].Value.Value)
+
+	cp, err = UnmarshalContractParameter([]byte(`{"type": "ByteArray", "value": "AAAA"}`), HexEncoding)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0xaa, 0xaa}, cp.Value)
+
+	// base64 is not taken for hex
+	var p ContractParameter
+	err = json.Unmarshal([]byte(`{"type": "ByteArray", "value": "AQI="}`), &p)
+	assert.NotNil(t, err)
+	_, err = UnmarshalContractParameter([]byte(`{"type": "ByteArray", "value": "01"}`), Base64Encoding)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,297 @@
+package sc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+)
+
+// compressedPublicKey is implemented by *keys.PublicKey, which can not be imported here
+type compressedPublicKey interface {
+	EncodeCompression() []byte
+}
+
+// NewContractParameter converts v to a parameter of the type of its Go type:
+//
+//	bool                                   Boolean
+//	int, int64, uint64 and other integers  Integer
+//	big.Int, *big.Int                      Integer
+//	helper.UInt160                         Hash160
+//	helper.UInt256                         Hash256
+//	[]byte                                 ByteArray
+//	*keys.PublicKey                        PublicKey
+//	string                                 String
+//	a slice or an array                    Array, the items are converted by NewContractParameter
+//	a map                                  Map, the entries are sorted by the keys
+//	ContractParameter                      itself
+func NewContractParameter(v interface{}) (ContractParameter, error) {
+	switch value := v.(type) {
+	case nil:
+		return ContractParameter{}, fmt.Errorf("nil can not be converted to a contract parameter")
+	case ContractParameter:
+		return value, nil
+	case bool:
+		return NewContractParameterWithType(Boolean, v)
+	case helper.UInt160:
+		return NewContractParameterWithType(Hash160, v)
+	case helper.UInt256:
+		return NewContractParameterWithType(Hash256, v)
+	case []byte:
+		return NewContractParameterWithType(ByteArray, v)
+	case compressedPublicKey:
+		return NewContractParameterWithType(PublicKey, v)
+	case string:
+		return NewContractParameterWithType(String, v)
+	}
+	if _, ok := toBigInt(v); ok {
+		return NewContractParameterWithType(Integer, v)
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Slice, reflect.Array:
+		return NewContractParameterWithType(Array, v)
+	case reflect.Map:
+		return NewContractParameterWithType(Map, v)
+	default:
+		return ContractParameter{}, fmt.Errorf("%T can not be converted to a contract parameter", v)
+	}
+}
+
+// NewContractParameterWithType converts v to a parameter of type t, the Go types accepted besides
+// the ones of NewContractParameter are:
+//
+//	Hash160            an address, a hex string, []byte of 20 bytes
+//	Hash256            a hex string, []byte of 32 bytes
+//	Signature          []byte of 64 bytes
+//	PublicKey          a hex string, []byte of 33 bytes
+//	Array              []ContractParameter
+//	Map                []ContractParameterPair
+//	Any                any type of NewContractParameter
+func NewContractParameterWithType(t ContractParameterType, v interface{}) (ContractParameter, error) {
+	if cp, ok := v.(ContractParameter); ok {
+		if cp.Type != t && t != Any {
+			return cp, fmt.Errorf("expected %s, got %s", t.String(), cp.Type.String())
+		}
+		return cp, nil
+	}
+	if t == Any {
+		return NewContractParameter(v)
+	}
+	cp := ContractParameter{Type: t}
+	switch t {
+	case Boolean:
+		b, ok := v.(bool)
+		if !ok {
+			return cp, typeError(t, v)
+		}
+		cp.Value = b
+	case Integer:
+		i, ok := toBigInt(v)
+		if !ok {
+			return cp, typeError(t, v)
+		}
+		cp.Value = *i
+	case Hash160:
+		var u helper.UInt160
+		var err error
+		switch value := v.(type) {
+		case helper.UInt160:
+			u = value
+		case []byte:
+			u, err = helper.UInt160FromBytes(value)
+		case string:
+			if len(value) == 34 {
+				u, err = helper.AddressToScriptHash(value)
+			} else {
+				u, err = helper.UInt160FromString(value)
+			}
+		default:
+			return cp, typeError(t, v)
+		}
+		if err != nil {
+			return cp, err
+		}
+		cp.Value = u.Bytes()
+	case Hash256:
+		var u helper.UInt256
+		var err error
+		switch value := v.(type) {
+		case helper.UInt256:
+			u = value
+		case []byte:
+			u, err = helper.UInt256FromBytes(value)
+		case string:
+			u, err = helper.UInt256FromString(value)
+		default:
+			return cp, typeError(t, v)
+		}
+		if err != nil {
+			return cp, err
+		}
+		cp.Value = u.Bytes()
+	case ByteArray, Signature:
+		b, ok := v.([]byte)
+		if !ok {
+			return cp, typeError(t, v)
+		}
+		if t == Signature && len(b) != 64 {
+			return cp, fmt.Errorf("signature of size %d", len(b))
+		}
+		cp.Value = b
+	case PublicKey:
+		var b []byte
+		switch value := v.(type) {
+		case []byte:
+			b = value
+		case string:
+			var err error
+			if b, err = hex.DecodeString(strings.TrimPrefix(value, "0x")); err != nil {
+				return cp, err
+			}
+		case compressedPublicKey:
+			if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+				return cp, fmt.Errorf("public key is nil")
+			}
+			b = value.EncodeCompression()
+		default:
+			return cp, typeError(t, v)
+		}
+		if len(b) != 33 || (b[0] != 0x02 && b[0] != 0x03) {
+			return cp, fmt.Errorf("invalid compressed public key %s", helper.BytesToHex(b))
+		}
+		cp.Value = b
+	case String:
+		s, ok := v.(string)
+		if !ok {
+			return cp, typeError(t, v)
+		}
+		cp.Value = s
+	case Array:
+		if cps, ok := v.([]ContractParameter); ok {
+			cp.Value = cps
+			break
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return cp, typeError(t, v)
+		}
+		cps := make([]ContractParameter, rv.Len())
+		for i := range cps {
+			item, err := NewContractParameter(rv.Index(i).Interface())
+			if err != nil {
+				return cp, fmt.Errorf("item %d: %v", i, err)
+			}
+			cps[i] = item
+		}
+		cp.Value = cps
+	case Map:
+		if pairs, ok := v.([]ContractParameterPair); ok {
+			for _, pair := range pairs {
+				if pair.Key.Type == Array || pair.Key.Type == Map {
+					return cp, fmt.Errorf("map key of type %s is not supported", pair.Key.Type.String())
+				}
+			}
+			cp.Value = pairs
+			break
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Map {
+			return cp, typeError(t, v)
+		}
+		pairs := make([]ContractParameterPair, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			key, err := NewContractParameter(k.Interface())
+			if err != nil {
+				return cp, fmt.Errorf("map key: %v", err)
+			}
+			if key.Type == Array || key.Type == Map {
+				return cp, fmt.Errorf("map key of type %s is not supported", key.Type.String())
+			}
+			value, err := NewContractParameter(rv.MapIndex(k).Interface())
+			if err != nil {
+				return cp, fmt.Errorf("map value: %v", err)
+			}
+			pairs = append(pairs, ContractParameterPair{Key: key, Value: value})
+		}
+		// go maps are not ordered, sort the keys to make the same script every time
+		sort.Slice(pairs, func(i, j int) bool {
+			return compareKeys(pairs[i].Key, pairs[j].Key) < 0
+		})
+		cp.Value = pairs
+	default:
+		return cp, fmt.Errorf("parameter type %s is not supported", t.String())
+	}
+	return cp, nil
+}
+
+// compareKeys orders the map keys by their types and then by their values
+func compareKeys(a, b ContractParameter) int {
+	if a.Type != b.Type {
+		if a.Type < b.Type {
+			return -1
+		}
+		return 1
+	}
+	switch a.Type {
+	case Integer:
+		x, _ := toBigInt(a.Value)
+		y, _ := toBigInt(b.Value)
+		return x.Cmp(y)
+	case Boolean:
+		x, _ := a.Value.(bool)
+		y, _ := b.Value.(bool)
+		if x == y {
+			return 0
+		}
+		if !x {
+			return -1
+		}
+		return 1
+	case String:
+		return strings.Compare(fmt.Sprint(a.Value), fmt.Sprint(b.Value))
+	default:
+		x, _ := a.Value.([]byte)
+		y, _ := b.Value.([]byte)
+		return bytes.Compare(x, y)
+	}
+}
+
+func toBigInt(v interface{}) (*big.Int, bool) {
+	switch i := v.(type) {
+	case *big.Int:
+		return i, i != nil
+	case big.Int:
+		return &i, true
+	case int:
+		return big.NewInt(int64(i)), true
+	case int8:
+		return big.NewInt(int64(i)), true
+	case int16:
+		return big.NewInt(int64(i)), true
+	case int32:
+		return big.NewInt(int64(i)), true
+	case int64:
+		return big.NewInt(i), true
+	case uint:
+		return new(big.Int).SetUint64(uint64(i)), true
+	case uint8:
+		return big.NewInt(int64(i)), true
+	case uint16:
+		return big.NewInt(int64(i)), true
+	case uint32:
+		return big.NewInt(int64(i)), true
+	case uint64:
+		return new(big.Int).SetUint64(i), true
+	default:
+		return nil, false
+	}
+}
+
+func typeError(t ContractParameterType, v interface{}) error {
+	return fmt.Errorf("%T can not be converted to %s", v, t.String())
+}
@@ -0,0 +1,75 @@
+package sc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewContractParameter(t *testing.T) {
+	u, _ := helper.UInt160FromString("14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26")
+	cp, err := NewContractParameter([]interface{}{1, true, "a", []byte{1}, u, []int{2, 3}})
+	assert.Nil(t, err)
+	assert.Equal(t, Array, cp.Type)
+	assert.Equal(t, []ContractParameter{
+		{Type: Integer, Value: *big.NewInt(1)},
+		{Type: Boolean, Value: true},
+		{Type: String, Value: "a"},
+		{Type: ByteArray, Value: []byte{1}},
+		{Type: Hash160, Value: u.Bytes()},
+		{Type: Array, Value: []ContractParameter{
+			{Type: Integer, Value: *big.NewInt(2)},
+			{Type: Integer, Value: *big.NewInt(3)},
+		}},
+	}, cp.Value)
+
+	_, err = NewContractParameter(nil)
+	assert.NotNil(t, err)
+	_, err = NewContractParameter(1.5)
+	assert.NotNil(t, err)
+	_, err = NewContractParameter([]interface{}{struct{}{}})
+	assert.NotNil(t, err)
+}
+
+func TestNewContractParameter_Map(t *testing.T) {
+	cp, err := NewContractParameter(map[interface{}]interface{}{"b": 2, "a": []string{"x"}, 3: false})
+	assert.Nil(t, err)
+	assert.Equal(t, Map, cp.Type)
+	// sorted by the key types and then by the key values
+	assert.Equal(t, []ContractParameterPair{
+		{Key: ContractParameter{Type: Integer, Value: *big.NewInt(3)}, Value: ContractParameter{Type: Boolean, Value: false}},
+		{Key: ContractParameter{Type: String, Value: "a"}, Value: ContractParameter{Type: Array, Value: []ContractParameter{{Type: String, Value: "x"}}}},
+		{Key: ContractParameter{Type: String, Value: "b"}, Value: ContractParameter{Type: Integer, Value: *big.NewInt(2)}},
+	}, cp.Value)
+
+	_, err = NewContractParameter(map[interface{}]int{[2]int{1, 2}: 1})
+	assert.NotNil(t, err)
+}
+
+func TestNewContractParameterWithType(t *testing.T) {
+	cp, err := NewContractParameterWithType(Hash160, "AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+	assert.Nil(t, err)
+	u, _ := helper.AddressToScriptHash("AUrE5r4NHznrgvqoFAGhoUbu96PE5YeDZY")
+	assert.Equal(t, u.Bytes(), cp.Value)
+
+	cp, err = NewContractParameterWithType(PublicKey, "031a6c6fbbdf02ca351745fa86b9ba5a9452d785ac4f7fc2b7548ca2a46c4fcf4a")
+	assert.Nil(t, err)
+	assert.Equal(t, 33, len(cp.Value.([]byte)))
+
+	cp, err = NewContractParameterWithType(Any, uint64(1)<<63)
+	assert.Nil(t, err)
+	assert.Equal(t, Integer, cp.Type)
+
+	_, err = NewContractParameterWithType(Hash256, []byte{1, 2})
+	assert.NotNil(t, err)
+	_, err = NewContractParameterWithType(Signature, []byte{1, 2})
+	assert.NotNil(t, err)
+	_, err = NewContractParameterWithType(PublicKey, []byte{4, 2})
+	assert.NotNil(t, err)
+	_, err = NewContractParameterWithType(Boolean, 1)
+	assert.NotNil(t, err)
+	_, err = NewContractParameterWithType(String, ContractParameter{Type: Integer, Value: *big.NewInt(1)})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,383 @@
+package sc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/joeqian10/neo-gogogo/crypto"
+	"github.com/joeqian10/neo-gogogo/helper"
+)
+
+// SysCallNames are the interop methods of neo 2.x, they are used to resolve the compressed SYSCALL ids
+var SysCallNames = []string{
+	"System.ExecutionEngine.GetScriptContainer",
+	"System.ExecutionEngine.GetExecutingScriptHash",
+	"System.ExecutionEngine.GetCallingScriptHash",
+	"System.ExecutionEngine.GetEntryScriptHash",
+	"System.Runtime.Platform",
+	"System.Runtime.GetTrigger",
+	"System.Runtime.CheckWitness",
+	"System.Runtime.Notify",
+	"System.Runtime.Log",
+	"System.Runtime.GetTime",
+	"System.Runtime.Serialize",
+	"System.Runtime.Deserialize",
+	"System.Blockchain.GetHeight",
+	"System.Blockchain.GetHeader",
+	"System.Blockchain.GetBlock",
+	"System.Blockchain.GetTransaction",
+	"System.Blockchain.GetTransactionHeight",
+	"System.Blockchain.GetContract",
+	"System.Header.GetIndex",
+	"System.Header.GetHash",
+	"System.Header.GetPrevHash",
+	"System.Header.GetTimestamp",
+	"System.Block.GetTransactionCount",
+	"System.Block.GetTransactions",
+	"System.Block.GetTransaction",
+	"System.Transaction.GetHash",
+	"System.Contract.Destroy",
+	"System.Contract.GetStorageContext",
+	"System.Storage.GetContext",
+	"System.Storage.GetReadOnlyContext",
+	"System.Storage.Get",
+	"System.Storage.Put",
+	"System.Storage.PutEx",
+	"System.Storage.Delete",
+	"System.StorageContext.AsReadOnly",
+	"Neo.Runtime.GetTrigger",
+	"Neo.Runtime.CheckWitness",
+	"Neo.Runtime.Notify",
+	"Neo.Runtime.Log",
+	"Neo.Runtime.GetTime",
+	"Neo.Runtime.Serialize",
+	"Neo.Runtime.Deserialize",
+	"Neo.Blockchain.GetHeight",
+	"Neo.Blockchain.GetHeader",
+	"Neo.Blockchain.GetBlock",
+	"Neo.Blockchain.GetTransaction",
+	"Neo.Blockchain.GetTransactionHeight",
+	"Neo.Blockchain.GetAccount",
+	"Neo.Blockchain.GetValidators",
+	"Neo.Blockchain.GetAsset",
+	"Neo.Blockchain.GetContract",
+	"Neo.Header.GetHash",
+	"Neo.Header.GetVersion",
+	"Neo.Header.GetPrevHash",
+	"Neo.Header.GetMerkleRoot",
+	"Neo.Header.GetTimestamp",
+	"Neo.Header.GetIndex",
+	"Neo.Header.GetConsensusData",
+	"Neo.Header.GetNextConsensus",
+	"Neo.Block.GetTransactionCount",
+	"Neo.Block.GetTransactions",
+	"Neo.Block.GetTransaction",
+	"Neo.Transaction.GetHash",
+	"Neo.Transaction.GetType",
+	"Neo.Transaction.GetAttributes",
+	"Neo.Transaction.GetInputs",
+	"Neo.Transaction.GetOutputs",
+	"Neo.Transaction.GetReferences",
+	"Neo.Transaction.GetUnspentCoins",
+	"Neo.Transaction.GetWitnesses",
+	"Neo.InvocationTransaction.GetScript",
+	"Neo.Witness.GetVerificationScript",
+	"Neo.Attribute.GetUsage",
+	"Neo.Attribute.GetData",
+	"Neo.Input.GetHash",
+	"Neo.Input.GetIndex",
+	"Neo.Output.GetAssetId",
+	"Neo.Output.GetValue",
+	"Neo.Output.GetScriptHash",
+	"Neo.Account.GetScriptHash",
+	"Neo.Account.GetVotes",
+	"Neo.Account.GetBalance",
+	"Neo.Account.IsStandard",
+	"Neo.Asset.Create",
+	"Neo.Asset.Renew",
+	"Neo.Asset.GetAssetId",
+	"Neo.Asset.GetAssetType",
+	"Neo.Asset.GetAmount",
+	"Neo.Asset.GetAvailable",
+	"Neo.Asset.GetPrecision",
+	"Neo.Asset.GetOwner",
+	"Neo.Asset.GetAdmin",
+	"Neo.Asset.GetIssuer",
+	"Neo.Contract.Create",
+	"Neo.Contract.Migrate",
+	"Neo.Contract.Destroy",
+	"Neo.Contract.GetScript",
+	"Neo.Contract.IsPayable",
+	"Neo.Contract.GetStorageContext",
+	"Neo.Storage.GetContext",
+	"Neo.Storage.GetReadOnlyContext",
+	"Neo.Storage.Get",
+	"Neo.Storage.Put",
+	"Neo.Storage.Delete",
+	"Neo.Storage.Find",
+	"Neo.StorageContext.AsReadOnly",
+	"Neo.Enumerator.Create",
+	"Neo.Enumerator.Next",
+	"Neo.Enumerator.Value",
+	"Neo.Enumerator.Concat",
+	"Neo.Iterator.Create",
+	"Neo.Iterator.Key",
+	"Neo.Iterator.Keys",
+	"Neo.Iterator.Values",
+	"Neo.Iterator.Concat",
+	"Neo.Iterator.Next",
+	"Neo.Iterator.Value",
+	"AntShares.Runtime.CheckWitness",
+	"AntShares.Runtime.Notify",
+	"AntShares.Runtime.Log",
+	"AntShares.Blockchain.GetHeight",
+	"AntShares.Blockchain.GetHeader",
+	"AntShares.Blockchain.GetBlock",
+	"AntShares.Blockchain.GetTransaction",
+	"AntShares.Blockchain.GetAccount",
+	"AntShares.Blockchain.GetValidators",
+	"AntShares.Blockchain.GetAsset",
+	"AntShares.Blockchain.GetContract",
+	"AntShares.Header.GetHash",
+	"AntShares.Header.GetVersion",
+	"AntShares.Header.GetPrevHash",
+	"AntShares.Header.GetMerkleRoot",
+	"AntShares.Header.GetTimestamp",
+	"AntShares.Header.GetConsensusData",
+	"AntShares.Header.GetNextConsensus",
+	"AntShares.Block.GetTransactionCount",
+	"AntShares.Block.GetTransactions",
+	"AntShares.Block.GetTransaction",
+	"AntShares.Transaction.GetHash",
+	"AntShares.Transaction.GetType",
+	"AntShares.Transaction.GetAttributes",
+	"AntShares.Transaction.GetInputs",
+	"AntShares.Transaction.GetOutputs",
+	"AntShares.Transaction.GetReferences",
+	"AntShares.Attribute.GetUsage",
+	"AntShares.Attribute.GetData",
+	"AntShares.Input.GetHash",
+	"AntShares.Input.GetIndex",
+	"AntShares.Output.GetAssetId",
+	"AntShares.Output.GetValue",
+	"AntShares.Output.GetScriptHash",
+	"AntShares.Account.GetScriptHash",
+	"AntShares.Account.GetVotes",
+	"AntShares.Account.GetBalance",
+	"AntShares.Asset.Create",
+	"AntShares.Asset.Renew",
+	"AntShares.Asset.GetAssetId",
+	"AntShares.Asset.GetAssetType",
+	"AntShares.Asset.GetAmount",
+	"AntShares.Asset.GetAvailable",
+	"AntShares.Asset.GetPrecision",
+	"AntShares.Asset.GetOwner",
+	"AntShares.Asset.GetAdmin",
+	"AntShares.Asset.GetIssuer",
+	"AntShares.Contract.Create",
+	"AntShares.Contract.Migrate",
+	"AntShares.Contract.Destroy",
+	"AntShares.Contract.GetScript",
+	"AntShares.Contract.GetStorageContext",
+	"AntShares.Storage.GetContext",
+	"AntShares.Storage.Get",
+	"AntShares.Storage.Put",
+	"AntShares.Storage.Delete",
+}
+
+var sysCallIds map[uint32]string
+
+func init() {
+	sysCallIds = make(map[uint32]string, len(SysCallNames))
+	for _, name := range SysCallNames {
+		sysCallIds[SysCallId(name)] = name
+	}
+}
+
+// SysCallId returns the compressed id of a SYSCALL name, which is emitted by EmitVmSysCall(api, true)
+func SysCallId(name string) uint32 {
+	return binary.LittleEndian.Uint32(crypto.Sha256([]byte(name))[:4])
+}
+
+// Instruction is an instruction of a script
+type Instruction struct {
+	Offset  int
+	OpCode  OpCode
+	Operand []byte // the operand without its size prefix, the data of PUSHBYTES and PUSHDATA, the hash of APPCALL and so on
+	// the operand of CALL_I, CALL_E, CALL_ED, CALL_ET and CALL_EDT starts with the numbers of the return values
+	// and the parameters, followed by the jump offset of CALL_I or the hash of CALL_E and CALL_ET
+}
+
+// Size returns the size of the instruction in the script
+func (i Instruction) Size() int {
+	return 1 + operandPrefixSize(i.OpCode) + len(i.Operand)
+}
+
+// operandPrefixSize returns the size of the operand size of op
+func operandPrefixSize(op OpCode) int {
+	switch op {
+	case PUSHDATA1, SYSCALL:
+		return 1
+	case PUSHDATA2:
+		return 2
+	case PUSHDATA4:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// JumpTarget returns the offset of the instruction JMP, JMPIF, JMPIFNOT, CALL and CALL_I jump to
+func (i Instruction) JumpTarget() int {
+	return i.Offset + int(i.jumpOffset())
+}
+
+func (i Instruction) jumpOffset() int16 {
+	if i.OpCode == CALL_I {
+		return int16(binary.LittleEndian.Uint16(i.Operand[2:]))
+	}
+	return int16(binary.LittleEndian.Uint16(i.Operand))
+}
+
+// ScriptHash returns the contract called by APPCALL, TAILCALL, CALL_E and CALL_ET, the zero hash is a dynamic call
+func (i Instruction) ScriptHash() helper.UInt160 {
+	operand := i.Operand
+	if i.OpCode == CALL_E || i.OpCode == CALL_ET {
+		operand = operand[2:]
+	}
+	u, _ := helper.UInt160FromBytes(operand)
+	return u
+}
+
+// SysCallName returns the name of the method called by SYSCALL, an empty string if the compressed id is unknown
+func (i Instruction) SysCallName() string {
+	if len(i.Operand) == 4 {
+		return sysCallIds[binary.LittleEndian.Uint32(i.Operand)]
+	}
+	return string(i.Operand)
+}
+
+// String returns the instruction in the format of Assemble:
+//
+//	PUSHBYTES4 0x01020304
+//	JMPIFNOT 5
+//	APPCALL 0x14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26
+//	SYSCALL Neo.Runtime.CheckWitness
+//	SYSCALL 0x9bf667ce ; System.Storage.Get
+//	CALL_I 1 2 5
+//	CALL_E 1 2 0x14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26
+//	CALL_ED 1 2
+func (i Instruction) String() string {
+	if i.OpCode >= PUSHBYTES1 && i.OpCode <= PUSHDATA4 {
+		return i.OpCode.String() + " 0x" + helper.BytesToHex(i.Operand)
+	}
+	switch i.OpCode {
+	case JMP, JMPIF, JMPIFNOT, CALL:
+		return fmt.Sprintf("%s %d", i.OpCode.String(), i.jumpOffset())
+	case CALL_I:
+		return fmt.Sprintf("CALL_I %d %d %d", i.Operand[0], i.Operand[1], i.jumpOffset())
+	case CALL_E, CALL_ET:
+		return fmt.Sprintf("%s %d %d 0x%s", i.OpCode.String(), i.Operand[0], i.Operand[1], i.ScriptHash().String())
+	case CALL_ED, CALL_EDT:
+		return fmt.Sprintf("%s %d %d", i.OpCode.String(), i.Operand[0], i.Operand[1])
+	case APPCALL, TAILCALL:
+		return i.OpCode.String() + " 0x" + i.ScriptHash().String()
+	case SYSCALL:
+		if len(i.Operand) == 4 {
+			s := "SYSCALL 0x" + helper.BytesToHex(i.Operand)
+			if name := i.SysCallName(); name != "" {
+				s += " ; " + name
+			}
+			return s
+		}
+		return "SYSCALL " + string(i.Operand)
+	default:
+		return i.OpCode.String()
+	}
+}
+
+// Disassemble decodes the instructions of script
+func Disassemble(script []byte) ([]Instruction, error) {
+	var instructions []Instruction
+	for offset := 0; offset < len(script); {
+		i, err := decodeInstruction(script, offset)
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, i)
+		offset += i.Size()
+	}
+	return instructions, nil
+}
+
+// DisassembleToString decodes script to text which can be read by Assemble, one instruction per line
+// with its offset and the targets of the jumps:
+//
+//	0000: PUSH0
+//	0001: JMPIFNOT 4 ; 0005
+//	0004: PUSH5
+//	0005: RET
+func DisassembleToString(script []byte) (string, error) {
+	instructions, err := Disassemble(script)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, i := range instructions {
+		sb.WriteString(fmt.Sprintf("%04d: %s", i.Offset, i.String()))
+		switch i.OpCode {
+		case JMP, JMPIF, JMPIFNOT, CALL, CALL_I:
+			sb.WriteString(fmt.Sprintf(" ; %04d", i.JumpTarget()))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+func decodeInstruction(script []byte, offset int) (Instruction, error) {
+	op := OpCode(script[offset])
+	if !op.IsValid() {
+		return Instruction{}, fmt.Errorf("invalid opcode 0x%02x at %d", byte(op), offset)
+	}
+	// n is the operand size
+	prefix, n := operandPrefixSize(op), 0
+	switch {
+	case op >= PUSHBYTES1 && op <= PUSHBYTES75:
+		n = int(op)
+	case op == JMP, op == JMPIF, op == JMPIFNOT, op == CALL:
+		n = 2
+	case op == APPCALL, op == TAILCALL:
+		n = 20
+	case op == CALL_ED, op == CALL_EDT:
+		n = 2
+	case op == CALL_I:
+		n = 4
+	case op == CALL_E, op == CALL_ET:
+		n = 22
+	}
+	start := offset + 1 + prefix
+	if start > len(script) {
+		return Instruction{}, fmt.Errorf("unexpected end of script at %d", offset)
+	}
+	switch prefix {
+	case 1:
+		n = int(script[offset+1])
+	case 2:
+		n = int(binary.LittleEndian.Uint16(script[offset+1:]))
+	case 4:
+		size := binary.LittleEndian.Uint32(script[offset+1:])
+		if uint64(size) > uint64(len(script)) {
+			return Instruction{}, fmt.Errorf("unexpected end of script at %d", offset)
+		}
+		n = int(size)
+	}
+	if start+n > len(script) {
+		return Instruction{}, fmt.Errorf("unexpected end of script at %d", offset)
+	}
+	return Instruction{
+		Offset:  offset,
+		OpCode:  op,
+		Operand: script[start : start+n],
+	}, nil
+}
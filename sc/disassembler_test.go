@@ -0,0 +1,91 @@
+package sc
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisassemble(t *testing.T) {
+	// verification script of a standard account
+	script := helper.HexToBytes("2103b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe55444426619ac")
+	instructions, err := Disassemble(script)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(instructions))
+	assert.Equal(t, OpCode(33), instructions[0].OpCode)
+	assert.Equal(t, "03b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe55444426619", helper.BytesToHex(instructions[0].Operand))
+	assert.Equal(t, 34, instructions[1].Offset)
+	assert.Equal(t, "CHECKSIG", instructions[1].String())
+}
+
+func TestDisassemble_Operands(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26")
+	sb := NewScriptBuilder()
+	_ = sb.EmitPushBytes(make([]byte, 80))
+	_ = sb.EmitJump(JMPIFNOT, 25)
+	_ = sb.EmitAppCall(scriptHash.Bytes(), true)
+	_ = sb.EmitVmSysCall("Neo.Runtime.Notify", false)
+	_ = sb.EmitVmSysCall("System.Storage.Get", true)
+	_ = sb.Emit(RET)
+
+	instructions, err := Disassemble(sb.ToArray())
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(instructions))
+	assert.Equal(t, PUSHDATA1, instructions[0].OpCode)
+	assert.Equal(t, 80, len(instructions[0].Operand))
+	assert.Equal(t, 82, instructions[0].Size())
+	assert.Equal(t, 82, instructions[1].Offset)
+	assert.Equal(t, 107, instructions[1].JumpTarget())
+	assert.Equal(t, scriptHash, instructions[2].ScriptHash())
+	assert.Equal(t, "TAILCALL 0x14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26", instructions[2].String())
+	assert.Equal(t, "Neo.Runtime.Notify", instructions[3].SysCallName())
+	assert.Equal(t, "SYSCALL Neo.Runtime.Notify", instructions[3].String())
+	assert.Equal(t, "System.Storage.Get", instructions[4].SysCallName())
+	assert.Equal(t, "SYSCALL 0x925de831 ; System.Storage.Get", instructions[4].String())
+}
+
+func TestDisassemble_CallWithCounts(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26")
+	sb := NewScriptBuilder()
+	_ = sb.Emit(VERIFY)
+	_ = sb.Emit(CALL_I, 1, 2, 0x07, 0x00)
+	_ = sb.Emit(CALL_E, append([]byte{1, 2}, scriptHash.Bytes()...)...)
+	_ = sb.Emit(CALL_EDT, 0, 1)
+	_ = sb.EmitVmSysCall("Neo.Iterator.Next", true)
+
+	instructions, err := Disassemble(sb.ToArray())
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(instructions))
+	assert.Equal(t, "VERIFY", instructions[0].String())
+	assert.Equal(t, "CALL_I 1 2 7", instructions[1].String())
+	assert.Equal(t, 8, instructions[1].JumpTarget())
+	assert.Equal(t, 6, instructions[2].Offset)
+	assert.Equal(t, scriptHash, instructions[2].ScriptHash())
+	assert.Equal(t, "CALL_E 1 2 0x14df5d02f9a52d3e92ab8cdcce5fc76c743a9b26", instructions[2].String())
+	assert.Equal(t, "CALL_EDT 0 1", instructions[3].String())
+	assert.Equal(t, "Neo.Iterator.Next", instructions[4].SysCallName())
+
+	_, err = Disassemble([]byte{byte(CALL_E), 1, 2})
+	assert.NotNil(t, err)
+}
+
+func TestDisassemble_Invalid(t *testing.T) {
+	_, err := Disassemble([]byte{0x02, 0x01})
+	assert.NotNil(t, err)
+	_, err = Disassemble([]byte{byte(PUSHDATA4), 0xff, 0xff, 0xff, 0xff})
+	assert.NotNil(t, err)
+	_, err = Disassemble([]byte{0xff})
+	assert.NotNil(t, err)
+}
+
+func TestDisassembleToString(t *testing.T) {
+	sb := NewScriptBuilder()
+	_ = sb.Emit(PUSH0)
+	_ = sb.EmitJump(JMPIFNOT, 4)
+	_ = sb.Emit(PUSH5)
+	_ = sb.Emit(RET)
+	s, err := DisassembleToString(sb.ToArray())
+	assert.Nil(t, err)
+	assert.Equal(t, "0000: PUSH0\n0001: JMPIFNOT 4 ; 0005\n0004: PUSH5\n0005: RET\n", s)
+}
@@ -1,5 +1,11 @@
 package sc
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 //https://github.com/neo-project/neo-vm/blob/master/src/neo-vm/OpCode.cs
 
 type OpCode byte
@@ -113,6 +119,7 @@ const (
 	HASH160       OpCode = 0xA9
 	HASH256       OpCode = 0xAA
 	CHECKSIG      OpCode = 0xAC
+	VERIFY        OpCode = 0xAD
 	CHECKMULTISIG OpCode = 0xAE
 
 	// Array
@@ -123,11 +130,83 @@ const (
 	SETITEM   OpCode = 0xC4
 	NEWARRAY  OpCode = 0xC5 //用作引用類型
 	NEWSTRUCT OpCode = 0xC6 //用作值類型
+	NEWMAP    OpCode = 0xC7
 	APPEND    OpCode = 0xC8
 	REVERSE   OpCode = 0xC9
 	REMOVE    OpCode = 0xCA
+	HASKEY    OpCode = 0xCB
+	KEYS      OpCode = 0xCC
+	VALUES    OpCode = 0xCD
+
+	// Stack isolation
+	CALL_I   OpCode = 0xE0 // Calls an offset of the script with the numbers of the return values and the parameters.
+	CALL_E   OpCode = 0xE1 // Calls a contract with the numbers of the return values and the parameters.
+	CALL_ED  OpCode = 0xE2 // Same as CALL_E but the script hash is popped from the stack.
+	CALL_ET  OpCode = 0xE3 // Same as CALL_E but as a tail call.
+	CALL_EDT OpCode = 0xE4 // Same as CALL_ED but as a tail call.
 
 	// Exceptions
 	THROW      OpCode = 0xF0
 	THROWIFNOT OpCode = 0xF1
 )
+
+var opCodeNames = map[OpCode]string{
+	PUSH0: "PUSH0", PUSHDATA1: "PUSHDATA1", PUSHDATA2: "PUSHDATA2", PUSHDATA4: "PUSHDATA4", PUSHM1: "PUSHM1",
+	PUSH1: "PUSH1", PUSH2: "PUSH2", PUSH3: "PUSH3", PUSH4: "PUSH4", PUSH5: "PUSH5", PUSH6: "PUSH6", PUSH7: "PUSH7", PUSH8: "PUSH8",
+	PUSH9: "PUSH9", PUSH10: "PUSH10", PUSH11: "PUSH11", PUSH12: "PUSH12", PUSH13: "PUSH13", PUSH14: "PUSH14", PUSH15: "PUSH15", PUSH16: "PUSH16",
+	NOP: "NOP", JMP: "JMP", JMPIF: "JMPIF", JMPIFNOT: "JMPIFNOT", CALL: "CALL", RET: "RET", APPCALL: "APPCALL", SYSCALL: "SYSCALL", TAILCALL: "TAILCALL",
+	DUPFROMALTSTACK: "DUPFROMALTSTACK", TOALTSTACK: "TOALTSTACK", FROMALTSTACK: "FROMALTSTACK", XDROP: "XDROP", XSWAP: "XSWAP", XTUCK: "XTUCK",
+	DEPTH: "DEPTH", DROP: "DROP", DUP: "DUP", NIP: "NIP", OVER: "OVER", PICK: "PICK", ROLL: "ROLL", ROT: "ROT", SWAP: "SWAP", TUCK: "TUCK",
+	CAT: "CAT", SUBSTR: "SUBSTR", LEFT: "LEFT", RIGHT: "RIGHT", SIZE: "SIZE",
+	INVERT: "INVERT", AND: "AND", OR: "OR", XOR: "XOR", EQUAL: "EQUAL",
+	INC: "INC", DEC: "DEC", SIGN: "SIGN", NEGATE: "NEGATE", ABS: "ABS", NOT: "NOT", NZ: "NZ", ADD: "ADD", SUB: "SUB", MUL: "MUL", DIV: "DIV", MOD: "MOD",
+	SHL: "SHL", SHR: "SHR", BOOLAND: "BOOLAND", BOOLOR: "BOOLOR", NUMEQUAL: "NUMEQUAL", NUMNOTEQUAL: "NUMNOTEQUAL",
+	LT: "LT", GT: "GT", LTE: "LTE", GTE: "GTE", MIN: "MIN", MAX: "MAX", WITHIN: "WITHIN",
+	SHA1: "SHA1", SHA256: "SHA256", HASH160: "HASH160", HASH256: "HASH256", CHECKSIG: "CHECKSIG", VERIFY: "VERIFY", CHECKMULTISIG: "CHECKMULTISIG",
+	ARRAYSIZE: "ARRAYSIZE", PACK: "PACK", UNPACK: "UNPACK", PICKITEM: "PICKITEM", SETITEM: "SETITEM", NEWARRAY: "NEWARRAY", NEWSTRUCT: "NEWSTRUCT", NEWMAP: "NEWMAP",
+	APPEND: "APPEND", REVERSE: "REVERSE", REMOVE: "REMOVE", HASKEY: "HASKEY", KEYS: "KEYS", VALUES: "VALUES",
+	CALL_I: "CALL_I", CALL_E: "CALL_E", CALL_ED: "CALL_ED", CALL_ET: "CALL_ET", CALL_EDT: "CALL_EDT",
+	THROW: "THROW", THROWIFNOT: "THROWIFNOT",
+}
+
+// IsValid returns false if op is not defined
+func (op OpCode) IsValid() bool {
+	if op >= PUSHBYTES1 && op <= PUSHBYTES75 {
+		return true
+	}
+	_, ok := opCodeNames[op]
+	return ok
+}
+
+// String returns the name of op, such as PUSHBYTES20 or CHECKSIG
+func (op OpCode) String() string {
+	if op >= PUSHBYTES1 && op <= PUSHBYTES75 {
+		return fmt.Sprintf("PUSHBYTES%d", byte(op))
+	}
+	if name, ok := opCodeNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("OpCode(0x%02x)", byte(op))
+}
+
+// OpCodeFromString returns the OpCode of name, PUSHF and PUSHT are accepted as well
+func OpCodeFromString(name string) (OpCode, error) {
+	if strings.HasPrefix(name, "PUSHBYTES") {
+		n, err := strconv.Atoi(strings.TrimPrefix(name, "PUSHBYTES"))
+		if err == nil && n >= int(PUSHBYTES1) && n <= int(PUSHBYTES75) {
+			return OpCode(n), nil
+		}
+	}
+	switch name {
+	case "PUSHF":
+		return PUSHF, nil
+	case "PUSHT":
+		return PUSHT, nil
+	}
+	for op, n := range opCodeNames {
+		if n == name {
+			return op, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown opcode %s", name)
+}
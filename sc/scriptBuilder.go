@@ -22,27 +22,33 @@ func (sb *ScriptBuilder) ToArray() []byte {
 	return sb.buff.Bytes()
 }
 
-func (sb *ScriptBuilder) MakeInvocationScript(scriptHash []byte, operation string, args []ContractParameter) {
+// MakeInvocationScript calls operation of the contract with args, an error is returned if a parameter
+// can not be pushed
+func (sb *ScriptBuilder) MakeInvocationScript(scriptHash []byte, operation string, args []ContractParameter) error {
 	if len(operation) == 0 { // Neo.VM.Helper.cs: Line 28
 		l := len(args)
 		for i := l - 1; i >= 0; i-- {
-			sb.EmitPushParameter(args[i])
+			if err := sb.EmitPushParameter(args[i]); err != nil {
+				return err
+			}
 		}
-		sb.EmitAppCall(scriptHash, false)
+		return sb.EmitAppCall(scriptHash, false)
 	} else {
 		if args != nil { // Neo.VM.Helper.cs: Line 43
 			l := len(args)
 			for i := l - 1; i >= 0; i-- {
-				sb.EmitPushParameter(args[i])
+				if err := sb.EmitPushParameter(args[i]); err != nil {
+					return err
+				}
 			}
 			sb.EmitPushInt(l)
 			sb.Emit(PACK)
 			sb.EmitPushString(operation)
-			sb.EmitAppCall(scriptHash, false)
+			return sb.EmitAppCall(scriptHash, false)
 		} else { // Neo.VM.Helper.cs: Line 35
 			sb.EmitPushBool(false)
 			sb.EmitPushString(operation)
-			sb.EmitAppCall(scriptHash, false)
+			return sb.EmitAppCall(scriptHash, false)
 		}
 	}
 }
@@ -142,52 +148,114 @@ func (sb *ScriptBuilder) EmitPushString(data string) error {
 	return sb.EmitPushBytes([]byte(data))
 }
 
+// EmitPushParameter pushes data, the Array and Map parameters can be nested to any depth
 func (sb *ScriptBuilder) EmitPushParameter(data ContractParameter) error {
-	var err error
 	switch data.Type {
-	case Signature:
-	case ByteArray:
-		err = sb.EmitPushBytes(data.Value.([]byte))
+	case Signature, ByteArray, PublicKey:
+		b, ok := data.Value.([]byte)
+		if !ok {
+			return parameterValueError(data)
+		}
+		return sb.EmitPushBytes(b)
 	case Boolean:
-		err = sb.EmitPushBool(data.Value.(bool))
+		b, ok := data.Value.(bool)
+		if !ok {
+			return parameterValueError(data)
+		}
+		return sb.EmitPushBool(b)
 	case Integer:
-		num := data.Value.(big.Int)
-		err = sb.EmitPushBigInt(num)
+		switch num := data.Value.(type) {
+		case big.Int:
+			return sb.EmitPushBigInt(num)
+		case *big.Int:
+			if num == nil {
+				return parameterValueError(data)
+			}
+			return sb.EmitPushBigInt(*num)
+		default:
+			return parameterValueError(data)
+		}
 	case Hash160:
-		u, e := helper.UInt160FromBytes(data.Value.([]byte))
-		if e != nil {
-			return e
+		switch v := data.Value.(type) {
+		case []byte:
+			u, err := helper.UInt160FromBytes(v)
+			if err != nil {
+				return err
+			}
+			return sb.EmitPushBytes(u.Bytes())
+		case helper.UInt160:
+			return sb.EmitPushBytes(v.Bytes())
+		default:
+			return parameterValueError(data)
 		}
-		err = sb.EmitPushBytes(u.Bytes())
 	case Hash256:
-		u, e := helper.UInt256FromBytes(data.Value.([]byte))
-		if e != nil {
-			return e
+		switch v := data.Value.(type) {
+		case []byte:
+			u, err := helper.UInt256FromBytes(v)
+			if err != nil {
+				return err
+			}
+			return sb.EmitPushBytes(u.Bytes())
+		case helper.UInt256:
+			return sb.EmitPushBytes(v.Bytes())
+		default:
+			return parameterValueError(data)
 		}
-		err = sb.EmitPushBytes(u.Bytes())
-	case PublicKey:
-		err = sb.EmitPushBytes(data.Value.([]byte))
 	case String:
-		s := string(data.Value.(string))
-		err = sb.EmitPushString(s)
+		s, ok := data.Value.(string)
+		if !ok {
+			return parameterValueError(data)
+		}
+		return sb.EmitPushString(s)
 	case Array:
-		a := data.Value.([]ContractParameter)
+		a, ok := data.Value.([]ContractParameter)
+		if !ok {
+			return parameterValueError(data)
+		}
+		// PACK takes the items from the top of the stack
 		for i := len(a) - 1; i >= 0; i-- {
-			e := sb.EmitPushParameter(a[i])
-			if e != nil {
-				return e
+			if err := sb.EmitPushParameter(a[i]); err != nil {
+				return err
 			}
 		}
-		err = sb.EmitPushInt(len(a))
-		if err != nil {
+		if err := sb.EmitPushInt(len(a)); err != nil {
 			return err
 		}
-		err = sb.Emit(PACK)
-	}
-	if err != nil {
-		return err
+		return sb.Emit(PACK)
+	case Map:
+		pairs, ok := data.Value.([]ContractParameterPair)
+		if !ok {
+			return parameterValueError(data)
+		}
+		if err := sb.Emit(NEWMAP); err != nil {
+			return err
+		}
+		// map[key] = value, SETITEM leaves the map on the stack
+		for _, pair := range pairs {
+			if pair.Key.Type == Array || pair.Key.Type == Map {
+				return fmt.Errorf("map key of type %s is not supported", pair.Key.Type.String())
+			}
+			if err := sb.Emit(DUP); err != nil {
+				return err
+			}
+			if err := sb.EmitPushParameter(pair.Key); err != nil {
+				return err
+			}
+			if err := sb.EmitPushParameter(pair.Value); err != nil {
+				return err
+			}
+			if err := sb.Emit(SETITEM); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("parameter type %s is not supported", data.Type.String())
 	}
-	return nil
+}
+
+func parameterValueError(data ContractParameter) error {
+	return fmt.Errorf("invalid value %T of %s parameter", data.Value, data.Type.String())
 }
 
 func (sb *ScriptBuilder) EmitSysCall(api string, args []ContractParameter) error {
@@ -195,9 +263,9 @@ func (sb *ScriptBuilder) EmitSysCall(api string, args []ContractParameter) error
 	l := len(args)
 	for i := l-1; i >= 0; i-- {
 		err = sb.EmitPushParameter(args[i])
-	}
-	if err != nil {
-		return err
+		if err != nil {
+			return err
+		}
 	}
 	return sb.EmitVmSysCall(api, true)
 }
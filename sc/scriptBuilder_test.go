@@ -85,6 +85,32 @@ func TestScriptBuilder_EmitPushParameter(t *testing.T) {
 	assert.Equal(t, "209b7cffdaa674beae0f930ebe6085af9093e5fe56b34a5c220ccdcf6efc336fc5", helper.BytesToHex(b))
 }
 
+func TestScriptBuilder_EmitPushParameter_Nested(t *testing.T) {
+	cp := ContractParameter{Type: Array, Value: []ContractParameter{
+		{Type: Integer, Value: big.NewInt(1)},
+		{Type: Map, Value: []ContractParameterPair{
+			{Key: ContractParameter{Type: String, Value: "a"}, Value: ContractParameter{Type: Array, Value: []ContractParameter{
+				{Type: Boolean, Value: true},
+			}}},
+		}},
+	}}
+	sb := NewScriptBuilder()
+	err := sb.EmitPushParameter(cp)
+	assert.Nil(t, err)
+	// NEWMAP DUP "a" [true] SETITEM, then 1, PACK 2
+	assert.Equal(t, "c77601615151c1c45152c1", helper.BytesToHex(sb.ToArray()))
+
+	sb = NewScriptBuilder()
+	err = sb.EmitPushParameter(ContractParameter{Type: Map, Value: []ContractParameterPair{
+		{Key: ContractParameter{Type: Array, Value: []ContractParameter{}}, Value: ContractParameter{Type: Boolean, Value: true}},
+	}})
+	assert.NotNil(t, err)
+	err = sb.EmitPushParameter(ContractParameter{Type: Integer, Value: "1"})
+	assert.NotNil(t, err)
+	err = sb.EmitPushParameter(ContractParameter{Type: InteropInterface})
+	assert.NotNil(t, err)
+}
+
 func TestScriptBuilder_EmitPushString(t *testing.T) {
 	sb := NewScriptBuilder()
 	_ = sb.EmitPushString("Hello World!")
@@ -0,0 +1,259 @@
+package tx
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+)
+
+// DefaultMaxTries is the max number of the branches explored by a BranchAndBoundSelector
+const DefaultMaxTries = 100000
+
+// Coin is an unspent output of an asset, which can be spent by a transaction input
+type Coin struct {
+	Reference CoinReference
+	Value     helper.Fixed8
+}
+
+// NewCoin converts an unspent output returned by getunspents to a Coin
+func NewCoin(u models.Unspent) (Coin, error) {
+	h, err := helper.UInt256FromString(u.Txid)
+	if err != nil {
+		return Coin{}, err
+	}
+	return Coin{
+		Reference: CoinReference{PrevHash: h, PrevIndex: uint16(u.N)},
+		Value:     u.Value,
+	}, nil
+}
+
+// CoinSelector selects the coins to pay amount, the total value of the selected coins minus amount is the change.
+// An error wrapping rpc.ErrInsufficientFunds is returned if the coins can not pay amount.
+type CoinSelector interface {
+	Select(coins []Coin, amount helper.Fixed8) ([]Coin, error)
+}
+
+// LargestFirstSelector takes the largest coins while they do not exceed the rest of the amount, and then the
+// smallest coin covering what is left. It is the default selector of the TransactionBuilder.
+type LargestFirstSelector struct{}
+
+func (LargestFirstSelector) Select(coins []Coin, amount helper.Fixed8) ([]Coin, error) {
+	if err := checkCoins(coins, amount); err != nil {
+		return nil, err
+	}
+	sorted := sortCoins(coins, true)
+	selected := []Coin{}
+	rest := amount
+	i := 0
+	for i < len(sorted) && rest.GreaterThan(helper.Zero) && !sorted[i].Value.GreaterThan(rest) {
+		selected = append(selected, sorted[i])
+		rest = rest.Sub(sorted[i].Value)
+		i++
+	}
+	if !rest.GreaterThan(helper.Zero) {
+		return selected, nil
+	}
+	// sorted[i] covers the rest since the coins are enough, use the nearest one
+	for i+1 < len(sorted) && !sorted[i+1].Value.LessThan(rest) {
+		i++
+	}
+	return append(selected, sorted[i]), nil
+}
+
+// SmallestFirstSelector takes the smallest coins until the amount is paid, which consolidates the dust of an
+// account into the change output at the cost of a larger transaction
+type SmallestFirstSelector struct{}
+
+func (SmallestFirstSelector) Select(coins []Coin, amount helper.Fixed8) ([]Coin, error) {
+	if err := checkCoins(coins, amount); err != nil {
+		return nil, err
+	}
+	return takeUntilPaid(sortCoins(coins, false), amount), nil
+}
+
+// RandomSelector takes coins in a random order until the amount is paid, so that the selection does not reveal
+// which outputs belong together. Rand is seeded by crypto/rand if it is nil.
+type RandomSelector struct {
+	Rand *rand.Rand
+}
+
+func (s RandomSelector) Select(coins []Coin, amount helper.Fixed8) ([]Coin, error) {
+	if err := checkCoins(coins, amount); err != nil {
+		return nil, err
+	}
+	r := s.Rand
+	if r == nil {
+		var seed [8]byte
+		if _, err := crand.Read(seed[:]); err != nil {
+			return nil, err
+		}
+		r = rand.New(rand.NewSource(int64(binary.LittleEndian.Uint64(seed[:]))))
+	}
+	shuffled := make([]Coin, len(coins))
+	for i, j := range r.Perm(len(coins)) {
+		shuffled[i] = coins[j]
+	}
+	return takeUntilPaid(shuffled, amount), nil
+}
+
+// BranchAndBoundSelector searches for the coins whose total value is between amount and amount plus
+// CostOfChange, so that the transaction needs no change output or only a change below CostOfChange.
+// A zero CostOfChange looks for an exact match. The total closest to amount is chosen among the MaxTries
+// branches explored, DefaultMaxTries is used if it is not positive. If there is no such match, the
+// Fallback selector is used, which is LargestFirstSelector if it is nil.
+type BranchAndBoundSelector struct {
+	CostOfChange helper.Fixed8
+	MaxTries     int
+	Fallback     CoinSelector
+}
+
+func (s BranchAndBoundSelector) Select(coins []Coin, amount helper.Fixed8) ([]Coin, error) {
+	if err := checkCoins(coins, amount); err != nil {
+		return nil, err
+	}
+	if selected, ok := s.search(sortCoins(coins, true), amount); ok {
+		return selected, nil
+	}
+	fallback := s.Fallback
+	if fallback == nil {
+		fallback = LargestFirstSelector{}
+	}
+	return fallback.Select(coins, amount)
+}
+
+// search explores the inclusion of the coins sorted in decreasing order depth first, a branch is cut when
+// its total exceeds the upper bound or the coins left can not reach amount
+func (s BranchAndBoundSelector) search(sorted []Coin, amount helper.Fixed8) ([]Coin, bool) {
+	maxTries := s.MaxTries
+	if maxTries <= 0 {
+		maxTries = DefaultMaxTries
+	}
+	upper := amount.Add(s.CostOfChange)
+	// remaining[i] is the total value of sorted[i:]
+	remaining := make([]helper.Fixed8, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1].Add(sorted[i].Value)
+	}
+
+	var best []int
+	var bestTotal helper.Fixed8
+	path := []int{}
+	tries := 0
+	var explore func(i int, total helper.Fixed8) bool // returns false once the search should stop
+	explore = func(i int, total helper.Fixed8) bool {
+		tries++
+		if tries > maxTries {
+			return false
+		}
+		if total.GreaterThan(upper) || total.Add(remaining[i]).LessThan(amount) {
+			return true
+		}
+		if !total.LessThan(amount) {
+			if best == nil || total.LessThan(bestTotal) {
+				best = append([]int{}, path...)
+				bestTotal = total
+			}
+			return !total.Equal(amount) // an exact match can not be improved
+		}
+		if i == len(sorted) {
+			return true
+		}
+		path = append(path, i)
+		if !explore(i+1, total.Add(sorted[i].Value)) {
+			return false
+		}
+		path = path[:len(path)-1]
+		// skip the coins equal to the one just excluded, they lead to the same totals
+		j := i + 1
+		for j < len(sorted) && sorted[j].Value.Equal(sorted[i].Value) {
+			j++
+		}
+		return explore(j, total)
+	}
+	explore(0, helper.Zero)
+
+	if best == nil {
+		return nil, false
+	}
+	selected := make([]Coin, len(best))
+	for k, i := range best {
+		selected[k] = sorted[i]
+	}
+	return selected, true
+}
+
+// MaxInputsSelector limits the number of the coins selected by Selector to MaxInputs. If Selector takes more,
+// the largest coins are taken instead, and an error wrapping rpc.ErrInsufficientFunds is returned if
+// MaxInputs coins can not pay amount. Selector is LargestFirstSelector if it is nil.
+type MaxInputsSelector struct {
+	Selector  CoinSelector
+	MaxInputs int
+}
+
+func (s MaxInputsSelector) Select(coins []Coin, amount helper.Fixed8) ([]Coin, error) {
+	selector := s.Selector
+	if selector == nil {
+		selector = LargestFirstSelector{}
+	}
+	selected, err := selector.Select(coins, amount)
+	if err != nil || len(selected) <= s.MaxInputs {
+		return selected, err
+	}
+	sorted := sortCoins(coins, true)
+	if len(sorted) > s.MaxInputs {
+		sorted = sorted[:s.MaxInputs]
+	}
+	if err = checkCoins(sorted, amount); err != nil {
+		return nil, fmt.Errorf("more than %d inputs are required: %w", s.MaxInputs, err)
+	}
+	return takeUntilPaid(sorted, amount), nil
+}
+
+// SumCoins returns the total value of coins
+func SumCoins(coins []Coin) helper.Fixed8 {
+	sum := helper.Zero
+	for _, c := range coins {
+		sum = sum.Add(c.Value)
+	}
+	return sum
+}
+
+func checkCoins(coins []Coin, amount helper.Fixed8) error {
+	if available := SumCoins(coins); available.LessThan(amount) {
+		return fmt.Errorf("%s available for %s: %w", available.String(), amount.String(), rpc.ErrInsufficientFunds)
+	}
+	return nil
+}
+
+// sortCoins returns a copy of coins sorted by their values
+func sortCoins(coins []Coin, decreasing bool) []Coin {
+	sorted := make([]Coin, len(coins))
+	copy(sorted, coins)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if decreasing {
+			return sorted[i].Value.GreaterThan(sorted[j].Value)
+		}
+		return sorted[i].Value.LessThan(sorted[j].Value)
+	})
+	return sorted
+}
+
+// takeUntilPaid takes the coins in order until their total value reaches amount
+func takeUntilPaid(coins []Coin, amount helper.Fixed8) []Coin {
+	selected := []Coin{}
+	sum := helper.Zero
+	for _, c := range coins {
+		if !sum.LessThan(amount) {
+			break
+		}
+		selected = append(selected, c)
+		sum = sum.Add(c.Value)
+	}
+	return selected
+}
@@ -0,0 +1,141 @@
+package tx
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCoins(values ...int64) []Coin {
+	coins := make([]Coin, len(values))
+	for i, v := range values {
+		coins[i] = Coin{
+			Reference: CoinReference{PrevHash: GasToken, PrevIndex: uint16(i)},
+			Value:     helper.Fixed8FromInt64(v),
+		}
+	}
+	return coins
+}
+
+func coinValues(coins []Coin) []int64 {
+	values := make([]int64, len(coins))
+	for i, c := range coins {
+		values[i] = helper.Fixed8ToInt64(c.Value)
+	}
+	return values
+}
+
+func TestNewCoin(t *testing.T) {
+	c, err := NewCoin(models.Unspent{
+		Txid:  "1c2f4605fa4c5ba9ca2a8ae87ae083a241d407f59472e707fe34e52d277d2331",
+		N:     1,
+		Value: helper.NewFixed8(8196167000),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8196167000), c.Value.Value)
+	assert.Equal(t, uint16(1), c.Reference.PrevIndex)
+	assert.Equal(t, "1c2f4605fa4c5ba9ca2a8ae87ae083a241d407f59472e707fe34e52d277d2331", c.Reference.PrevHash.String())
+
+	_, err = NewCoin(models.Unspent{Txid: "abc"})
+	assert.NotNil(t, err)
+}
+
+func TestLargestFirstSelector_Select(t *testing.T) {
+	coins := newCoins(1, 50, 3, 20)
+	s := LargestFirstSelector{}
+
+	selected, err := s.Select(coins, helper.Fixed8FromInt64(71))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{50, 20, 1}, coinValues(selected))
+
+	// 20 is the nearest coin covering 5
+	selected, err = s.Select(coins, helper.Fixed8FromInt64(5))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{20}, coinValues(selected))
+
+	// 3 is the nearest coin covering the rest after 50 and 20
+	selected, err = s.Select(coins, helper.Fixed8FromInt64(72))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{50, 20, 3}, coinValues(selected))
+
+	// all the coins are needed
+	selected, err = s.Select(coins, helper.Fixed8FromInt64(74))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(selected))
+
+	_, err = s.Select(coins, helper.Fixed8FromInt64(75))
+	assert.True(t, errors.Is(err, rpc.ErrInsufficientFunds))
+}
+
+func TestSmallestFirstSelector_Select(t *testing.T) {
+	selected, err := SmallestFirstSelector{}.Select(newCoins(1, 50, 3, 20), helper.Fixed8FromInt64(10))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 3, 20}, coinValues(selected))
+}
+
+func TestRandomSelector_Select(t *testing.T) {
+	coins := newCoins(1, 50, 3, 20, 7, 9)
+	s := RandomSelector{Rand: rand.New(rand.NewSource(1))}
+	for i := 0; i < 10; i++ {
+		selected, err := s.Select(coins, helper.Fixed8FromInt64(30))
+		assert.Nil(t, err)
+		sum := SumCoins(selected)
+		assert.False(t, sum.LessThan(helper.Fixed8FromInt64(30)))
+		// no coin is redundant except the last one taken
+		assert.True(t, sum.Sub(selected[len(selected)-1].Value).LessThan(helper.Fixed8FromInt64(30)))
+	}
+
+	_, err := RandomSelector{}.Select(coins, helper.Fixed8FromInt64(91))
+	assert.True(t, errors.Is(err, rpc.ErrInsufficientFunds))
+}
+
+func TestBranchAndBoundSelector_Select(t *testing.T) {
+	coins := newCoins(1, 50, 3, 20, 7, 9)
+
+	// 20 + 9 + 1 is an exact match
+	selected, err := BranchAndBoundSelector{}.Select(coins, helper.Fixed8FromInt64(30))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30), helper.Fixed8ToInt64(SumCoins(selected)))
+
+	// no exact match for 91, 90 is all the coins
+	_, err = BranchAndBoundSelector{}.Select(coins, helper.Fixed8FromInt64(91))
+	assert.True(t, errors.Is(err, rpc.ErrInsufficientFunds))
+
+	// no exact match for 85, the change of 50 + 20 + 9 + 7 is within the cost of change
+	selected, err = BranchAndBoundSelector{CostOfChange: helper.Fixed8FromInt64(1)}.Select(coins, helper.Fixed8FromInt64(85))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(86), helper.Fixed8ToInt64(SumCoins(selected)))
+
+	// falls back if there is no match
+	selected, err = BranchAndBoundSelector{Fallback: SmallestFirstSelector{}}.Select(newCoins(5, 10), helper.Fixed8FromInt64(6))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5, 10}, coinValues(selected))
+
+	// 20 + 7 + 3 + 1 is an exact match for 31, but the search stops after MaxTries and falls back
+	selected, err = BranchAndBoundSelector{}.Select(coins, helper.Fixed8FromInt64(31))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(31), helper.Fixed8ToInt64(SumCoins(selected)))
+	selected, err = BranchAndBoundSelector{MaxTries: 1}.Select(coins, helper.Fixed8FromInt64(31))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{50}, coinValues(selected))
+}
+
+func TestMaxInputsSelector_Select(t *testing.T) {
+	coins := newCoins(1, 50, 3, 20, 7, 9)
+
+	selected, err := MaxInputsSelector{Selector: SmallestFirstSelector{}, MaxInputs: 3}.Select(coins, helper.Fixed8FromInt64(10))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 3, 7}, coinValues(selected))
+
+	selected, err = MaxInputsSelector{Selector: SmallestFirstSelector{}, MaxInputs: 2}.Select(coins, helper.Fixed8FromInt64(30))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{50}, coinValues(selected))
+
+	_, err = MaxInputsSelector{MaxInputs: 2}.Select(coins, helper.Fixed8FromInt64(80))
+	assert.True(t, errors.Is(err, rpc.ErrInsufficientFunds))
+}
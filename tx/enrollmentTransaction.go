@@ -0,0 +1,145 @@
+package tx
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/joeqian10/neo-gogogo/crypto"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+)
+
+// EnrollmentTransaction inherits Transaction, it is deprecated in neo-2.x and only found in old blocks
+type EnrollmentTransaction struct {
+	*Transaction
+	PublicKey *keys.PublicKey
+}
+
+func (tx *EnrollmentTransaction) Size() int {
+	return len(tx.RawTransaction())
+}
+
+// implement ITransaction interface
+func (tx *EnrollmentTransaction) GetTransaction() *Transaction {
+	return tx.Transaction
+}
+
+// HashString returns the transaction Id string
+func (tx *EnrollmentTransaction) HashString() string {
+	hash := crypto.Hash256(tx.UnsignedRawTransaction())
+	tx.Hash, _ = helper.UInt256FromBytes(hash)
+	return hex.EncodeToString(helper.ReverseBytes(hash)) // reverse to big endian
+}
+
+func (tx *EnrollmentTransaction) UnsignedRawTransaction() []byte {
+	buf := io.NewBufBinaryWriter()
+	tx.SerializeUnsigned(buf.BinaryWriter)
+	if buf.Err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func (tx *EnrollmentTransaction) RawTransaction() []byte {
+	buf := io.NewBufBinaryWriter()
+	tx.Serialize(buf.BinaryWriter)
+	if buf.Err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func (tx *EnrollmentTransaction) RawTransactionString() string {
+	return hex.EncodeToString(tx.RawTransaction())
+}
+
+// FromHexString parses a hex string
+func (tx *EnrollmentTransaction) FromHexString(rawTx string) (*EnrollmentTransaction, error) {
+	b, err := hex.DecodeString(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	br := io.NewBinaryReaderFromBuf(b)
+	tx.Deserialize(br)
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	return tx, nil
+}
+
+// Deserialize implements Serializable interface.
+func (tx *EnrollmentTransaction) Deserialize(br *io.BinaryReader) {
+	tx.DeserializeUnsigned(br)
+	tx.Transaction.DeserializeWitnesses(br)
+}
+
+func (tx *EnrollmentTransaction) DeserializeUnsigned(br *io.BinaryReader) {
+	tx.Transaction.DeserializeUnsigned1(br)
+	tx.DeserializeExclusiveData(br)
+	tx.Transaction.DeserializeUnsigned2(br)
+}
+
+func (tx *EnrollmentTransaction) DeserializeExclusiveData(br *io.BinaryReader) {
+	if tx.Version != 0 {
+		br.Err = fmt.Errorf("format error: invalid version %d for EnrollmentTransaction", tx.Version)
+		return
+	}
+	tx.PublicKey = readPublicKey(br)
+}
+
+// Serialize implements Serializable interface.
+func (tx *EnrollmentTransaction) Serialize(bw *io.BinaryWriter) {
+	tx.SerializeUnsigned(bw)
+	tx.SerializeWitnesses(bw)
+}
+
+func (tx *EnrollmentTransaction) SerializeUnsigned(bw *io.BinaryWriter) {
+	tx.Transaction.SerializeUnsigned1(bw)
+	tx.SerializeExclusiveData(bw)
+	tx.SerializeUnsigned2(bw)
+}
+
+func (tx *EnrollmentTransaction) SerializeExclusiveData(bw *io.BinaryWriter) {
+	writePublicKey(bw, tx.PublicKey)
+}
+
+// readPublicKey reads an ECPoint, which is 0x00 for infinity, 33 bytes compressed or 65 bytes uncompressed
+func readPublicKey(br *io.BinaryReader) *keys.PublicKey {
+	var prefix byte
+	br.ReadLE(&prefix)
+	if br.Err != nil {
+		return nil
+	}
+	var data []byte
+	switch prefix {
+	case 0x00:
+		data = []byte{prefix}
+	case 0x02, 0x03:
+		data = make([]byte, 33)
+	case 0x04:
+		data = make([]byte, 65)
+	default:
+		br.Err = fmt.Errorf("format error: invalid public key prefix %d", prefix)
+		return nil
+	}
+	data[0] = prefix
+	br.ReadLE(data[1:])
+	if br.Err != nil {
+		return nil
+	}
+	p, err := keys.NewPublicKey(data)
+	if err != nil {
+		br.Err = err
+		return nil
+	}
+	return p
+}
+
+// writePublicKey writes an ECPoint in compressed format, the same as neo does
+func writePublicKey(bw *io.BinaryWriter, p *keys.PublicKey) {
+	if p == nil {
+		bw.WriteLE(byte(0x00))
+		return
+	}
+	bw.WriteLE(p.EncodeCompression())
+}
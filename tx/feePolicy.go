@@ -0,0 +1,50 @@
+package tx
+
+import "github.com/joeqian10/neo-gogogo/helper"
+
+// the free gas of an invocation and the network fee rules of the SimplePolicy plugin of neo 2.x,
+// the values are in the smallest unit of GAS
+const (
+	DefaultFreeGas                = 1000000000 // 10 GAS
+	DefaultMaxFreeTransactionSize = 1024
+	DefaultFeePerExtraByte        = 1000   // 0.00001 GAS
+	DefaultLowPriorityThreshold   = 100000 // 0.001 GAS
+)
+
+// FeePolicy computes the fees required by the transactions built by the TransactionBuilder. fee.Calculator
+// implements it for the policy of a network:
+//
+//	tb.FeePolicy = fee.NewCalculator(fee.TestNetPolicy())
+type FeePolicy interface {
+	// InvocationGas returns the Gas of an InvocationTransaction running a script which consumes gasConsumed
+	InvocationGas(gasConsumed helper.Fixed8) helper.Fixed8
+	// NetworkFeeOfSize returns the network fee required by a transaction of size bytes
+	NetworkFeeOfSize(size int) helper.Fixed8
+}
+
+// DefaultFeePolicy is the policy of a neo 2.x node with the default settings, the same as fee.MainNetPolicy.
+// It is the FeePolicy of the TransactionBuilder if none is set.
+type DefaultFeePolicy struct{}
+
+// InvocationGas deducts DefaultFreeGas from gasConsumed and rounds the rest up to an integer
+func (DefaultFeePolicy) InvocationGas(gasConsumed helper.Fixed8) helper.Fixed8 {
+	gas := gasConsumed.Sub(helper.NewFixed8(DefaultFreeGas))
+	if !gas.GreaterThan(helper.Zero) {
+		return helper.Zero
+	}
+	return gas.Ceiling()
+}
+
+// NetworkFeeOfSize charges DefaultFeePerExtraByte for each byte above DefaultMaxFreeTransactionSize,
+// and no less than DefaultLowPriorityThreshold
+func (DefaultFeePolicy) NetworkFeeOfSize(size int) helper.Fixed8 {
+	extra := size - DefaultMaxFreeTransactionSize
+	if extra <= 0 {
+		return helper.Zero
+	}
+	fee := helper.NewFixed8(DefaultFeePerExtraByte * int64(extra))
+	if fee.LessThan(helper.NewFixed8(DefaultLowPriorityThreshold)) {
+		return helper.NewFixed8(DefaultLowPriorityThreshold)
+	}
+	return fee
+}
@@ -21,6 +21,15 @@ func NewIssueTransaction(script []byte) *IssueTransaction {
 	return tx
 }
 
+func (tx *IssueTransaction) Size() int {
+	return len(tx.RawTransaction())
+}
+
+// implement ITransaction interface
+func (tx *IssueTransaction) GetTransaction() *Transaction {
+	return tx.Transaction
+}
+
 // HashString returns the transaction Id string
 func (tx *IssueTransaction) HashString() string {
 	hash := crypto.Hash256(tx.UnsignedRawTransaction())
@@ -23,6 +23,15 @@ type MinerTransaction struct {
 //	return mtx
 //}
 
+func (mtx *MinerTransaction) Size() int {
+	return len(mtx.RawTransaction())
+}
+
+// implement ITransaction interface
+func (mtx *MinerTransaction) GetTransaction() *Transaction {
+	return mtx.Transaction
+}
+
 // HashString returns the transaction Id string
 func (mtx *MinerTransaction) HashString() string {
 	hash := crypto.Hash256(mtx.UnsignedRawTransaction())
@@ -0,0 +1,142 @@
+package tx
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/joeqian10/neo-gogogo/crypto"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/sc"
+)
+
+// PublishTransaction inherits Transaction, it is deprecated in neo-2.x and only found in old blocks
+type PublishTransaction struct {
+	*Transaction
+	Script        []byte
+	ParameterList []sc.ContractParameterType
+	ReturnType    sc.ContractParameterType
+	NeedStorage   bool
+	Name          string
+	CodeVersion   string
+	Author        string
+	Email         string
+	Description   string
+}
+
+func (tx *PublishTransaction) Size() int {
+	return len(tx.RawTransaction())
+}
+
+// implement ITransaction interface
+func (tx *PublishTransaction) GetTransaction() *Transaction {
+	return tx.Transaction
+}
+
+// HashString returns the transaction Id string
+func (tx *PublishTransaction) HashString() string {
+	hash := crypto.Hash256(tx.UnsignedRawTransaction())
+	tx.Hash, _ = helper.UInt256FromBytes(hash)
+	return hex.EncodeToString(helper.ReverseBytes(hash)) // reverse to big endian
+}
+
+func (tx *PublishTransaction) UnsignedRawTransaction() []byte {
+	buf := io.NewBufBinaryWriter()
+	tx.SerializeUnsigned(buf.BinaryWriter)
+	if buf.Err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func (tx *PublishTransaction) RawTransaction() []byte {
+	buf := io.NewBufBinaryWriter()
+	tx.Serialize(buf.BinaryWriter)
+	if buf.Err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func (tx *PublishTransaction) RawTransactionString() string {
+	return hex.EncodeToString(tx.RawTransaction())
+}
+
+// FromHexString parses a hex string
+func (tx *PublishTransaction) FromHexString(rawTx string) (*PublishTransaction, error) {
+	b, err := hex.DecodeString(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	br := io.NewBinaryReaderFromBuf(b)
+	tx.Deserialize(br)
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	return tx, nil
+}
+
+// Deserialize implements Serializable interface.
+func (tx *PublishTransaction) Deserialize(br *io.BinaryReader) {
+	tx.DeserializeUnsigned(br)
+	tx.Transaction.DeserializeWitnesses(br)
+}
+
+func (tx *PublishTransaction) DeserializeUnsigned(br *io.BinaryReader) {
+	tx.Transaction.DeserializeUnsigned1(br)
+	tx.DeserializeExclusiveData(br)
+	tx.Transaction.DeserializeUnsigned2(br)
+}
+
+func (tx *PublishTransaction) DeserializeExclusiveData(br *io.BinaryReader) {
+	if tx.Version > 1 {
+		br.Err = fmt.Errorf("format error: invalid version %d for PublishTransaction", tx.Version)
+		return
+	}
+	tx.Script = br.ReadVarBytes()
+	parameters := br.ReadVarBytes()
+	tx.ParameterList = make([]sc.ContractParameterType, len(parameters))
+	for i, p := range parameters {
+		tx.ParameterList[i] = sc.ContractParameterType(p)
+	}
+	br.ReadLE(&tx.ReturnType)
+	if tx.Version >= 1 {
+		br.ReadLE(&tx.NeedStorage)
+	} else {
+		tx.NeedStorage = false
+	}
+	tx.Name = br.ReadVarString()
+	tx.CodeVersion = br.ReadVarString()
+	tx.Author = br.ReadVarString()
+	tx.Email = br.ReadVarString()
+	tx.Description = br.ReadVarString()
+}
+
+// Serialize implements Serializable interface.
+func (tx *PublishTransaction) Serialize(bw *io.BinaryWriter) {
+	tx.SerializeUnsigned(bw)
+	tx.SerializeWitnesses(bw)
+}
+
+func (tx *PublishTransaction) SerializeUnsigned(bw *io.BinaryWriter) {
+	tx.Transaction.SerializeUnsigned1(bw)
+	tx.SerializeExclusiveData(bw)
+	tx.SerializeUnsigned2(bw)
+}
+
+func (tx *PublishTransaction) SerializeExclusiveData(bw *io.BinaryWriter) {
+	bw.WriteVarBytes(tx.Script)
+	parameters := make([]byte, len(tx.ParameterList))
+	for i, p := range tx.ParameterList {
+		parameters[i] = byte(p)
+	}
+	bw.WriteVarBytes(parameters)
+	bw.WriteLE(tx.ReturnType)
+	if tx.Version >= 1 {
+		bw.WriteLE(tx.NeedStorage)
+	}
+	bw.WriteVarString(tx.Name)
+	bw.WriteVarString(tx.CodeVersion)
+	bw.WriteVarString(tx.Author)
+	bw.WriteVarString(tx.Email)
+	bw.WriteVarString(tx.Description)
+}
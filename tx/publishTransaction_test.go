@@ -0,0 +1,36 @@
+package tx
+
+import (
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPublishTransaction(t *testing.T) {
+	ptx := &PublishTransaction{
+		Transaction:   NewTransaction(),
+		Script:        []byte{byte(sc.PUSHT)},
+		ParameterList: []sc.ContractParameterType{sc.String, sc.Array},
+		ReturnType:    sc.ByteArray,
+		NeedStorage:   true,
+		Name:          "name",
+		CodeVersion:   "1.0",
+		Author:        "author",
+		Email:         "email",
+		Description:   "description",
+	}
+	ptx.Type = Publish_Transaction
+	ptx.Version = 1
+
+	tx, err := NewTransactionFromBytes(ptx.RawTransaction())
+	assert.Nil(t, err)
+	assert.IsType(t, &PublishTransaction{}, tx)
+	assert.Equal(t, ptx, tx)
+	assert.Equal(t, ptx.HashString(), tx.HashString())
+
+	// NeedStorage is not serialized in version 0
+	ptx.Version = 0
+	tx, err = NewTransactionFromBytes(ptx.RawTransaction())
+	assert.Nil(t, err)
+	assert.False(t, tx.(*PublishTransaction).NeedStorage)
+}
@@ -0,0 +1,168 @@
+package tx
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/joeqian10/neo-gogogo/crypto"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+	"strconv"
+)
+
+// AssetType of the asset registered by a RegisterTransaction
+type AssetType byte
+
+const (
+	CreditFlag AssetType = 0x40
+	DutyFlag   AssetType = 0x80
+
+	GoverningToken AssetType = 0x00
+	UtilityToken   AssetType = 0x01
+	Currency       AssetType = 0x08
+	Share          AssetType = DutyFlag | 0x10
+	Invoice        AssetType = DutyFlag | 0x18
+	Token          AssetType = CreditFlag | 0x20
+)
+
+func (t AssetType) String() string {
+	switch t {
+	case CreditFlag:
+		return "CreditFlag"
+	case DutyFlag:
+		return "DutyFlag"
+	case GoverningToken:
+		return "GoverningToken"
+	case UtilityToken:
+		return "UtilityToken"
+	case Currency:
+		return "Currency"
+	case Share:
+		return "Share"
+	case Invoice:
+		return "Invoice"
+	case Token:
+		return "Token"
+	default:
+		return strconv.FormatUint(uint64(t), 10)
+	}
+}
+
+// NewAssetTypeFromString parses the asset type name used in json, such as "GoverningToken"
+func NewAssetTypeFromString(s string) (AssetType, error) {
+	for _, t := range []AssetType{CreditFlag, DutyFlag, GoverningToken, UtilityToken, Currency, Share, Invoice, Token} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("unknown asset type: %s", s)
+	}
+	return AssetType(n), nil
+}
+
+// RegisterTransaction inherits Transaction, it is deprecated in neo-2.x and only found in old blocks
+type RegisterTransaction struct {
+	*Transaction
+	AssetType AssetType
+	Name      string
+	Amount    helper.Fixed8
+	Precision byte
+	Owner     *keys.PublicKey
+	Admin     helper.UInt160
+}
+
+func (tx *RegisterTransaction) Size() int {
+	return len(tx.RawTransaction())
+}
+
+// implement ITransaction interface
+func (tx *RegisterTransaction) GetTransaction() *Transaction {
+	return tx.Transaction
+}
+
+// HashString returns the transaction Id string
+func (tx *RegisterTransaction) HashString() string {
+	hash := crypto.Hash256(tx.UnsignedRawTransaction())
+	tx.Hash, _ = helper.UInt256FromBytes(hash)
+	return hex.EncodeToString(helper.ReverseBytes(hash)) // reverse to big endian
+}
+
+func (tx *RegisterTransaction) UnsignedRawTransaction() []byte {
+	buf := io.NewBufBinaryWriter()
+	tx.SerializeUnsigned(buf.BinaryWriter)
+	if buf.Err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func (tx *RegisterTransaction) RawTransaction() []byte {
+	buf := io.NewBufBinaryWriter()
+	tx.Serialize(buf.BinaryWriter)
+	if buf.Err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func (tx *RegisterTransaction) RawTransactionString() string {
+	return hex.EncodeToString(tx.RawTransaction())
+}
+
+// FromHexString parses a hex string
+func (tx *RegisterTransaction) FromHexString(rawTx string) (*RegisterTransaction, error) {
+	b, err := hex.DecodeString(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	br := io.NewBinaryReaderFromBuf(b)
+	tx.Deserialize(br)
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	return tx, nil
+}
+
+// Deserialize implements Serializable interface.
+func (tx *RegisterTransaction) Deserialize(br *io.BinaryReader) {
+	tx.DeserializeUnsigned(br)
+	tx.Transaction.DeserializeWitnesses(br)
+}
+
+func (tx *RegisterTransaction) DeserializeUnsigned(br *io.BinaryReader) {
+	tx.Transaction.DeserializeUnsigned1(br)
+	tx.DeserializeExclusiveData(br)
+	tx.Transaction.DeserializeUnsigned2(br)
+}
+
+func (tx *RegisterTransaction) DeserializeExclusiveData(br *io.BinaryReader) {
+	br.ReadLE(&tx.AssetType)
+	tx.Name = br.ReadVarString()
+	br.ReadLE(&tx.Amount)
+	br.ReadLE(&tx.Precision)
+	tx.Owner = readPublicKey(br)
+	br.ReadLE(&tx.Admin)
+}
+
+// Serialize implements Serializable interface.
+func (tx *RegisterTransaction) Serialize(bw *io.BinaryWriter) {
+	tx.SerializeUnsigned(bw)
+	tx.SerializeWitnesses(bw)
+}
+
+func (tx *RegisterTransaction) SerializeUnsigned(bw *io.BinaryWriter) {
+	tx.Transaction.SerializeUnsigned1(bw)
+	tx.SerializeExclusiveData(bw)
+	tx.SerializeUnsigned2(bw)
+}
+
+func (tx *RegisterTransaction) SerializeExclusiveData(bw *io.BinaryWriter) {
+	bw.WriteLE(tx.AssetType)
+	bw.WriteVarString(tx.Name)
+	bw.WriteLE(tx.Amount)
+	bw.WriteLE(tx.Precision)
+	writePublicKey(bw, tx.Owner)
+	bw.WriteLE(tx.Admin)
+}
@@ -0,0 +1,25 @@
+package tx
+
+import (
+	"encoding/hex"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRegisterTransaction(t *testing.T) {
+	// mainnet genesis transaction which registers NEO
+	rawTx := "400000455b7b226c616e67223a227a682d434e222c226e616d65223a22e5b08fe89a81e882a1227d2c7b226c616e67223a22656e222c226e616d65223a22416e745368617265227d5d0000c16ff28623000000da1745e9b549bd0bfa1a569971c77eba30cd5a4b00000000"
+	rtx := &RegisterTransaction{Transaction: NewTransaction()}
+	rtx, err := rtx.FromHexString(rawTx)
+	assert.Nil(t, err)
+	assert.Equal(t, Register_Transaction, rtx.Type)
+	assert.Equal(t, GoverningToken, rtx.AssetType)
+	assert.Equal(t, `[{"lang":"zh-CN","name":"小蚁股"},{"lang":"en","name":"AntShare"}]`, rtx.Name)
+	assert.Equal(t, helper.Fixed8FromInt64(100000000), rtx.Amount)
+	assert.Equal(t, byte(0), rtx.Precision)
+	assert.Equal(t, "00", rtx.Owner.String())
+	assert.Equal(t, "Abf2qMs1pzQb8kYk9RuxtUb9jtRKJVuBJt", helper.ScriptHashToAddress(rtx.Admin))
+	assert.Equal(t, NeoTokenId, rtx.HashString())
+	assert.Equal(t, rawTx, hex.EncodeToString(rtx.RawTransaction()))
+}
@@ -1,7 +1,11 @@
 package tx
 
 import (
+	"encoding/hex"
+	"fmt"
 	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"strconv"
 )
 
 // StateType represents the type of StateDescriptor.
@@ -13,6 +17,29 @@ const (
 	Validator StateType = 0x48
 )
 
+func (t StateType) String() string {
+	switch t {
+	case Account:
+		return "Account"
+	case Validator:
+		return "Validator"
+	default:
+		return "StateType=" + strconv.FormatUint(uint64(t), 10)
+	}
+}
+
+// NewStateTypeFromString parses the state type name used in json, such as "Account"
+func NewStateTypeFromString(s string) (StateType, error) {
+	switch s {
+	case "Account":
+		return Account, nil
+	case "Validator":
+		return Validator, nil
+	default:
+		return 0, fmt.Errorf("unknown state type: %s", s)
+	}
+}
+
 // StateDescriptor ..
 type StateDescriptor struct {
 	Type  StateType
@@ -21,6 +48,22 @@ type StateDescriptor struct {
 	Field string
 }
 
+func NewStateDescriptorFromRPC(d models.RpcStateDescriptor) (*StateDescriptor, error) {
+	t, err := NewStateTypeFromString(d.Type)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(d.Key)
+	if err != nil {
+		return nil, err
+	}
+	value, err := hex.DecodeString(d.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &StateDescriptor{Type: t, Key: key, Value: value, Field: d.Field}, nil
+}
+
 // Deserialize implements Serializable interface.
 func (s *StateDescriptor) Deserialize(r *io.BinaryReader) {
 	r.ReadLE(&s.Type)
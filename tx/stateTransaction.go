@@ -22,6 +22,15 @@ func NewStateTransaction(script []byte) *StateTransaction {
 	return tx
 }
 
+func (tx *StateTransaction) Size() int {
+	return len(tx.RawTransaction())
+}
+
+// implement ITransaction interface
+func (tx *StateTransaction) GetTransaction() *Transaction {
+	return tx.Transaction
+}
+
 // HashString returns the transaction Id string
 func (tx *StateTransaction) HashString() string {
 	hash := crypto.Hash256(tx.UnsignedRawTransaction())
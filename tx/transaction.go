@@ -2,10 +2,16 @@ package tx
 
 import (
 	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
 	"github.com/joeqian10/neo-gogogo/wallet/keys"
 	"sort"
+	"strings"
 )
 
 const (
@@ -35,8 +41,227 @@ func NewTransaction() *Transaction {
 	}
 }
 
-func NewTransactionFromRPC()  {
+// NewTransactionFromRPC converts a transaction returned by getrawtransaction or getblock with verbose=1
+// to the typed transaction of its type, such as *ContractTransaction. An error is returned if the
+// hash of the result differs from rpcTx.Txid.
+func NewTransactionFromRPC(rpcTx models.RpcTransaction) (ITransaction, error) {
+	t, err := NewTransactionTypeFromString(rpcTx.Type)
+	if err != nil {
+		return nil, err
+	}
+	tx := newTransactionOfType(t)
+	base := tx.GetTransaction()
+	base.Version = uint8(rpcTx.Version)
+	for _, attr := range rpcTx.Attributes {
+		base.Attributes = append(base.Attributes, NewTransactionAttributeFromRPC(attr))
+	}
+	for _, vin := range rpcTx.Vin {
+		input, err := NewCoinReferenceFromRPC(vin)
+		if err != nil {
+			return nil, err
+		}
+		base.Inputs = append(base.Inputs, input)
+	}
+	for _, vout := range rpcTx.Vout {
+		output, err := NewTransactionOutputFromRPC(vout)
+		if err != nil {
+			return nil, err
+		}
+		base.Outputs = append(base.Outputs, output)
+	}
+	for _, script := range rpcTx.Scripts {
+		invocation, err := hex.DecodeString(script.Invocation)
+		if err != nil {
+			return nil, err
+		}
+		verification, err := hex.DecodeString(script.Verification)
+		if err != nil {
+			return nil, err
+		}
+		base.Witnesses = append(base.Witnesses, &Witness{InvocationScript: invocation, VerificationScript: verification})
+	}
+	if err = exclusiveDataFromRPC(tx, rpcTx); err != nil {
+		return nil, err
+	}
+	if len(rpcTx.Txid) != 0 && tx.HashString() != strings.TrimPrefix(rpcTx.Txid, "0x") {
+		return nil, fmt.Errorf("hash mismatch: expected %s got %s", rpcTx.Txid, tx.HashString())
+	}
+	return tx, nil
+}
+
+func exclusiveDataFromRPC(tx ITransaction, rpcTx models.RpcTransaction) error {
+	var err error
+	switch t := tx.(type) {
+	case *MinerTransaction:
+		t.Nonce = uint32(rpcTx.Nonce)
+	case *ClaimTransaction:
+		t.Claims = make([]*CoinReference, len(rpcTx.Claims))
+		for i, claim := range rpcTx.Claims {
+			t.Claims[i], err = NewCoinReferenceFromRPC(models.RpcTransactionInput(claim))
+			if err != nil {
+				return err
+			}
+		}
+	case *EnrollmentTransaction:
+		t.PublicKey, err = keys.NewPublicKeyFromString(rpcTx.PubKey)
+		if err != nil {
+			return err
+		}
+	case *RegisterTransaction:
+		if rpcTx.Asset == nil {
+			return fmt.Errorf("asset is missing in RegisterTransaction")
+		}
+		if t.AssetType, err = NewAssetTypeFromString(rpcTx.Asset.Type); err != nil {
+			return err
+		}
+		t.Name = assetNameFromRPC(rpcTx.Asset.Name)
+		if t.Amount, err = helper.Fixed8FromString(rpcTx.Asset.Amount); err != nil {
+			return err
+		}
+		t.Precision = byte(rpcTx.Asset.Precision)
+		if t.Owner, err = keys.NewPublicKeyFromString(rpcTx.Asset.Owner); err != nil {
+			return err
+		}
+		if t.Admin, err = helper.AddressToScriptHash(rpcTx.Asset.Admin); err != nil {
+			return err
+		}
+	case *StateTransaction:
+		t.Descriptors = make([]*StateDescriptor, len(rpcTx.Descriptors))
+		for i, d := range rpcTx.Descriptors {
+			t.Descriptors[i], err = NewStateDescriptorFromRPC(d)
+			if err != nil {
+				return err
+			}
+		}
+	case *PublishTransaction:
+		if rpcTx.Contract == nil {
+			return fmt.Errorf("contract is missing in PublishTransaction")
+		}
+		if t.Script, err = hex.DecodeString(rpcTx.Contract.Code.Script); err != nil {
+			return err
+		}
+		t.ParameterList = make([]sc.ContractParameterType, len(rpcTx.Contract.Code.Parameters))
+		for i, p := range rpcTx.Contract.Code.Parameters {
+			if t.ParameterList[i], err = sc.NewContractParameterTypeFromString(p); err != nil {
+				return err
+			}
+		}
+		if t.ReturnType, err = sc.NewContractParameterTypeFromString(rpcTx.Contract.Code.ReturnType); err != nil {
+			return err
+		}
+		t.NeedStorage = rpcTx.Contract.NeedStorage
+		t.Name = rpcTx.Contract.Name
+		t.CodeVersion = rpcTx.Contract.Version
+		t.Author = rpcTx.Contract.Author
+		t.Email = rpcTx.Contract.Email
+		t.Description = rpcTx.Contract.Description
+	case *InvocationTransaction:
+		if t.Script, err = hex.DecodeString(rpcTx.Script); err != nil {
+			return err
+		}
+		if t.Version >= 1 {
+			if t.Gas, err = helper.Fixed8FromString(rpcTx.Gas); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// the node replies the name parsed as json, or as a string if it is not json
+func assetNameFromRPC(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return name
+	}
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// NewTransactionFromBytes deserializes a raw transaction of any type,
+// the result can be cast to the typed transaction, such as *InvocationTransaction
+func NewTransactionFromBytes(b []byte) (ITransaction, error) {
+	r := bytes.NewReader(b)
+	br := io.NewBinaryReaderFromIO(r)
+	tx := DeserializeTransaction(br)
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("format error: %d bytes left after the transaction", r.Len())
+	}
+	return tx, nil
+}
 
+// NewTransactionFromHexString parses the hex string returned by getrawtransaction with verbose=0
+func NewTransactionFromHexString(rawTx string) (ITransaction, error) {
+	b, err := hex.DecodeString(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransactionFromBytes(b)
+}
+
+// DeserializeTransaction reads a transaction of any type from br, it returns nil if br.Err is set
+func DeserializeTransaction(br *io.BinaryReader) ITransaction {
+	var t TransactionType
+	br.ReadLE(&t)
+	if br.Err != nil {
+		return nil
+	}
+	tx := newTransactionOfType(t)
+	if tx == nil {
+		br.Err = fmt.Errorf("format error: unknown transaction type %d", t)
+		return nil
+	}
+	base := tx.GetTransaction()
+	br.ReadLE(&base.Version)
+	tx.DeserializeExclusiveData(br)
+	base.DeserializeUnsigned2(br)
+	base.DeserializeWitnesses(br)
+	if br.Err != nil {
+		return nil
+	}
+	return tx
+}
+
+type typedTransaction interface {
+	ITransaction
+	DeserializeExclusiveData(br *io.BinaryReader)
+}
+
+func newTransactionOfType(t TransactionType) typedTransaction {
+	var tx typedTransaction
+	switch t {
+	case Miner_Transaction:
+		tx = &MinerTransaction{Transaction: NewTransaction()}
+	case Issue_Transaction:
+		tx = &IssueTransaction{Transaction: NewTransaction()}
+	case Claim_Transaction:
+		tx = &ClaimTransaction{Transaction: NewTransaction()}
+	case Enrollment_Transaction:
+		tx = &EnrollmentTransaction{Transaction: NewTransaction()}
+	case Register_Transaction:
+		tx = &RegisterTransaction{Transaction: NewTransaction()}
+	case Contract_Transaction:
+		tx = &ContractTransaction{Transaction: NewTransaction()}
+	case State_Transaction:
+		tx = &StateTransaction{Transaction: NewTransaction()}
+	case Publish_Transaction:
+		tx = &PublishTransaction{Transaction: NewTransaction()}
+	case Invocation_Transaction:
+		tx = &InvocationTransaction{Transaction: NewTransaction()}
+	default:
+		return nil
+	}
+	tx.GetTransaction().Type = t
+	return tx
 }
 
 func (t *Transaction) DeserializeUnsigned1(br *io.BinaryReader) {
@@ -133,8 +358,11 @@ func (t *Transaction) AddAddressToAttribute(address string) error {
 }
 
 type ITransaction interface {
+	io.Serializable
 	GetTransaction() *Transaction
 	UnsignedRawTransaction() []byte
+	RawTransaction() []byte
+	HashString() string
 }
 
 // add signature for ITransaction
@@ -95,46 +95,19 @@ func NewTransactionAttributeUsageFromString(s string) TransactionAttributeUsage
 		return DescriptionUrl
 	case "Description":
 		return Description
-	case "Hash1":
-	case "Hash2":
-	case "Hash3":
-	case "Hash4":
-	case "Hash5":
-	case "Hash6":
-	case "Hash7":
-	case "Hash8":
-	case "Hash9":
-	case "Hash10":
-	case "Hash11":
-	case "Hash12":
-	case "Hash13":
-	case "Hash14":
-	case "Hash15":
+	case "Hash1", "Hash2", "Hash3", "Hash4", "Hash5", "Hash6", "Hash7", "Hash8",
+		"Hash9", "Hash10", "Hash11", "Hash12", "Hash13", "Hash14", "Hash15":
 		sub := s[4:]
 		n, _ := strconv.Atoi(sub)
 		return TransactionAttributeUsage(byte(n + 160))
 	case "Remark":
 		return Remark
-	case "Remark1":
-	case "Remark2":
-	case "Remark3":
-	case "Remark4":
-	case "Remark5":
-	case "Remark6":
-	case "Remark7":
-	case "Remark8":
-	case "Remark9":
-	case "Remark10":
-	case "Remark11":
-	case "Remark12":
-	case "Remark13":
-	case "Remark14":
-	case "Remark15":
+	case "Remark1", "Remark2", "Remark3", "Remark4", "Remark5", "Remark6", "Remark7", "Remark8",
+		"Remark9", "Remark10", "Remark11", "Remark12", "Remark13", "Remark14", "Remark15":
 		sub := s[6:]
 		n, _ := strconv.Atoi(sub)
 		return TransactionAttributeUsage(byte(n + 240))
 	default:
 		return Remark
 	}
-	return Remark
 }
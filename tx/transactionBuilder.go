@@ -7,7 +7,7 @@ import (
 	"github.com/joeqian10/neo-gogogo/rpc/models"
 	"github.com/joeqian10/neo-gogogo/sc"
 	"math/big"
-	"sort"
+	"strings"
 )
 
 const NeoTokenId = "c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b"
@@ -17,8 +17,10 @@ var NeoToken, _ = helper.UInt256FromString(NeoTokenId)
 var GasToken, _ = helper.UInt256FromString(GasTokenId)
 
 type TransactionBuilder struct {
-	EndPoint string
-	Client   rpc.IRpcClient // new node
+	EndPoint  string
+	Client    rpc.IRpcClient // new node
+	Selector  CoinSelector   // selects the inputs, LargestFirstSelector is used if it is nil
+	FeePolicy FeePolicy      // computes the required fees, DefaultFeePolicy is used if it is nil
 }
 
 func NewTransactionBuilder(endPoint string) *TransactionBuilder {
@@ -32,8 +34,21 @@ func NewTransactionBuilder(endPoint string) *TransactionBuilder {
 	}
 }
 
+// NewTransactionBuilderFromClient uses an existing client, such as a rpc.RpcClientPool
+func NewTransactionBuilderFromClient(client rpc.IRpcClient) *TransactionBuilder {
+	return &TransactionBuilder{
+		Client: client,
+	}
+}
+
 func (tb *TransactionBuilder) MakeContractTransaction(from helper.UInt160, to helper.UInt160, assetId helper.UInt256, amount helper.Fixed8,
 	attributes []*TransactionAttribute, changeAddress helper.UInt160, fee helper.Fixed8) (*ContractTransaction, error) {
+	return tb.MakeContractTransactionWithSelector(from, to, assetId, amount, attributes, changeAddress, fee, tb.Selector)
+}
+
+// MakeContractTransactionWithSelector is the same as MakeContractTransaction, but the inputs are selected by selector
+func (tb *TransactionBuilder) MakeContractTransactionWithSelector(from helper.UInt160, to helper.UInt160, assetId helper.UInt256, amount helper.Fixed8,
+	attributes []*TransactionAttribute, changeAddress helper.UInt160, fee helper.Fixed8, selector CoinSelector) (*ContractTransaction, error) {
 	if changeAddress.String() == "0000000000000000000000000000000000000000" {
 		changeAddress = from
 	}
@@ -56,7 +71,7 @@ func (tb *TransactionBuilder) MakeContractTransaction(from helper.UInt160, to he
 		// has network fee
 		if assetIdString == GasTokenId { // all are gas
 			amount = amount.Add(fee)
-			inputs, totalPayGas, err = tb.GetTransactionInputs(from, GasToken, amount)
+			inputs, totalPayGas, err = tb.GetTransactionInputsWithSelector(from, GasToken, amount, selector)
 			if err != nil {
 				return nil, err
 			}
@@ -64,14 +79,14 @@ func (tb *TransactionBuilder) MakeContractTransaction(from helper.UInt160, to he
 				outputs = append(outputs, NewTransactionOutput(assetId, totalPayGas.Sub(amount), changeAddress))
 			}
 		} else { // more than gas
-			inputs, totalPay, err = tb.GetTransactionInputs(from, assetId, amount)
+			inputs, totalPay, err = tb.GetTransactionInputsWithSelector(from, assetId, amount, selector)
 			if err != nil {
 				return nil, err
 			}
 			if totalPay.GreaterThan(amount) {
 				outputs = append(outputs, NewTransactionOutput(assetId, totalPay.Sub(amount), changeAddress))
 			}
-			gasInputs, totalPayGas, err = tb.GetTransactionInputs(from, GasToken, fee)
+			gasInputs, totalPayGas, err = tb.GetTransactionInputsWithSelector(from, GasToken, fee, selector)
 			if err != nil {
 				return nil, err
 			}
@@ -84,7 +99,7 @@ func (tb *TransactionBuilder) MakeContractTransaction(from helper.UInt160, to he
 		}
 	} else {
 		// no network fee
-		inputs, totalPay, err = tb.GetTransactionInputs(from, assetId, amount)
+		inputs, totalPay, err = tb.GetTransactionInputsWithSelector(from, assetId, amount, selector)
 		if err != nil {
 			return nil, err
 		}
@@ -99,51 +114,50 @@ func (tb *TransactionBuilder) MakeContractTransaction(from helper.UInt160, to he
 
 // get transaction inputs according to the amount, and return UTXOs and their total amount
 func (tb *TransactionBuilder) GetTransactionInputs(from helper.UInt160, assetId helper.UInt256, amount helper.Fixed8) ([]*CoinReference, helper.Fixed8, error) {
+	return tb.GetTransactionInputsWithSelector(from, assetId, amount, tb.Selector)
+}
+
+// GetTransactionInputsWithSelector is the same as GetTransactionInputs, but the UTXOs are selected by selector
+func (tb *TransactionBuilder) GetTransactionInputsWithSelector(from helper.UInt160, assetId helper.UInt256, amount helper.Fixed8, selector CoinSelector) ([]*CoinReference, helper.Fixed8, error) {
 	if amount.Equal(helper.Zero) {
 		return nil, helper.Zero, nil
 	}
-	unspentBalance, available, err := tb.GetBalance(from, assetId)
+	unspentBalance, _, err := tb.GetBalance(from, assetId)
 	if err != nil {
 		return nil, helper.Zero, err
 	}
-	if available.LessThan(amount) {
-		return nil, helper.Zero, fmt.Errorf("not enough balance in address: %s", helper.ScriptHashToAddress(from))
-	}
-	unspents := unspentBalance.Unspents
-	sort.Sort(sort.Reverse(models.UnspentSlice(unspents))) // sort in decreasing order
-	var i int = 0
-	var a float64 = helper.Fixed8ToFloat64(amount)
-	var inputs []*CoinReference = []*CoinReference{}
-	var sum helper.Fixed8 = helper.Zero
-	for i < len(unspents) && unspents[i].Value <= a {
-		a -= unspents[i].Value
-		inputs = append(inputs, ToCoinReference(unspents[i]))
-		sum = sum.Add(helper.Fixed8FromFloat64(unspents[i].Value))
-		i++
-	}
-	if a == 0 {
-		return inputs, sum, nil
-	}
-	// use the nearest amount
-	for i < len(unspents) && unspents[i].Value >= a {
-		i++
-	}
-	inputs = append(inputs, ToCoinReference(unspents[i-1]))
-	sum = sum.Add(helper.Fixed8FromFloat64(unspents[i-1].Value))
-	return inputs, sum, nil
+	coins := make([]Coin, len(unspentBalance.Unspents))
+	for i, u := range unspentBalance.Unspents {
+		if coins[i], err = NewCoin(u); err != nil {
+			return nil, helper.Zero, err
+		}
+	}
+	if selector == nil {
+		selector = LargestFirstSelector{}
+	}
+	selected, err := selector.Select(coins, amount)
+	if err != nil {
+		return nil, helper.Zero, fmt.Errorf("not enough balance in address %s: %w", helper.ScriptHashToAddress(from), err)
+	}
+	inputs := make([]*CoinReference, len(selected))
+	for i := range selected {
+		input := selected[i].Reference
+		inputs[i] = &input
+	}
+	return inputs, SumCoins(selected), nil
 }
 
 // GetBalance is used to get balance of neo or gas or other utxo asset
 func (tb *TransactionBuilder) GetBalance(account helper.UInt160, assetId helper.UInt256) (*models.UnspentBalance, helper.Fixed8, error) {
 	response := tb.Client.GetUnspents(helper.ScriptHashToAddress(account))
 	if response.HasError() {
-		return nil, helper.Zero, fmt.Errorf(response.ErrorResponse.Error.Message)
+		return nil, helper.Zero, response.GetError()
 	}
 	balances := response.Result.Balances
 	// check if there is enough balance of this asset in this account
 	for _, balance := range balances {
 		if balance.AssetHash == assetId.String() {
-			return &balance, helper.Fixed8FromFloat64(balance.Amount), nil
+			return &balance, balance.Amount, nil
 		}
 	}
 	return nil, helper.Zero, fmt.Errorf("asset not found")
@@ -151,26 +165,34 @@ func (tb *TransactionBuilder) GetBalance(account helper.UInt160, assetId helper.
 
 // this is a general api for invoking smart contract and creating an invocation transaction, including transferring nep-5 assets
 func (tb *TransactionBuilder) MakeInvocationTransaction(script []byte, from helper.UInt160, attributes []*TransactionAttribute, changeAddress helper.UInt160, sysFee helper.Fixed8, netFee helper.Fixed8) (*InvocationTransaction, error) {
-	if changeAddress.String() == "0000000000000000000000000000000000000000" {
-		changeAddress = from
-	}
+	return tb.MakeInvocationTransactionWithSelector(script, from, attributes, changeAddress, sysFee, netFee, tb.Selector)
+}
+
+// MakeInvocationTransactionWithSelector is the same as MakeInvocationTransaction, but the gas inputs are selected by selector
+func (tb *TransactionBuilder) MakeInvocationTransactionWithSelector(script []byte, from helper.UInt160, attributes []*TransactionAttribute, changeAddress helper.UInt160, sysFee helper.Fixed8, netFee helper.Fixed8, selector CoinSelector) (*InvocationTransaction, error) {
 	// use rpc to get gas consumed
 	gasConsumed, err := tb.GetGasConsumed(script, from.String())
 	if err != nil {
 		return nil, err
 	}
+	return tb.MakeInvocationTransactionWithGas(script, from, attributes, changeAddress, gasConsumed.Add(sysFee), netFee, selector)
+}
+
+// MakeInvocationTransactionWithGas is the same as MakeInvocationTransactionWithSelector, but the script is not run,
+// gas is the system fee of the transaction
+func (tb *TransactionBuilder) MakeInvocationTransactionWithGas(script []byte, from helper.UInt160, attributes []*TransactionAttribute, changeAddress helper.UInt160, gas helper.Fixed8, netFee helper.Fixed8, selector CoinSelector) (*InvocationTransaction, error) {
+	if changeAddress.String() == "0000000000000000000000000000000000000000" {
+		changeAddress = from
+	}
 	itx := NewInvocationTransaction(script)
 	if attributes != nil {
 		itx.Attributes = attributes
 	}
-	itx.Gas = gasConsumed.Add(sysFee) // add sys fee
+	itx.Gas = gas
 	fee := itx.Gas.Add(netFee) // add net fee
-	if itx.Size() > 1024 {
-		fee = fee.Add(helper.Fixed8FromFloat64(0.001))
-		fee = fee.Add(helper.Fixed8FromFloat64(float64(itx.Size()) * 0.00001))
-	}
+	fee = fee.Add(tb.feePolicy().NetworkFeeOfSize(itx.Size()))
 	// get transaction inputs
-	inputs, totalPayGas, err := tb.GetTransactionInputs(from, GasToken, fee)
+	inputs, totalPayGas, err := tb.GetTransactionInputsWithSelector(from, GasToken, fee, selector)
 	if err != nil {
 		return nil, err
 	}
@@ -181,10 +203,17 @@ func (tb *TransactionBuilder) MakeInvocationTransaction(script []byte, from help
 	return itx, nil
 }
 
+func (tb *TransactionBuilder) feePolicy() FeePolicy {
+	if tb.FeePolicy == nil {
+		return DefaultFeePolicy{}
+	}
+	return tb.FeePolicy
+}
+
 func (tb *TransactionBuilder) GetGasConsumed(script []byte, checkWitnessHashes string) (*helper.Fixed8, error) {
 	response := tb.Client.InvokeScript(helper.BytesToHex(script), checkWitnessHashes)
 	if response.HasError() {
-		return nil, fmt.Errorf(response.ErrorResponse.Error.Message)
+		return nil, response.GetError()
 	}
 	if response.Result.State == "FAULT" { // use ScriptContainer in contract will cause engine fault
 		result := helper.Fixed8FromInt64(0)
@@ -194,17 +223,38 @@ func (tb *TransactionBuilder) GetGasConsumed(script []byte, checkWitnessHashes s
 	//if response.Result.State == "FAULT" {
 	//	return nil, fmt.Errorf("engine faulted")
 	//}
-	gasConsumed, err := helper.Fixed8FromString(response.Result.GasConsumed)
+	gas, err := tb.gasToPay(response.Result.GasConsumed)
 	if err != nil {
 		return nil, err
 	}
-	gas := gasConsumed.Sub(helper.Fixed8FromInt64(10))
-	if gas.LessThan(helper.Zero) || gas.Equal(helper.Zero) {
-		return &helper.Zero, nil
-	} else {
-		g := gas.Ceiling()
-		return &g, nil
+	return &gas, nil
+}
+
+// GetGasConsumedWithWitnesses returns the gas to pay for script the same as GetGasConsumed, CheckWitness returns
+// true for all of checkWitnessHashes in the test run, or only the first one if the client is not an
+// rpc.IWitnessInvoker. An error is returned if the engine faults.
+func (tb *TransactionBuilder) GetGasConsumedWithWitnesses(script []byte, checkWitnessHashes []helper.UInt160) (helper.Fixed8, error) {
+	hashes := make([]string, len(checkWitnessHashes))
+	for i, h := range checkWitnessHashes {
+		hashes[i] = h.String()
+	}
+	response := rpc.InvokeScriptWithWitnesses(tb.Client, helper.BytesToHex(script), hashes)
+	if response.HasError() {
+		return helper.Zero, response.GetError()
+	}
+	if strings.Contains(response.Result.State, "FAULT") {
+		return helper.Zero, fmt.Errorf("engine faulted when estimating the gas")
+	}
+	return tb.gasToPay(response.Result.GasConsumed)
+}
+
+// gasToPay returns the Gas of an InvocationTransaction by the FeePolicy, the free gas is deducted
+func (tb *TransactionBuilder) gasToPay(gasConsumed string) (helper.Fixed8, error) {
+	consumed, err := helper.Fixed8FromString(gasConsumed)
+	if err != nil {
+		return helper.Zero, err
 	}
+	return tb.feePolicy().InvocationGas(consumed), nil
 }
 
 func (tb *TransactionBuilder) MakeClaimTransaction(from helper.UInt160, changeAddress helper.UInt160, attributes []*TransactionAttribute) (*ClaimTransaction, error) {
@@ -235,7 +285,7 @@ func (tb *TransactionBuilder) MakeClaimTransaction(from helper.UInt160, changeAd
 func (tb *TransactionBuilder) GetClaimables(from helper.UInt160) ([]*CoinReference, *helper.Fixed8, error) {
 	response := tb.Client.GetClaimable(helper.ScriptHashToAddress(from))
 	if response.HasError() {
-		return nil, nil, fmt.Errorf(response.ErrorResponse.Error.Message)
+		return nil, nil, response.GetError()
 	}
 	var claims []*CoinReference
 	claimables := response.Result.Claimables
@@ -252,7 +302,7 @@ func (tb *TransactionBuilder) GetClaimables(from helper.UInt160) ([]*CoinReferen
 			PrevIndex: uint16(claimables[i].N),
 		}
 		claims = append(claims, claim)
-		total = total.Add(helper.Fixed8FromFloat64(claimables[i].Unclaimed))
+		total = total.Add(claimables[i].Unclaimed)
 	}
 	return claims, &total, nil
 }
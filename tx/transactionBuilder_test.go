@@ -1,9 +1,11 @@
 package tx
 
 import (
+	"errors"
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/rpc"
 	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -17,6 +19,12 @@ func TestNewTransactionBuilder(t *testing.T) {
 	assert.Equal(t, "http://seed1.ngd.network:20332", tb.EndPoint)
 }
 
+func TestNewTransactionBuilderFromClient(t *testing.T) {
+	pool := rpc.NewClientPool("http://seed1.ngd.network:20332", "http://seed2.ngd.network:20332")
+	tb := NewTransactionBuilderFromClient(pool)
+	assert.Equal(t, pool, tb.Client)
+}
+
 func TestTransactionBuilder_GetBalance(t *testing.T) {
 	var clientMock = new(rpc.RpcClientMock)
 	var tb = TransactionBuilder{
@@ -26,7 +34,7 @@ func TestTransactionBuilder_GetBalance(t *testing.T) {
 	clientMock.On("GetUnspents", mock.Anything).Return(rpc.GetUnspentsResponse{
 		RpcResponse: rpc.RpcResponse{
 			JsonRpc: "2.0",
-			ID: 1,
+			ID:      1,
 		},
 		ErrorResponse: rpc.ErrorResponse{
 			Error: rpc.RpcError{
@@ -37,30 +45,30 @@ func TestTransactionBuilder_GetBalance(t *testing.T) {
 		Result: models.RpcUnspent{
 			Balances: []models.UnspentBalance{
 				{
-					Unspents:    []models.Unspent{
+					Unspents: []models.Unspent{
 						{
 							Txid:  "4ee4af75d5aa60598fbae40ce86fb9a23ffec5a75dfa8b59d259d15f9e304319",
 							N:     0,
-							Value: 27844.821,
+							Value: helper.NewFixed8(2784482100000), // 27844.821
 						},
 					},
 					AssetHash:   "602c79718b16e442de58778e148d0b1084e3b2dffd5de6b7b16cee7969282de7",
 					Asset:       "GAS",
 					AssetSymbol: "GAS",
-					Amount:      27844.821,
+					Amount:      helper.NewFixed8(2784482100000), // 27844.821
 				},
 				{
-					Unspents:    []models.Unspent{
+					Unspents: []models.Unspent{
 						{
 							Txid:  "c3182952855314b3f4b1ecf01a03b891d4627d19426ce841275f6d4c186e729a",
 							N:     0,
-							Value: 800000,
+							Value: helper.Fixed8FromInt64(800000),
 						},
 					},
 					AssetHash:   "c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b",
 					Asset:       "NEO",
 					AssetSymbol: "NEO",
-					Amount:      800000,
+					Amount:      helper.Fixed8FromInt64(800000),
 				},
 			},
 			Address: "AGofsxAUDwt52KjaB664GYsqVAkULYvKNt",
@@ -80,9 +88,9 @@ func TestTransactionBuilder_GetGasConsumed(t *testing.T) {
 		Client:   clientMock,
 	}
 	clientMock.On("InvokeScript", mock.Anything, mock.Anything).Return(rpc.InvokeScriptResponse{
-		RpcResponse:   rpc.RpcResponse{
+		RpcResponse: rpc.RpcResponse{
 			JsonRpc: "2.0",
-			ID: 1,
+			ID:      1,
 		},
 		ErrorResponse: rpc.ErrorResponse{
 			Error: rpc.RpcError{
@@ -90,14 +98,14 @@ func TestTransactionBuilder_GetGasConsumed(t *testing.T) {
 				Message: "",
 			},
 		},
-		Result:        models.InvokeResult{
-			Script:"00c1046e616d656763d26113bac4208254d98a3eebaee66230ead7b9",
-			State:"HALT",
-			GasConsumed:"0.126",
-			Stack: []models.InvokeStack{
+		Result: models.InvokeResult{
+			Script:      "00c1046e616d656763d26113bac4208254d98a3eebaee66230ead7b9",
+			State:       "HALT",
+			GasConsumed: "0.126",
+			Stack: []sc.ContractParameter{
 				{
-					Type:  "ByteArray",
-					Value: "516c696e6b20546f6b656e",
+					Type:  sc.ByteArray,
+					Value: helper.HexToBytes("516c696e6b20546f6b656e"),
 				},
 			},
 		},
@@ -108,6 +116,89 @@ func TestTransactionBuilder_GetGasConsumed(t *testing.T) {
 	assert.Equal(t, helper.Fixed8FromFloat64(0).Value, f.Value) // 10 gas free limit
 }
 
+// noSizeFeePolicy is the policy of a private net without the size fee, and 20 free GAS
+type noSizeFeePolicy struct{}
+
+func (noSizeFeePolicy) InvocationGas(gasConsumed helper.Fixed8) helper.Fixed8 {
+	gas := gasConsumed.Sub(helper.Fixed8FromInt64(20))
+	if !gas.GreaterThan(helper.Zero) {
+		return helper.Zero
+	}
+	return gas.Ceiling()
+}
+
+func (noSizeFeePolicy) NetworkFeeOfSize(size int) helper.Fixed8 {
+	return helper.Zero
+}
+
+func TestTransactionBuilder_MakeInvocationTransactionWithGas(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	var tb = TransactionBuilder{
+		EndPoint: "",
+		Client:   clientMock,
+	}
+	clientMock.On("GetUnspents", mock.Anything).Return(rpc.GetUnspentsResponse{
+		Result: models.RpcUnspent{
+			Balances: []models.UnspentBalance{
+				{
+					Unspents: []models.Unspent{
+						{
+							Txid:  "0a99ebd286931375c2ec828603e88392e3a40e9cecd4b228bd6be206fdb21005",
+							N:     0,
+							Value: helper.Fixed8FromInt64(1),
+						},
+					},
+					AssetHash: GasTokenId,
+					Amount:    helper.Fixed8FromInt64(1),
+				},
+			},
+		},
+	})
+	script := make([]byte, 2000)
+	netFee := helper.Fixed8FromFloat64(0.5)
+
+	// the size fee of the default policy, 0.00001 GAS for each byte above 1024
+	itx, err := tb.MakeInvocationTransactionWithGas(script, helper.UInt160{}, nil, helper.UInt160{}, helper.Zero, netFee, nil)
+	assert.Nil(t, err)
+	// the size is taken before the inputs and the outputs are added
+	sizeFee := helper.NewFixed8(int64(NewInvocationTransaction(script).Size()-1024) * 1000)
+	assert.Equal(t, helper.Fixed8FromInt64(1).Sub(netFee).Sub(sizeFee), itx.Outputs[0].Value)
+
+	tb.FeePolicy = noSizeFeePolicy{}
+	itx, err = tb.MakeInvocationTransactionWithGas(script, helper.UInt160{}, nil, helper.UInt160{}, helper.Zero, netFee, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, netFee, itx.Outputs[0].Value)
+}
+
+func TestTransactionBuilder_GetGasConsumedWithWitnesses(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	var tb = TransactionBuilder{
+		EndPoint: "",
+		Client:   clientMock,
+	}
+	a := helper.UInt160{1}
+	b := helper.UInt160{2}
+	clientMock.On("InvokeScriptWithWitnesses", "51", []string{a.String(), b.String()}).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{State: "HALT", GasConsumed: "11.2"},
+	})
+	clientMock.On("InvokeScriptWithWitnesses", "51", []string{a.String()}).Return(rpc.InvokeScriptResponse{
+		Result: models.InvokeResult{State: "FAULT, BREAK", GasConsumed: "0.1"},
+	})
+
+	f, e := tb.GetGasConsumedWithWitnesses([]byte{0x51}, []helper.UInt160{a, b})
+	assert.Nil(t, e)
+	assert.Equal(t, helper.Fixed8FromInt64(2).Value, f.Value)
+
+	_, e = tb.GetGasConsumedWithWitnesses([]byte{0x51}, []helper.UInt160{a})
+	assert.NotNil(t, e)
+
+	// the free gas of another network
+	tb.FeePolicy = noSizeFeePolicy{}
+	f, e = tb.GetGasConsumedWithWitnesses([]byte{0x51}, []helper.UInt160{a, b})
+	assert.Nil(t, e)
+	assert.Equal(t, helper.Zero, f)
+}
+
 func TestTransactionBuilder_GetClaimables(t *testing.T) {
 	var clientMock = new(rpc.RpcClientMock)
 	var tb = TransactionBuilder{
@@ -115,9 +206,9 @@ func TestTransactionBuilder_GetClaimables(t *testing.T) {
 		Client:   clientMock,
 	}
 	clientMock.On("GetClaimable", mock.Anything).Return(rpc.GetClaimableResponse{
-		RpcResponse:   rpc.RpcResponse{
+		RpcResponse: rpc.RpcResponse{
 			JsonRpc: "2.0",
-			ID: 1,
+			ID:      1,
 		},
 		ErrorResponse: rpc.ErrorResponse{
 			Error: rpc.RpcError{
@@ -125,7 +216,7 @@ func TestTransactionBuilder_GetClaimables(t *testing.T) {
 				Message: "",
 			},
 		},
-		Result:        models.RpcClaimable{
+		Result: models.RpcClaimable{
 			Claimables: []models.Claimable{
 				{
 					TxId:        "52ba70ef18e879785572c917795cd81422c3820b8cf44c24846a30ee7376fd77",
@@ -133,13 +224,13 @@ func TestTransactionBuilder_GetClaimables(t *testing.T) {
 					Value:       800000,
 					StartHeight: 476496,
 					EndHeight:   488154,
-					Generated:   746.112,
-					SysFee:      3.92,
-					Unclaimed:   750.032,
+					Generated:   helper.NewFixed8(74611200000), // 746.112
+					SysFee:      helper.NewFixed8(392000000),   // 3.92
+					Unclaimed:   helper.NewFixed8(75003200000), // 750.032
 				},
 			},
-			Address:"AGofsxAUDwt52KjaB664GYsqVAkULYvKNt",
-			Unclaimed:750.032,
+			Address:   "AGofsxAUDwt52KjaB664GYsqVAkULYvKNt",
+			Unclaimed: helper.NewFixed8(75003200000), // 750.032
 		},
 	})
 
@@ -176,36 +267,36 @@ func TestTransactionBuilder_GetTransactionInputs(t *testing.T) {
 						{
 							Txid:  "0a99ebd286931375c2ec828603e88392e3a40e9cecd4b228bd6be206fdb21005",
 							N:     0,
-							Value: 11250,
+							Value: helper.Fixed8FromInt64(11250),
 						},
 						{
 							Txid:  "1c2f4605fa4c5ba9ca2a8ae87ae083a241d407f59472e707fe34e52d277d2331",
 							N:     1,
-							Value: 81.96167,
+							Value: helper.NewFixed8(8196167000), // 81.96167
 						},
 						{
 							Txid:  "6d7de9f60c1b8c86f3a2a0d8001c051e9192d59fd2e922f02516770533e0cfc4",
 							N:     0,
-							Value: 0.03833,
+							Value: helper.NewFixed8(3833000), // 0.03833
 						},
 					},
 					AssetHash:   "602c79718b16e442de58778e148d0b1084e3b2dffd5de6b7b16cee7969282de7",
 					Asset:       "GAS",
 					AssetSymbol: "GAS",
-					Amount:      11332,
+					Amount:      helper.Fixed8FromInt64(11332),
 				},
 				{
 					Unspents: []models.Unspent{
 						{
 							Txid:  "c724d26a3e2bb4417f6cebd56a7c5138987dc0b49b41fe1b5c632f5208c1e05f",
 							N:     0,
-							Value: 100000000,
+							Value: helper.Fixed8FromInt64(100000000),
 						},
 					},
 					AssetHash:   "c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b",
 					Asset:       "NEO",
 					AssetSymbol: "NEO",
-					Amount:      100000000,
+					Amount:      helper.Fixed8FromInt64(100000000),
 				},
 			},
 			Address: "AGofsxAUDwt52KjaB664GYsqVAkULYvKNt",
@@ -216,6 +307,21 @@ func TestTransactionBuilder_GetTransactionInputs(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(inputs))
 	assert.Equal(t, int64(1125000000000), payTotal.Value)
+
+	_, _, err = tb.GetTransactionInputs(helper.UInt160{}, GasToken, helper.Fixed8FromFloat64(20000))
+	assert.True(t, errors.Is(err, rpc.ErrInsufficientFunds))
+
+	// 0.03833 + 81.96167 is exactly 82
+	inputs, payTotal, err = tb.GetTransactionInputsWithSelector(helper.UInt160{}, GasToken, helper.Fixed8FromInt64(80), SmallestFirstSelector{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(inputs))
+	assert.Equal(t, int64(8200000000), payTotal.Value)
+
+	tb.Selector = MaxInputsSelector{Selector: SmallestFirstSelector{}, MaxInputs: 1}
+	inputs, payTotal, err = tb.GetTransactionInputs(helper.UInt160{}, GasToken, helper.Fixed8FromInt64(80))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(inputs))
+	assert.Equal(t, int64(1125000000000), payTotal.Value)
 }
 
 func TestTransactionBuilder_LoadScriptTransaction(t *testing.T) {
@@ -225,7 +331,7 @@ func TestTransactionBuilder_LoadScriptTransaction(t *testing.T) {
 		Client:   clientMock,
 	}
 
-	script := []byte{ 0x01, 0x02, 0x03, 0x04 }
+	script := []byte{0x01, 0x02, 0x03, 0x04}
 	paramList := "0710"
 	returnType := "05"
 	hasStorage := true
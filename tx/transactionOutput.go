@@ -3,6 +3,7 @@ package tx
 import (
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/helper/io"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
 )
 
 // TransactionOutput
@@ -21,6 +22,22 @@ func NewTransactionOutput(assetID helper.UInt256, value helper.Fixed8, scriptHas
 	}
 }
 
+func NewTransactionOutputFromRPC(output models.RpcTransactionOutput) (*TransactionOutput, error) {
+	assetId, err := helper.UInt256FromString(output.Asset)
+	if err != nil {
+		return nil, err
+	}
+	value, err := helper.Fixed8FromString(output.Value)
+	if err != nil {
+		return nil, err
+	}
+	scriptHash, err := helper.AddressToScriptHash(output.Address)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransactionOutput(assetId, value, scriptHash), nil
+}
+
 // Deserialize implements Serializable interface.
 func (out *TransactionOutput) Deserialize(br *io.BinaryReader) {
 	br.ReadLE(&out.AssetId)
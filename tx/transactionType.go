@@ -1,6 +1,9 @@
 package tx
 
-import "strconv"
+import (
+	"fmt"
+	"strconv"
+)
 
 // Transaction types
 type TransactionType uint8
@@ -43,4 +46,15 @@ func (t TransactionType) String() string {
 	default:
 		return "TransactionType=" + strconv.FormatUint(uint64(t), 10)
 	}
-}
\ No newline at end of file
+}
+// NewTransactionTypeFromString parses the type name used in json, such as "ContractTransaction"
+func NewTransactionTypeFromString(s string) (TransactionType, error) {
+	for _, t := range []TransactionType{Miner_Transaction, Issue_Transaction, Claim_Transaction,
+		Enrollment_Transaction, Register_Transaction, Contract_Transaction, State_Transaction,
+		Publish_Transaction, Invocation_Transaction} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown transaction type: %s", s)
+}
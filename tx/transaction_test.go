@@ -1,6 +1,9 @@
 package tx
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
 	"github.com/joeqian10/neo-gogogo/wallet/keys"
 	"github.com/stretchr/testify/assert"
 	"sort"
@@ -65,3 +68,70 @@ func TestAddMultiSignature(t *testing.T) {
 	assert.True(t, ctx.Witnesses[1].scriptHash.Less(ctx.Witnesses[2].scriptHash))
 	assert.True(t, ctx.Witnesses[2].scriptHash.Less(ctx.Witnesses[3].scriptHash))
 }
+
+func TestNewTransactionFromHexString(t *testing.T) {
+	// mainnet transaction: bdf6cc3b9af12a7565bda80933a75ee8cef1bc771d0d58effc08e4c8b436da79
+	rawTx := "80000001888da99f8f497fd65c4325786a09511159c279af4e7eb532e9edd628c87cc1ee0000019b7cffdaa674beae0f930ebe6085af9093e5fe56b34a5c220ccdcf6efc336fc50082167010000000a8666b4830229d6a1a9b80f6088059191c122d2b0141409e79e132290c82916a88f1a3db5cf9f3248b780cfece938ab0f0812d0e188f3a489c7d1a23def86bd69d863ae67de753b2c2392e9497eadc8eb9fc43aa52c645232103e2f6a334e05002624cf616f01a62cff2844c34a3b08ca16048c259097e315078ac"
+	tx, err := NewTransactionFromHexString(rawTx)
+	assert.Nil(t, err)
+	assert.IsType(t, &ContractTransaction{}, tx)
+	assert.Equal(t, "bdf6cc3b9af12a7565bda80933a75ee8cef1bc771d0d58effc08e4c8b436da79", tx.HashString())
+	assert.Equal(t, rawTx, hex.EncodeToString(tx.RawTransaction()))
+
+	rawTx = "d101590400b33f7114839c33710da24cf8e7d536b8d244f3991cf565c8146063795d3b9b3cd55aef026eae992b91063db0db53c1087472616e7366657267c5cc1cb5392019e2cc4e6d6b5ea54c8d4b6d11acf166cb072961424c54f6000000000000000001206063795d3b9b3cd55aef026eae992b91063db0db0000014140c6a131c55ca38995402dff8e92ac55d89cbed4b98dfebbcb01acbc01bd78fa2ce2061be921b8999a9ab79c2958875bccfafe7ce1bbbaf1f56580815ea3a4feed232102d41ddce2c97be4c9aa571b8a32cbc305aa29afffbcae71b0ef568db0e93929aaac"
+	tx, err = NewTransactionFromHexString(rawTx)
+	assert.Nil(t, err)
+	assert.IsType(t, &InvocationTransaction{}, tx)
+	assert.Equal(t, rawTx, hex.EncodeToString(tx.RawTransaction()))
+
+	_, err = NewTransactionFromHexString(rawTx + "00")
+	assert.NotNil(t, err)
+	_, err = NewTransactionFromHexString("ff00000000")
+	assert.NotNil(t, err)
+}
+
+func TestNewTransactionFromRPC(t *testing.T) {
+	rpcTx := models.RpcTransaction{}
+	err := json.Unmarshal([]byte(`{
+		"txid": "0xbdf6cc3b9af12a7565bda80933a75ee8cef1bc771d0d58effc08e4c8b436da79",
+		"size": 202,
+		"type": "ContractTransaction",
+		"version": 0,
+		"attributes": [],
+		"vin": [{"txid": "0xeec17cc828d6ede932b57e4eaf79c2591151096a7825435cd67f498f9fa98d88", "vout": 0}],
+		"vout": [{
+			"n": 0,
+			"asset": "0xc56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b",
+			"value": "706",
+			"address": "AX8HrvkgUQn4A1im3PCekWhExxyqQqe2de"
+		}],
+		"sys_fee": "0",
+		"net_fee": "0",
+		"scripts": [{
+			"invocation": "409e79e132290c82916a88f1a3db5cf9f3248b780cfece938ab0f0812d0e188f3a489c7d1a23def86bd69d863ae67de753b2c2392e9497eadc8eb9fc43aa52c645",
+			"verification": "2103e2f6a334e05002624cf616f01a62cff2844c34a3b08ca16048c259097e315078ac"
+		}]
+	}`), &rpcTx)
+	assert.Nil(t, err)
+	tx, err := NewTransactionFromRPC(rpcTx)
+	assert.Nil(t, err)
+	assert.IsType(t, &ContractTransaction{}, tx)
+	assert.Equal(t, "bdf6cc3b9af12a7565bda80933a75ee8cef1bc771d0d58effc08e4c8b436da79", tx.HashString())
+	assert.Equal(t, int64(70600000000), tx.GetTransaction().Outputs[0].Value.Value)
+
+	rpcTx.Vout[0].Value = "705"
+	_, err = NewTransactionFromRPC(rpcTx)
+	assert.NotNil(t, err)
+
+	rpcTx = models.RpcTransaction{
+		Txid:       "0xfe4b3af60677204c57e573a57bdc97bc5059b05ad85b1474f84431f88d910f64",
+		Type:       "InvocationTransaction",
+		Version:    1,
+		Attributes: []models.RpcTransactionAttribute{{Usage: "Script", Data: "6063795d3b9b3cd55aef026eae992b91063db0db"}},
+		Script:     "0400b33f7114839c33710da24cf8e7d536b8d244f3991cf565c8146063795d3b9b3cd55aef026eae992b91063db0db53c1087472616e7366657267c5cc1cb5392019e2cc4e6d6b5ea54c8d4b6d11acf166cb072961424c54f6",
+		Gas:        "0",
+	}
+	tx, err = NewTransactionFromRPC(rpcTx)
+	assert.Nil(t, err)
+	assert.IsType(t, &InvocationTransaction{}, tx)
+}
@@ -0,0 +1,1215 @@
+package vm
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo-gogogo/crypto"
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+)
+
+// limits of neo-vm 2.x
+const (
+	MaxShlShr              = 256
+	MinShlShr              = -256
+	MaxSizeForBigInteger   = 32
+	MaxStackSize           = 2 * 1024
+	MaxItemSize            = 1024 * 1024
+	MaxInvocationStackSize = 1024
+	MaxArraySize           = 1024
+)
+
+// GasRatio is the Fixed8 value of one price unit, which is 0.001 GAS
+const GasRatio = 100000
+
+// VMState is the state of an ExecutionEngine
+type VMState byte
+
+const (
+	NONE  VMState = 0
+	HALT  VMState = 1 << 0
+	FAULT VMState = 1 << 1
+	BREAK VMState = 1 << 2
+)
+
+func (s VMState) String() string {
+	switch s {
+	case NONE:
+		return "NONE"
+	case HALT:
+		return "HALT"
+	case FAULT:
+		return "FAULT"
+	case BREAK:
+		return "BREAK"
+	default:
+		return fmt.Sprintf("VMState(%d)", byte(s))
+	}
+}
+
+// TriggerType is returned by Runtime.GetTrigger
+type TriggerType byte
+
+const (
+	Verification TriggerType = 0x00
+	Application  TriggerType = 0x10
+)
+
+// ExecutionContext is a script being executed, each context has its own stacks the same as neo-vm 2.x
+type ExecutionContext struct {
+	Script             []byte
+	InstructionPointer int
+	// RVCount is the number of items returned to the calling context by RET, -1 for all of them
+	RVCount         int
+	EvaluationStack *RandomAccessStack
+	AltStack        *RandomAccessStack
+
+	scriptHash *helper.UInt160
+}
+
+// NewExecutionContext creates a context which returns all its items
+func NewExecutionContext(script []byte) *ExecutionContext {
+	return newExecutionContext(script, -1)
+}
+
+func newExecutionContext(script []byte, rvcount int) *ExecutionContext {
+	return &ExecutionContext{
+		Script:          script,
+		RVCount:         rvcount,
+		EvaluationStack: NewRandomAccessStack(),
+		AltStack:        NewRandomAccessStack(),
+	}
+}
+
+// ScriptHash returns the script hash of the script
+func (c *ExecutionContext) ScriptHash() helper.UInt160 {
+	if c.scriptHash == nil {
+		h, _ := helper.BytesToScriptHash(c.Script)
+		c.scriptHash = &h
+	}
+	return *c.scriptHash
+}
+
+// clone returns a context of the same script with empty stacks, it is used by CALL and CALL_I
+func (c *ExecutionContext) clone(rvcount int) *ExecutionContext {
+	context := newExecutionContext(c.Script, rvcount)
+	context.InstructionPointer = c.InstructionPointer
+	context.scriptHash = c.scriptHash
+	return context
+}
+
+// nextInstruction returns RET at the end of the script, the same as neo-vm
+func (c *ExecutionContext) nextInstruction() sc.OpCode {
+	if c.InstructionPointer >= len(c.Script) {
+		return sc.RET
+	}
+	return sc.OpCode(c.Script[c.InstructionPointer])
+}
+
+// Notification is sent by Runtime.Notify
+type Notification struct {
+	ScriptHash helper.UInt160
+	State      StackItem
+}
+
+// LogEvent is sent by Runtime.Log
+type LogEvent struct {
+	ScriptHash helper.UInt160
+	Message    string
+}
+
+// ExecutionEngine runs NeoVM 2.x scripts offline, the same as the ApplicationEngine of neo 2.x
+// without the blockchain. The storage and the called contracts are provided by Storage and Scripts.
+//
+//	e := vm.NewExecutionEngine()
+//	e.Scripts = vm.NewScriptTable(contract)
+//	state := e.Run(script)
+//	result := e.GetInvokeResult()
+type ExecutionEngine struct {
+	Trigger   TriggerType
+	Message   []byte           // data signed for CHECKSIG and CHECKMULTISIG, usually the unsigned transaction
+	Witnesses []helper.UInt160 // script hashes which pass Runtime.CheckWitness
+	Timestamp uint32           // returned by Runtime.GetTime
+	GasLimit  helper.Fixed8    // FAULT once the consumed gas exceeds it, zero for no limit
+
+	Interop *InteropService
+	Storage IStorage
+	Scripts IScriptTable
+
+	State           VMState
+	FaultReason     error
+	InvocationStack []*ExecutionContext // the current context is the last one
+	ResultStack     *RandomAccessStack  // the items returned by the entry context
+	Notifications   []Notification
+	Logs            []LogEvent
+
+	entryScript []byte
+	gasConsumed int64
+}
+
+// NewExecutionEngine creates an engine with the standard interop services and an empty memory storage
+func NewExecutionEngine() *ExecutionEngine {
+	return &ExecutionEngine{
+		Trigger:     Application,
+		Interop:     NewStandardInteropService(),
+		Storage:     NewMemoryStorage(),
+		Scripts:     ScriptTable{},
+		ResultStack: NewRandomAccessStack(),
+	}
+}
+
+// GasConsumed returns the GAS consumed by the executed instructions
+func (e *ExecutionEngine) GasConsumed() helper.Fixed8 {
+	return helper.NewFixed8(e.gasConsumed)
+}
+
+// AddGas consumes price units of 0.001 GAS, it fails if the GasLimit is exceeded
+func (e *ExecutionEngine) AddGas(price int64) error {
+	e.gasConsumed += price * GasRatio
+	if e.GasLimit.Value > 0 && e.gasConsumed > e.GasLimit.Value {
+		return fmt.Errorf("gas limit %s exceeded", e.GasLimit.String())
+	}
+	return nil
+}
+
+// CurrentContext returns the context being executed, nil if there is none
+func (e *ExecutionEngine) CurrentContext() *ExecutionContext {
+	if len(e.InvocationStack) == 0 {
+		return nil
+	}
+	return e.InvocationStack[len(e.InvocationStack)-1]
+}
+
+// CallingContext returns the context which called the current one, nil if there is none
+func (e *ExecutionEngine) CallingContext() *ExecutionContext {
+	if len(e.InvocationStack) < 2 {
+		return nil
+	}
+	return e.InvocationStack[len(e.InvocationStack)-2]
+}
+
+// EntryContext returns the context of the first loaded script
+func (e *ExecutionEngine) EntryContext() *ExecutionContext {
+	if len(e.InvocationStack) == 0 {
+		return nil
+	}
+	return e.InvocationStack[0]
+}
+
+// EvaluationStack returns the evaluation stack of the current context, or the ResultStack once the
+// entry context has returned, it is used by the interop services
+func (e *ExecutionEngine) EvaluationStack() *RandomAccessStack {
+	if context := e.CurrentContext(); context != nil {
+		return context.EvaluationStack
+	}
+	return e.ResultStack
+}
+
+// LoadScript pushes a new context of script onto the invocation stack
+func (e *ExecutionEngine) LoadScript(script []byte) *ExecutionContext {
+	if e.entryScript == nil {
+		e.entryScript = script
+	}
+	context := NewExecutionContext(script)
+	e.InvocationStack = append(e.InvocationStack, context)
+	return context
+}
+
+// Run loads script and executes it
+func (e *ExecutionEngine) Run(script []byte) VMState {
+	e.LoadScript(script)
+	return e.Execute()
+}
+
+// Execute runs the loaded scripts until HALT or FAULT
+func (e *ExecutionEngine) Execute() VMState {
+	e.State &^= BREAK
+	for e.State&(HALT|FAULT) == 0 {
+		e.StepInto()
+	}
+	return e.State
+}
+
+// StepInto executes the next instruction
+func (e *ExecutionEngine) StepInto() {
+	if e.State&(HALT|FAULT) != 0 {
+		return
+	}
+	context := e.CurrentContext()
+	if context == nil {
+		e.State |= HALT
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(vmError)
+			if !ok {
+				panic(r)
+			}
+			e.fault(err.error)
+		}
+	}()
+	op := context.nextInstruction()
+	if err := e.AddGas(e.getPrice(context, op)); err != nil {
+		e.fault(err)
+		return
+	}
+	context.InstructionPointer++
+	e.executeOp(op, context)
+	if e.stackSize() > MaxStackSize {
+		e.fault(fmt.Errorf("stack size exceeds %d", MaxStackSize))
+	}
+}
+
+// stackSize counts the items on the stacks of all the contexts
+func (e *ExecutionEngine) stackSize() int {
+	n := 0
+	for _, context := range e.InvocationStack {
+		n += context.EvaluationStack.Count() + context.AltStack.Count()
+	}
+	return n
+}
+
+func (e *ExecutionEngine) fault(err error) {
+	e.State |= FAULT
+	e.FaultReason = err
+}
+
+// vmError is raised inside executeOp and turned into FAULT by StepInto
+type vmError struct {
+	error
+}
+
+func throw(format string, a ...interface{}) {
+	panic(vmError{fmt.Errorf(format, a...)})
+}
+
+func check(err error) {
+	if err != nil {
+		panic(vmError{err})
+	}
+}
+
+func (e *ExecutionEngine) getPrice(context *ExecutionContext, op sc.OpCode) int64 {
+	switch {
+	case op <= sc.PUSH16, op == sc.NOP:
+		return 0
+	}
+	switch op {
+	case sc.APPCALL, sc.TAILCALL, sc.CALL_E, sc.CALL_ED, sc.CALL_ET, sc.CALL_EDT:
+		return 10
+	case sc.SYSCALL:
+		api, err := readVarBytes(context.Script, context.InstructionPointer+1)
+		if err != nil {
+			return 1
+		}
+		return e.Interop.GetPrice(api)
+	case sc.SHA1, sc.SHA256:
+		return 10
+	case sc.HASH160, sc.HASH256:
+		return 20
+	case sc.CHECKSIG, sc.VERIFY:
+		return 100
+	case sc.CHECKMULTISIG:
+		item, err := context.EvaluationStack.Peek(0)
+		if err != nil {
+			return 1
+		}
+		var n int64
+		if a, ok := item.(*ArrayItem); ok {
+			n = int64(len(a.Value))
+		} else if a, ok := item.(*StructItem); ok {
+			n = int64(len(a.Value))
+		} else if i, err := item.GetBigInteger(); err == nil && i.IsInt64() {
+			n = i.Int64()
+		}
+		if n < 1 {
+			return 1
+		}
+		return 100 * n
+	default:
+		return 1
+	}
+}
+
+// PopBigInteger pops an item as an integer, it is used by the interop services
+func (e *ExecutionEngine) PopBigInteger() (*big.Int, error) {
+	item, err := e.EvaluationStack().Pop()
+	if err != nil {
+		return nil, err
+	}
+	return item.GetBigInteger()
+}
+
+// PopByteArray pops an item as a byte array
+func (e *ExecutionEngine) PopByteArray() ([]byte, error) {
+	item, err := e.EvaluationStack().Pop()
+	if err != nil {
+		return nil, err
+	}
+	return item.GetByteArray()
+}
+
+// PopBoolean pops an item as a boolean
+func (e *ExecutionEngine) PopBoolean() (bool, error) {
+	item, err := e.EvaluationStack().Pop()
+	if err != nil {
+		return false, err
+	}
+	return item.GetBoolean(), nil
+}
+
+// PopInterop pops the object wrapped by an InteropItem
+func (e *ExecutionEngine) PopInterop() (interface{}, error) {
+	item, err := e.EvaluationStack().Pop()
+	if err != nil {
+		return nil, err
+	}
+	i, ok := item.(*InteropItem)
+	if !ok {
+		return nil, fmt.Errorf("expected interop interface")
+	}
+	return i.Value, nil
+}
+
+func (e *ExecutionEngine) pop() StackItem {
+	item, err := e.EvaluationStack().Pop()
+	check(err)
+	return item
+}
+
+func (e *ExecutionEngine) peek(index int) StackItem {
+	item, err := e.EvaluationStack().Peek(index)
+	check(err)
+	return item
+}
+
+func (e *ExecutionEngine) popInt() *big.Int {
+	i, err := e.PopBigInteger()
+	check(err)
+	return i
+}
+
+// popIndex pops a non negative int
+func (e *ExecutionEngine) popIndex() int {
+	i := e.popInt()
+	if i.Sign() < 0 || !i.IsInt64() || i.Int64() > MaxItemSize {
+		throw("invalid index %s", i.String())
+	}
+	return int(i.Int64())
+}
+
+func (e *ExecutionEngine) popBytes() []byte {
+	b, err := e.PopByteArray()
+	check(err)
+	return b
+}
+
+func (e *ExecutionEngine) push(item StackItem) {
+	e.EvaluationStack().Push(item)
+}
+
+func (e *ExecutionEngine) pushInt(i *big.Int) {
+	if len(helper.BigIntToNeoBytes(i)) > MaxSizeForBigInteger {
+		throw("integer overflow")
+	}
+	e.push(NewIntegerItem(i))
+}
+
+func (e *ExecutionEngine) pushBool(b bool) {
+	e.push(NewBooleanItem(b))
+}
+
+func (e *ExecutionEngine) pushBytes(b []byte) {
+	if len(b) > MaxItemSize {
+		throw("item size exceeds %d", MaxItemSize)
+	}
+	e.push(NewByteArrayItem(b))
+}
+
+func (e *ExecutionEngine) popArray() []StackItem {
+	return arrayItems(e.pop())
+}
+
+// popKey pops a key of a map or an index of an array, which must be primitive
+func (e *ExecutionEngine) popKey() StackItem {
+	key := e.pop()
+	switch key.(type) {
+	case *ByteArrayItem, *IntegerItem, *BooleanItem:
+		return key
+	default:
+		throw("map key must be a primitive item")
+	}
+	return nil
+}
+
+func arrayItems(item StackItem) []StackItem {
+	switch a := item.(type) {
+	case *ArrayItem:
+		return a.Value
+	case *StructItem:
+		return a.Value
+	default:
+		throw("expected array")
+	}
+	return nil
+}
+
+// arrayIndex converts key to an index of an array of size n
+func arrayIndex(key StackItem, n int) int {
+	i, err := key.GetBigInteger()
+	check(err)
+	if i.Sign() < 0 || !i.IsInt64() || i.Int64() >= int64(n) {
+		throw("index %s out of range", i.String())
+	}
+	return int(i.Int64())
+}
+
+// readOperand reads n bytes after the instruction pointer
+func readOperand(context *ExecutionContext, n int) []byte {
+	ip := context.InstructionPointer
+	if n < 0 || ip+n > len(context.Script) {
+		throw("unexpected end of script at %d", ip)
+	}
+	context.InstructionPointer += n
+	return context.Script[ip : ip+n]
+}
+
+// readVarBytes reads a var bytes at offset without moving the instruction pointer
+func readVarBytes(script []byte, offset int) ([]byte, error) {
+	if offset >= len(script) {
+		return nil, fmt.Errorf("unexpected end of script")
+	}
+	n, size := int(script[offset]), 1
+	switch script[offset] {
+	case 0xfd:
+		if offset+3 > len(script) {
+			return nil, fmt.Errorf("unexpected end of script")
+		}
+		n, size = int(binary.LittleEndian.Uint16(script[offset+1:])), 3
+	case 0xfe, 0xff:
+		return nil, fmt.Errorf("var bytes too long")
+	}
+	if offset+size+n > len(script) {
+		return nil, fmt.Errorf("unexpected end of script")
+	}
+	return script[offset+size : offset+size+n], nil
+}
+
+func varIntSize(n int) int {
+	if n < 0xfd {
+		return 1
+	}
+	return 3
+}
+
+func (e *ExecutionEngine) jump(context *ExecutionContext, offset int) {
+	// the offset is relative to the opcode
+	target := context.InstructionPointer - 1 + offset
+	if target < 0 || target > len(context.Script) {
+		throw("jump out of range: %d", target)
+	}
+	context.InstructionPointer = target
+}
+
+func (e *ExecutionEngine) loadContext(context *ExecutionContext) {
+	if len(e.InvocationStack) >= MaxInvocationStackSize {
+		throw("invocation stack size exceeds %d", MaxInvocationStackSize)
+	}
+	e.InvocationStack = append(e.InvocationStack, context)
+}
+
+// removeCallingContext drops the caller of the current context for the tail calls
+func (e *ExecutionEngine) removeCallingContext() {
+	n := len(e.InvocationStack)
+	e.InvocationStack = append(e.InvocationStack[:n-2], e.InvocationStack[n-1])
+}
+
+// moveItems moves the top count items of from onto to, -1 moves all of them
+func moveItems(from *RandomAccessStack, to *RandomAccessStack, count int) {
+	if count == -1 {
+		count = from.Count()
+	}
+	check(from.CopyTo(to, count))
+	for i := 0; i < count; i++ {
+		_, _ = from.Pop()
+	}
+}
+
+// getScript returns the script of a called contract
+func (e *ExecutionEngine) getScript(scriptHash helper.UInt160) []byte {
+	if e.Scripts == nil {
+		throw("no script table to call %s", scriptHash.String())
+	}
+	script, err := e.Scripts.GetScript(scriptHash)
+	check(err)
+	return script
+}
+
+func (e *ExecutionEngine) executeOp(op sc.OpCode, context *ExecutionContext) {
+	if op >= sc.PUSHBYTES1 && op <= sc.PUSHBYTES75 {
+		e.pushBytes(readOperand(context, int(op)))
+		return
+	}
+	switch op {
+	// Constants
+	case sc.PUSH0:
+		e.pushBytes([]byte{})
+	case sc.PUSHDATA1:
+		n := readOperand(context, 1)[0]
+		e.pushBytes(readOperand(context, int(n)))
+	case sc.PUSHDATA2:
+		n := binary.LittleEndian.Uint16(readOperand(context, 2))
+		e.pushBytes(readOperand(context, int(n)))
+	case sc.PUSHDATA4:
+		n := binary.LittleEndian.Uint32(readOperand(context, 4))
+		if n > MaxItemSize {
+			throw("item size exceeds %d", MaxItemSize)
+		}
+		e.pushBytes(readOperand(context, int(n)))
+	case sc.PUSHM1, sc.PUSH1, sc.PUSH2, sc.PUSH3, sc.PUSH4, sc.PUSH5, sc.PUSH6, sc.PUSH7, sc.PUSH8,
+		sc.PUSH9, sc.PUSH10, sc.PUSH11, sc.PUSH12, sc.PUSH13, sc.PUSH14, sc.PUSH15, sc.PUSH16:
+		e.pushInt(big.NewInt(int64(op) - int64(sc.PUSH1) + 1))
+
+	// Flow control
+	case sc.NOP:
+	case sc.JMP, sc.JMPIF, sc.JMPIFNOT:
+		offset := int(int16(binary.LittleEndian.Uint16(readOperand(context, 2))))
+		jump := true
+		if op != sc.JMP {
+			jump = e.pop().GetBoolean()
+			if op == sc.JMPIFNOT {
+				jump = !jump
+			}
+		}
+		if jump {
+			context.InstructionPointer -= 2
+			e.jump(context, offset)
+		}
+	case sc.CALL:
+		// the called context takes all the items and returns all of them
+		offset := int(int16(binary.LittleEndian.Uint16(readOperand(context, 2))))
+		call := context.clone(-1)
+		call.InstructionPointer -= 2
+		e.jump(call, offset)
+		e.loadContext(call)
+		moveItems(context.EvaluationStack, call.EvaluationStack, -1)
+	case sc.RET:
+		rvcount := context.RVCount
+		if rvcount == -1 {
+			rvcount = context.EvaluationStack.Count()
+		}
+		if context.EvaluationStack.Count() < rvcount {
+			throw("%d items to return but %d on the stack", rvcount, context.EvaluationStack.Count())
+		}
+		e.InvocationStack = e.InvocationStack[:len(e.InvocationStack)-1]
+		caller := e.CurrentContext()
+		if caller == nil {
+			check(context.EvaluationStack.CopyTo(e.ResultStack, rvcount))
+			e.State |= HALT
+			break
+		}
+		check(context.EvaluationStack.CopyTo(caller.EvaluationStack, rvcount))
+		if context.RVCount == -1 {
+			check(context.AltStack.CopyTo(caller.AltStack, context.AltStack.Count()))
+		}
+	case sc.APPCALL, sc.TAILCALL:
+		hash := readOperand(context, 20)
+		scriptHash, _ := helper.UInt160FromBytes(hash)
+		if scriptHash == (helper.UInt160{}) {
+			// dynamic invoke
+			b := e.popBytes()
+			var err error
+			scriptHash, err = helper.UInt160FromBytes(b)
+			check(err)
+		}
+		call := NewExecutionContext(e.getScript(scriptHash))
+		e.loadContext(call)
+		moveItems(context.EvaluationStack, call.EvaluationStack, -1)
+		if op == sc.TAILCALL {
+			e.removeCallingContext()
+		}
+	case sc.CALL_I:
+		// rvcount, pcount and the offset from the opcode + 2
+		operand := readOperand(context, 4)
+		rvcount, pcount := int(operand[0]), int(operand[1])
+		if context.EvaluationStack.Count() < pcount {
+			throw("%d parameters but %d items on the stack", pcount, context.EvaluationStack.Count())
+		}
+		call := context.clone(rvcount)
+		call.InstructionPointer -= 4
+		e.jump(call, int(int16(binary.LittleEndian.Uint16(operand[2:])))+2)
+		e.loadContext(call)
+		moveItems(context.EvaluationStack, call.EvaluationStack, pcount)
+	case sc.CALL_E, sc.CALL_ED, sc.CALL_ET, sc.CALL_EDT:
+		// rvcount, pcount and the script hash unless it is popped from the stack for CALL_ED and CALL_EDT
+		operand := readOperand(context, 2)
+		rvcount, pcount := int(operand[0]), int(operand[1])
+		tail := op == sc.CALL_ET || op == sc.CALL_EDT
+		if tail && context.RVCount != rvcount {
+			throw("tail call returns %d items but the context returns %d", rvcount, context.RVCount)
+		}
+		var scriptHash helper.UInt160
+		var err error
+		if op == sc.CALL_ED || op == sc.CALL_EDT {
+			scriptHash, err = helper.UInt160FromBytes(e.popBytes())
+		} else {
+			scriptHash, err = helper.UInt160FromBytes(readOperand(context, 20))
+		}
+		check(err)
+		if context.EvaluationStack.Count() < pcount {
+			throw("%d parameters but %d items on the stack", pcount, context.EvaluationStack.Count())
+		}
+		call := newExecutionContext(e.getScript(scriptHash), rvcount)
+		e.loadContext(call)
+		moveItems(context.EvaluationStack, call.EvaluationStack, pcount)
+		if tail {
+			e.removeCallingContext()
+		}
+	case sc.SYSCALL:
+		api, err := readVarBytes(context.Script, context.InstructionPointer)
+		check(err)
+		readOperand(context, varIntSize(len(api))+len(api))
+		check(e.Interop.Invoke(e, api))
+
+	// Stack
+	case sc.DUPFROMALTSTACK:
+		item, err := context.AltStack.Peek(0)
+		check(err)
+		e.push(item)
+	case sc.TOALTSTACK:
+		context.AltStack.Push(e.pop())
+	case sc.FROMALTSTACK:
+		item, err := context.AltStack.Pop()
+		check(err)
+		e.push(item)
+	case sc.XDROP:
+		n := e.popIndex()
+		_, err := e.EvaluationStack().Remove(n)
+		check(err)
+	case sc.XSWAP:
+		n := e.popIndex()
+		if n == 0 {
+			break
+		}
+		a, b := e.peek(0), e.peek(n)
+		items := e.EvaluationStack().items
+		items[len(items)-1], items[len(items)-1-n] = b, a
+	case sc.XTUCK:
+		n := e.popIndex()
+		if n == 0 {
+			throw("invalid XTUCK index 0")
+		}
+		check(e.EvaluationStack().Insert(n, e.peek(0)))
+	case sc.DEPTH:
+		e.pushInt(big.NewInt(int64(e.EvaluationStack().Count())))
+	case sc.DROP:
+		e.pop()
+	case sc.DUP:
+		e.push(e.peek(0))
+	case sc.NIP:
+		_, err := e.EvaluationStack().Remove(1)
+		check(err)
+	case sc.OVER:
+		e.push(e.peek(1))
+	case sc.PICK:
+		n := e.popIndex()
+		e.push(e.peek(n))
+	case sc.ROLL:
+		n := e.popIndex()
+		if n == 0 {
+			break
+		}
+		item, err := e.EvaluationStack().Remove(n)
+		check(err)
+		e.push(item)
+	case sc.ROT:
+		item, err := e.EvaluationStack().Remove(2)
+		check(err)
+		e.push(item)
+	case sc.SWAP:
+		item, err := e.EvaluationStack().Remove(1)
+		check(err)
+		e.push(item)
+	case sc.TUCK:
+		check(e.EvaluationStack().Insert(2, e.peek(0)))
+
+	// Splice
+	case sc.CAT:
+		x2 := e.popBytes()
+		x1 := e.popBytes()
+		e.pushBytes(append(append(make([]byte, 0, len(x1)+len(x2)), x1...), x2...))
+	case sc.SUBSTR:
+		count := e.popIndex()
+		index := e.popIndex()
+		x := e.popBytes()
+		if index > len(x) {
+			index = len(x)
+		}
+		if index+count > len(x) {
+			count = len(x) - index
+		}
+		e.pushBytes(x[index : index+count])
+	case sc.LEFT:
+		count := e.popIndex()
+		x := e.popBytes()
+		if count < len(x) {
+			x = x[:count]
+		}
+		e.pushBytes(x)
+	case sc.RIGHT:
+		count := e.popIndex()
+		x := e.popBytes()
+		if count > len(x) {
+			throw("RIGHT count %d exceeds the size %d", count, len(x))
+		}
+		e.pushBytes(x[len(x)-count:])
+	case sc.SIZE:
+		e.pushInt(big.NewInt(int64(len(e.popBytes()))))
+
+	// Bitwise logic
+	case sc.INVERT:
+		e.pushInt(new(big.Int).Not(e.popInt()))
+	case sc.AND, sc.OR, sc.XOR:
+		x2 := e.popInt()
+		x1 := e.popInt()
+		r := new(big.Int)
+		switch op {
+		case sc.AND:
+			r.And(x1, x2)
+		case sc.OR:
+			r.Or(x1, x2)
+		default:
+			r.Xor(x1, x2)
+		}
+		e.pushInt(r)
+	case sc.EQUAL:
+		x2 := e.pop()
+		x1 := e.pop()
+		e.pushBool(x1.Equals(x2))
+
+	// Arithmetic
+	case sc.INC:
+		e.pushInt(new(big.Int).Add(e.popInt(), big.NewInt(1)))
+	case sc.DEC:
+		e.pushInt(new(big.Int).Sub(e.popInt(), big.NewInt(1)))
+	case sc.SIGN:
+		e.pushInt(big.NewInt(int64(e.popInt().Sign())))
+	case sc.NEGATE:
+		e.pushInt(new(big.Int).Neg(e.popInt()))
+	case sc.ABS:
+		e.pushInt(new(big.Int).Abs(e.popInt()))
+	case sc.NOT:
+		e.pushBool(!e.pop().GetBoolean())
+	case sc.NZ:
+		e.pushBool(e.popInt().Sign() != 0)
+	case sc.ADD, sc.SUB, sc.MUL, sc.DIV, sc.MOD, sc.MIN, sc.MAX:
+		x2 := e.popInt()
+		x1 := e.popInt()
+		r := new(big.Int)
+		switch op {
+		case sc.ADD:
+			r.Add(x1, x2)
+		case sc.SUB:
+			r.Sub(x1, x2)
+		case sc.MUL:
+			r.Mul(x1, x2)
+		case sc.DIV, sc.MOD:
+			if x2.Sign() == 0 {
+				throw("division by zero")
+			}
+			// BigInteger truncates toward zero
+			if op == sc.DIV {
+				r.Quo(x1, x2)
+			} else {
+				r.Rem(x1, x2)
+			}
+		case sc.MIN:
+			r.Set(x1)
+			if x2.Cmp(x1) < 0 {
+				r.Set(x2)
+			}
+		default:
+			r.Set(x1)
+			if x2.Cmp(x1) > 0 {
+				r.Set(x2)
+			}
+		}
+		e.pushInt(r)
+	case sc.SHL, sc.SHR:
+		shift := e.popInt()
+		if !shift.IsInt64() || shift.Int64() > MaxShlShr || shift.Int64() < MinShlShr {
+			throw("invalid shift %s", shift.String())
+		}
+		s := shift.Int64()
+		if s == 0 {
+			break
+		}
+		if op == sc.SHR {
+			s = -s
+		}
+		x := e.popInt()
+		if s > 0 {
+			e.pushInt(new(big.Int).Lsh(x, uint(s)))
+		} else {
+			e.pushInt(new(big.Int).Rsh(x, uint(-s)))
+		}
+	case sc.BOOLAND, sc.BOOLOR:
+		x2 := e.pop().GetBoolean()
+		x1 := e.pop().GetBoolean()
+		if op == sc.BOOLAND {
+			e.pushBool(x1 && x2)
+		} else {
+			e.pushBool(x1 || x2)
+		}
+	case sc.NUMEQUAL, sc.NUMNOTEQUAL, sc.LT, sc.GT, sc.LTE, sc.GTE:
+		x2 := e.popInt()
+		x1 := e.popInt()
+		c := x1.Cmp(x2)
+		switch op {
+		case sc.NUMEQUAL:
+			e.pushBool(c == 0)
+		case sc.NUMNOTEQUAL:
+			e.pushBool(c != 0)
+		case sc.LT:
+			e.pushBool(c < 0)
+		case sc.GT:
+			e.pushBool(c > 0)
+		case sc.LTE:
+			e.pushBool(c <= 0)
+		default:
+			e.pushBool(c >= 0)
+		}
+	case sc.WITHIN:
+		b := e.popInt()
+		a := e.popInt()
+		x := e.popInt()
+		e.pushBool(a.Cmp(x) <= 0 && x.Cmp(b) < 0)
+
+	// Crypto
+	case sc.SHA1:
+		h := sha1.Sum(e.popBytes())
+		e.pushBytes(h[:])
+	case sc.SHA256:
+		h := sha256.Sum256(e.popBytes())
+		e.pushBytes(h[:])
+	case sc.HASH160:
+		e.pushBytes(crypto.Hash160(e.popBytes()))
+	case sc.HASH256:
+		e.pushBytes(crypto.Hash256(e.popBytes()))
+	case sc.CHECKSIG:
+		pubKey := e.popBytes()
+		signature := e.popBytes()
+		e.pushBool(verifySignature(e.Message, signature, pubKey))
+	case sc.VERIFY:
+		pubKey := e.popBytes()
+		signature := e.popBytes()
+		message := e.popBytes()
+		e.pushBool(verifySignature(message, signature, pubKey))
+	case sc.CHECKMULTISIG:
+		pubKeys := e.popByteArrays()
+		signatures := e.popByteArrays()
+		if len(signatures) > len(pubKeys) {
+			throw("more signatures than public keys")
+		}
+		// the signatures are in the order of the public keys
+		ok := true
+		for i, j := 0, 0; ok && i < len(signatures) && j < len(pubKeys); {
+			if verifySignature(e.Message, signatures[i], pubKeys[j]) {
+				i++
+			}
+			j++
+			if len(signatures)-i > len(pubKeys)-j {
+				ok = false
+			}
+		}
+		e.pushBool(ok)
+
+	// Array
+	case sc.ARRAYSIZE:
+		switch item := e.pop().(type) {
+		case *ArrayItem:
+			e.pushInt(big.NewInt(int64(len(item.Value))))
+		case *StructItem:
+			e.pushInt(big.NewInt(int64(len(item.Value))))
+		case *MapItem:
+			e.pushInt(big.NewInt(int64(item.Count())))
+		default:
+			b, err := item.GetByteArray()
+			check(err)
+			e.pushInt(big.NewInt(int64(len(b))))
+		}
+	case sc.PACK:
+		size := e.popIndex()
+		if size > MaxArraySize || size > e.EvaluationStack().Count() {
+			throw("invalid PACK size %d", size)
+		}
+		items := make([]StackItem, size)
+		for i := range items {
+			items[i] = e.pop()
+		}
+		e.push(NewArrayItem(items))
+	case sc.UNPACK:
+		var items []StackItem
+		switch a := e.pop().(type) {
+		case *ArrayItem:
+			items = a.Value
+		case *StructItem:
+			items = a.Value
+		default:
+			throw("expected array")
+		}
+		for i := len(items) - 1; i >= 0; i-- {
+			e.push(items[i])
+		}
+		e.pushInt(big.NewInt(int64(len(items))))
+	case sc.PICKITEM:
+		key := e.popKey()
+		switch a := e.pop().(type) {
+		case *MapItem:
+			value, ok := a.Get(key)
+			if !ok {
+				throw("key not found in map")
+			}
+			e.push(value)
+		case *ArrayItem:
+			e.push(a.Value[arrayIndex(key, len(a.Value))])
+		case *StructItem:
+			e.push(a.Value[arrayIndex(key, len(a.Value))])
+		default:
+			throw("expected array or map")
+		}
+	case sc.SETITEM:
+		value := e.pop()
+		if s, ok := value.(*StructItem); ok {
+			value = s.Clone()
+		}
+		key := e.popKey()
+		switch a := e.pop().(type) {
+		case *MapItem:
+			if _, ok := a.Get(key); !ok && a.Count() >= MaxArraySize {
+				throw("map size exceeds %d", MaxArraySize)
+			}
+			a.Set(key, value)
+		case *ArrayItem:
+			a.Value[arrayIndex(key, len(a.Value))] = value
+		case *StructItem:
+			a.Value[arrayIndex(key, len(a.Value))] = value
+		default:
+			throw("expected array or map")
+		}
+	case sc.NEWARRAY, sc.NEWSTRUCT:
+		var items []StackItem
+		switch item := e.peek(0).(type) {
+		case *ArrayItem:
+			e.pop()
+			items = item.Value
+		case *StructItem:
+			e.pop()
+			items = item.Value
+		default:
+			count := e.popIndex()
+			if count > MaxArraySize {
+				throw("array size exceeds %d", MaxArraySize)
+			}
+			items = make([]StackItem, count)
+			for i := range items {
+				items[i] = NewBooleanItem(false)
+			}
+		}
+		if op == sc.NEWARRAY {
+			e.push(NewArrayItem(items))
+		} else {
+			e.push(NewStructItem(items))
+		}
+	case sc.NEWMAP:
+		e.push(NewMapItem())
+	case sc.APPEND:
+		item := e.pop()
+		if s, ok := item.(*StructItem); ok {
+			item = s.Clone()
+		}
+		switch a := e.pop().(type) {
+		case *ArrayItem:
+			if len(a.Value) >= MaxArraySize {
+				throw("array size exceeds %d", MaxArraySize)
+			}
+			a.Value = append(a.Value, item)
+		case *StructItem:
+			if len(a.Value) >= MaxArraySize {
+				throw("array size exceeds %d", MaxArraySize)
+			}
+			a.Value = append(a.Value, item)
+		default:
+			throw("expected array")
+		}
+	case sc.REVERSE:
+		items := e.popArray()
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	case sc.REMOVE:
+		key := e.popKey()
+		switch a := e.pop().(type) {
+		case *MapItem:
+			a.Remove(key)
+		case *ArrayItem:
+			index := arrayIndex(key, len(a.Value))
+			a.Value = append(a.Value[:index], a.Value[index+1:]...)
+		case *StructItem:
+			index := arrayIndex(key, len(a.Value))
+			a.Value = append(a.Value[:index], a.Value[index+1:]...)
+		default:
+			throw("expected array or map")
+		}
+	case sc.HASKEY:
+		key := e.popKey()
+		switch a := e.pop().(type) {
+		case *MapItem:
+			_, ok := a.Get(key)
+			e.pushBool(ok)
+		case *ArrayItem, *StructItem:
+			i, err := key.GetBigInteger()
+			check(err)
+			if i.Sign() < 0 {
+				throw("invalid index %s", i.String())
+			}
+			e.pushBool(i.Cmp(big.NewInt(int64(len(arrayItems(a))))) < 0)
+		default:
+			throw("expected array or map")
+		}
+	case sc.KEYS:
+		m, ok := e.pop().(*MapItem)
+		if !ok {
+			throw("expected map")
+		}
+		e.push(NewArrayItem(append([]StackItem{}, m.Keys...)))
+	case sc.VALUES:
+		var values []StackItem
+		switch a := e.pop().(type) {
+		case *MapItem:
+			values = a.Values
+		case *ArrayItem, *StructItem:
+			values = arrayItems(a)
+		default:
+			throw("expected array or map")
+		}
+		items := make([]StackItem, len(values))
+		for i, item := range values {
+			if s, ok := item.(*StructItem); ok {
+				item = s.Clone()
+			}
+			items[i] = item
+		}
+		e.push(NewArrayItem(items))
+
+	// Exceptions
+	case sc.THROW:
+		throw("THROW")
+	case sc.THROWIFNOT:
+		if !e.pop().GetBoolean() {
+			throw("THROWIFNOT")
+		}
+	default:
+		throw("invalid opcode 0x%02x", byte(op))
+	}
+}
+
+// popByteArrays pops an array of byte arrays, or a count followed by the byte arrays, there must be at least one
+func (e *ExecutionEngine) popByteArrays() [][]byte {
+	var items []StackItem
+	switch a := e.peek(0).(type) {
+	case *ArrayItem:
+		e.pop()
+		items = a.Value
+	case *StructItem:
+		e.pop()
+		items = a.Value
+	default:
+		n := e.popIndex()
+		if n < 1 || n > e.EvaluationStack().Count() {
+			throw("invalid count %d", n)
+		}
+		items = make([]StackItem, n)
+		for i := range items {
+			items[i] = e.pop()
+		}
+	}
+	if len(items) == 0 {
+		throw("empty array")
+	}
+	result := make([][]byte, len(items))
+	for i, item := range items {
+		b, err := item.GetByteArray()
+		check(err)
+		result[i] = b
+	}
+	return result
+}
+
+func verifySignature(message []byte, signature []byte, pubKey []byte) bool {
+	if message == nil || len(signature) != 64 {
+		return false
+	}
+	p, err := keys.NewPublicKey(pubKey)
+	if err != nil {
+		return false
+	}
+	return keys.VerifySignature(message, signature, p)
+}
+
+// GetInvokeResult returns the result in the format of the invokescript rpc method
+func (e *ExecutionEngine) GetInvokeResult() models.InvokeResult {
+	items := e.ResultStack.Items()
+	stack := make([]sc.ContractParameter, len(items))
+	for i, item := range items {
+		stack[i] = ToContractParameter(item)
+	}
+	return models.InvokeResult{
+		Script:      helper.BytesToHex(e.entryScript),
+		State:       e.State.String(),
+		GasConsumed: e.GasConsumed().String(),
+		Stack:       stack,
+	}
+}
+
+// ToContractParameter converts a stack item the same as the neo node, a Struct is converted to an Array
+func ToContractParameter(item StackItem) sc.ContractParameter {
+	switch v := item.(type) {
+	case *ByteArrayItem:
+		return sc.ContractParameter{Type: sc.ByteArray, Value: v.Value}
+	case *IntegerItem:
+		return sc.ContractParameter{Type: sc.Integer, Value: *v.Value}
+	case *BooleanItem:
+		return sc.ContractParameter{Type: sc.Boolean, Value: v.Value}
+	case *ArrayItem:
+		return sc.ContractParameter{Type: sc.Array, Value: toContractParameters(v.Value)}
+	case *StructItem:
+		return sc.ContractParameter{Type: sc.Array, Value: toContractParameters(v.Value)}
+	case *MapItem:
+		pairs := make([]sc.ContractParameterPair, v.Count())
+		for i := range pairs {
+			pairs[i] = sc.ContractParameterPair{
+				Key:   ToContractParameter(v.Keys[i]),
+				Value: ToContractParameter(v.Values[i]),
+			}
+		}
+		return sc.ContractParameter{Type: sc.Map, Value: pairs}
+	default:
+		return sc.ContractParameter{Type: sc.InteropInterface}
+	}
+}
+
+func toContractParameters(items []StackItem) []sc.ContractParameter {
+	result := make([]sc.ContractParameter, len(items))
+	for i, item := range items {
+		result[i] = ToContractParameter(item)
+	}
+	return result
+}
@@ -0,0 +1,436 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutionEngine_Arithmetic(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushInt(3)
+	_ = sb.EmitPushInt(4)
+	_ = sb.Emit(sc.ADD)
+	_ = sb.EmitPushInt(-2)
+	_ = sb.Emit(sc.MUL)
+	_ = sb.EmitPushInt(-14)
+	_ = sb.EmitPushInt(4)
+	_ = sb.Emit(sc.DIV)
+	e := NewExecutionEngine()
+	state := e.Run(sb.ToArray())
+	assert.Equal(t, HALT, state)
+	assert.Equal(t, 2, e.ResultStack.Count())
+	top, _ := e.PopBigInteger()
+	assert.Equal(t, int64(-3), top.Int64())
+	next, _ := e.PopBigInteger()
+	assert.Equal(t, int64(-14), next.Int64())
+	// ADD, MUL, DIV and the RET at the end of the script
+	assert.Equal(t, int64(4*GasRatio), e.GasConsumed().Value)
+}
+
+func TestExecutionEngine_DivideByZero(t *testing.T) {
+	e := NewExecutionEngine()
+	state := e.Run([]byte{byte(sc.PUSH1), byte(sc.PUSH0), byte(sc.DIV)})
+	assert.Equal(t, FAULT, state)
+	assert.NotNil(t, e.FaultReason)
+}
+
+func TestExecutionEngine_Jump(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.Emit(sc.PUSH0)
+	_ = sb.EmitJump(sc.JMPIFNOT, 4) // skip PUSH5
+	_ = sb.Emit(sc.PUSH5)
+	_ = sb.Emit(sc.PUSH6)
+	e := NewExecutionEngine()
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, 1, e.ResultStack.Count())
+	i, _ := e.PopBigInteger()
+	assert.Equal(t, int64(6), i.Int64())
+}
+
+func TestExecutionEngine_Call(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitJump(sc.CALL, 4)
+	_ = sb.Emit(sc.RET)
+	_ = sb.Emit(sc.PUSH7)
+	_ = sb.Emit(sc.RET)
+	e := NewExecutionEngine()
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, 1, e.ResultStack.Count())
+	i, _ := e.PopBigInteger()
+	assert.Equal(t, int64(7), i.Int64())
+}
+
+func TestExecutionEngine_CallI(t *testing.T) {
+	// 3 - 1 by a function taking 2 parameters and returning 1 item
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushInt(5)
+	_ = sb.EmitPushInt(3)
+	_ = sb.EmitPushInt(1)
+	_ = sb.Emit(sc.CALL_I, 1, 2, 4, 0) // to the SUB after RET, the offset is from the opcode + 2
+	_ = sb.Emit(sc.RET)
+	_ = sb.Emit(sc.SUB)
+	_ = sb.Emit(sc.RET)
+	e := NewExecutionEngine()
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, []sc.ContractParameter{
+		{Type: sc.Integer, Value: *big.NewInt(5)},
+		{Type: sc.Integer, Value: *big.NewInt(2)},
+	}, e.GetInvokeResult().Stack)
+
+	// the function returns fewer items than rvcount
+	e = NewExecutionEngine()
+	assert.Equal(t, FAULT, e.Run([]byte{byte(sc.CALL_I), 1, 0, 4, 0, byte(sc.NOP), byte(sc.RET)}))
+	// not enough parameters
+	e = NewExecutionEngine()
+	assert.Equal(t, FAULT, e.Run([]byte{byte(sc.PUSH1), byte(sc.CALL_I), 1, 2, 5, 0, byte(sc.RET)}))
+}
+
+func TestExecutionEngine_CallE(t *testing.T) {
+	// the contract adds its 2 parameters, the items below them are not seen
+	contract := []byte{byte(sc.DEPTH), byte(sc.ADD), byte(sc.ADD), byte(sc.RET)}
+	scripts := NewScriptTable()
+	contractHash := scripts.Add(contract)
+
+	callE := append([]byte{byte(sc.PUSH7), byte(sc.PUSH1), byte(sc.PUSH2), byte(sc.CALL_E), 1, 2}, contractHash.Bytes()...)
+	e := NewExecutionEngine()
+	e.Scripts = scripts
+	assert.Equal(t, HALT, e.Run(callE))
+	assert.Equal(t, []sc.ContractParameter{
+		{Type: sc.Integer, Value: *big.NewInt(7)},
+		{Type: sc.Integer, Value: *big.NewInt(5)},
+	}, e.GetInvokeResult().Stack)
+	// CALL_E 10 and 5 of the contract
+	assert.Equal(t, "0.015", e.GetInvokeResult().GasConsumed)
+
+	// the script hash of CALL_ED is popped from the stack
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushInt(1)
+	_ = sb.EmitPushInt(2)
+	_ = sb.EmitPushBytes(contractHash.Bytes())
+	_ = sb.Emit(sc.CALL_ED, 1, 2)
+	e = NewExecutionEngine()
+	e.Scripts = scripts
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, []sc.ContractParameter{{Type: sc.Integer, Value: *big.NewInt(5)}}, e.GetInvokeResult().Stack)
+
+	e = NewExecutionEngine()
+	assert.Equal(t, FAULT, e.Run(append([]byte{byte(sc.CALL_E), 0, 0}, helper.UInt160{1}.Bytes()...)))
+}
+
+func TestExecutionEngine_CallET(t *testing.T) {
+	contract := []byte{byte(sc.DEPTH), byte(sc.ADD), byte(sc.ADD), byte(sc.RET)}
+	scripts := NewScriptTable()
+	contractHash := scripts.Add(contract)
+
+	// a function returning 1 item tail calls the contract, whose result goes to the caller of the function
+	function := append([]byte{byte(sc.PUSH2), byte(sc.CALL_ET), 1, 2}, contractHash.Bytes()...)
+	script := append([]byte{byte(sc.PUSH1), byte(sc.CALL_I), 1, 1, 4, 0, byte(sc.RET)}, function...)
+	e := NewExecutionEngine()
+	e.Scripts = scripts
+	assert.Equal(t, HALT, e.Run(script))
+	assert.Equal(t, []sc.ContractParameter{{Type: sc.Integer, Value: *big.NewInt(5)}}, e.GetInvokeResult().Stack)
+
+	// the same with the script hash on the stack
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushInt(2)
+	_ = sb.EmitPushBytes(contractHash.Bytes())
+	_ = sb.Emit(sc.CALL_EDT, 1, 2)
+	function = sb.ToArray()
+	script = append([]byte{byte(sc.PUSH1), byte(sc.CALL_I), 1, 1, 4, 0, byte(sc.RET)}, function...)
+	e = NewExecutionEngine()
+	e.Scripts = scripts
+	assert.Equal(t, HALT, e.Run(script))
+	assert.Equal(t, []sc.ContractParameter{{Type: sc.Integer, Value: *big.NewInt(5)}}, e.GetInvokeResult().Stack)
+
+	// the rvcount of the tail call differs from the one of the context
+	e = NewExecutionEngine()
+	e.Scripts = scripts
+	assert.Equal(t, FAULT, e.Run(append([]byte{byte(sc.PUSH1), byte(sc.PUSH2), byte(sc.CALL_ET), 1, 2}, contractHash.Bytes()...)))
+}
+
+func TestExecutionEngine_Array(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushInt(1)
+	_ = sb.EmitPushInt(2)
+	_ = sb.EmitPushInt(2)
+	_ = sb.Emit(sc.PACK)
+	_ = sb.Emit(sc.DUP)
+	_ = sb.Emit(sc.REVERSE)
+	_ = sb.Emit(sc.DUP)
+	_ = sb.EmitPushString("a")
+	_ = sb.Emit(sc.APPEND)
+	_ = sb.Emit(sc.DUP)
+	_ = sb.Emit(sc.ARRAYSIZE)
+	e := NewExecutionEngine()
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+
+	result := e.GetInvokeResult()
+	assert.Equal(t, "HALT", result.State)
+	assert.Equal(t, 2, len(result.Stack))
+	assert.Equal(t, sc.ContractParameter{Type: sc.Array, Value: []sc.ContractParameter{
+		{Type: sc.Integer, Value: *big.NewInt(1)},
+		{Type: sc.Integer, Value: *big.NewInt(2)},
+		{Type: sc.ByteArray, Value: []byte("a")},
+	}}, result.Stack[0])
+	assert.Equal(t, sc.ContractParameter{Type: sc.Integer, Value: *big.NewInt(3)}, result.Stack[1])
+}
+
+func TestExecutionEngine_Struct(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushInt(0)
+	_ = sb.Emit(sc.NEWARRAY)
+	_ = sb.Emit(sc.DUP)
+	_ = sb.EmitPushInt(1)
+	_ = sb.Emit(sc.NEWSTRUCT)
+	_ = sb.Emit(sc.DUP)
+	_ = sb.Emit(sc.TOALTSTACK)
+	_ = sb.Emit(sc.APPEND)
+	// the appended struct is a copy
+	_ = sb.Emit(sc.FROMALTSTACK)
+	_ = sb.EmitPushInt(0)
+	_ = sb.EmitPushInt(5)
+	_ = sb.Emit(sc.SETITEM)
+	e := NewExecutionEngine()
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+
+	result := e.GetInvokeResult()
+	assert.Equal(t, 1, len(result.Stack))
+	assert.Equal(t, sc.ContractParameter{Type: sc.Array, Value: []sc.ContractParameter{
+		{Type: sc.Array, Value: []sc.ContractParameter{{Type: sc.Boolean, Value: false}}},
+	}}, result.Stack[0])
+}
+
+func TestExecutionEngine_Map(t *testing.T) {
+	cp, _ := sc.NewContractParameter(map[string]interface{}{"a": 1, "b": []int{2, 3}})
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushParameter(cp)
+	_ = sb.Emit(sc.DUP)
+	_ = sb.EmitPushString("b")
+	_ = sb.Emit(sc.PICKITEM)
+	_ = sb.Emit(sc.ARRAYSIZE)
+	_ = sb.Emit(sc.OVER)
+	_ = sb.EmitPushString("c")
+	_ = sb.Emit(sc.HASKEY)
+	e := NewExecutionEngine()
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	hasKey, _ := e.PopBoolean()
+	assert.False(t, hasKey)
+	size, _ := e.PopBigInteger()
+	assert.Equal(t, int64(2), size.Int64())
+	top, _ := e.ResultStack.Peek(0)
+	m, ok := top.(*MapItem)
+	assert.True(t, ok)
+	assert.Equal(t, 2, m.Count())
+	assert.Equal(t, sc.Map, e.GetInvokeResult().Stack[0].Type)
+
+	sb = sc.NewScriptBuilder()
+	_ = sb.Emit(sc.NEWMAP)
+	_ = sb.EmitPushInt(1)
+	_ = sb.Emit(sc.PICKITEM)
+	assert.Equal(t, FAULT, NewExecutionEngine().Run(sb.ToArray()))
+}
+
+func TestExecutionEngine_AppCall(t *testing.T) {
+	// Storage.Put(ctx, "k", "v"); return Storage.Get(ctx, "k")
+	contract := sc.NewScriptBuilder()
+	_ = contract.EmitPushString("v")
+	_ = contract.EmitPushString("k")
+	_ = contract.EmitVmSysCall("Neo.Storage.GetContext", false)
+	_ = contract.EmitVmSysCall("Neo.Storage.Put", false)
+	_ = contract.EmitPushString("k")
+	_ = contract.EmitVmSysCall("System.Storage.GetContext", true)
+	_ = contract.EmitVmSysCall("System.Storage.Get", true)
+	_ = contract.Emit(sc.RET)
+
+	e := NewExecutionEngine()
+	scripts := NewScriptTable()
+	contractHash := scripts.Add(contract.ToArray())
+	e.Scripts = scripts
+
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitAppCall(contractHash.Bytes(), false)
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Nil(t, e.FaultReason)
+
+	value, _ := e.Storage.Get(contractHash, []byte("k"))
+	assert.Equal(t, []byte("v"), value)
+	result := e.GetInvokeResult()
+	assert.Equal(t, helper.BytesToHex(sb.ToArray()), result.Script)
+	assert.Equal(t, []sc.ContractParameter{{Type: sc.ByteArray, Value: []byte("v")}}, result.Stack)
+	// APPCALL 10, Put 1000, Get 100, 2 GetContext, 2 RET
+	assert.Equal(t, "1.114", result.GasConsumed)
+}
+
+func TestExecutionEngine_AppCallNotFound(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitAppCall(make([]byte, 20), false)
+	e := NewExecutionEngine()
+	// the script hash of a dynamic invoke is popped from the stack
+	e.LoadScript(sb.ToArray()).EvaluationStack.Push(NewByteArrayItem(helper.UInt160{1}.Bytes()))
+	assert.Equal(t, FAULT, e.Execute())
+	assert.Equal(t, "FAULT", e.GetInvokeResult().State)
+}
+
+func TestExecutionEngine_ReadOnlyStorage(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushString("v")
+	_ = sb.EmitPushString("k")
+	_ = sb.EmitVmSysCall("System.Storage.GetReadOnlyContext", true)
+	_ = sb.EmitVmSysCall("System.Storage.Put", true)
+	e := NewExecutionEngine()
+	assert.Equal(t, FAULT, e.Run(sb.ToArray()))
+	assert.NotNil(t, e.FaultReason)
+}
+
+func TestExecutionEngine_GasLimit(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushString("v")
+	_ = sb.EmitPushString("k")
+	_ = sb.EmitVmSysCall("System.Storage.GetContext", true)
+	_ = sb.EmitVmSysCall("System.Storage.Put", true)
+	e := NewExecutionEngine()
+	e.GasLimit = helper.Fixed8FromFloat64(0.5)
+	assert.Equal(t, FAULT, e.Run(sb.ToArray()))
+	value, _ := e.Storage.Get(helper.UInt160{}, []byte("k"))
+	assert.Nil(t, value)
+}
+
+func TestExecutionEngine_Throw(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.Emit(sc.PUSH0)
+	_ = sb.Emit(sc.THROWIFNOT)
+	e := NewExecutionEngine()
+	assert.Equal(t, FAULT, e.Run(sb.ToArray()))
+	assert.Equal(t, "THROWIFNOT", e.FaultReason.Error())
+}
+
+func TestExecutionEngine_CheckSig(t *testing.T) {
+	pair, _ := keys.GenerateKeyPair()
+	message := []byte("message")
+	signature, _ := pair.Sign(message)
+
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushBytes(signature)
+	_ = sb.EmitPushBytes(pair.PublicKey.EncodeCompression())
+	_ = sb.Emit(sc.CHECKSIG)
+	_ = sb.EmitPushBytes(pair.PublicKey.EncodeCompression())
+	_ = sb.EmitVmSysCall("Neo.Runtime.CheckWitness", false)
+
+	e := NewExecutionEngine()
+	e.Message = message
+	e.Witnesses = []helper.UInt160{pair.PublicKey.ScriptHash()}
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, []sc.ContractParameter{
+		{Type: sc.Boolean, Value: true},
+		{Type: sc.Boolean, Value: true},
+	}, e.GetInvokeResult().Stack)
+
+	e = NewExecutionEngine()
+	e.Message = []byte("other")
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, []sc.ContractParameter{
+		{Type: sc.Boolean, Value: false},
+		{Type: sc.Boolean, Value: false},
+	}, e.GetInvokeResult().Stack)
+}
+
+func TestExecutionEngine_Verify(t *testing.T) {
+	pair, _ := keys.GenerateKeyPair()
+	message := []byte("message")
+	signature, _ := pair.Sign(message)
+
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushBytes(message)
+	_ = sb.EmitPushBytes(signature)
+	_ = sb.EmitPushBytes(pair.PublicKey.EncodeCompression())
+	_ = sb.Emit(sc.VERIFY)
+	e := NewExecutionEngine()
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, []sc.ContractParameter{{Type: sc.Boolean, Value: true}}, e.GetInvokeResult().Stack)
+}
+
+func TestExecutionEngine_CheckMultiSig(t *testing.T) {
+	pair1, _ := keys.GenerateKeyPair()
+	pair2, _ := keys.GenerateKeyPair()
+	message := []byte("message")
+	signature1, _ := pair1.Sign(message)
+	signature2, _ := pair2.Sign(message)
+
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushBytes(signature1)
+	_ = sb.EmitPushBytes(signature2)
+	_ = sb.EmitPushInt(2)
+	_ = sb.EmitPushBytes(pair1.PublicKey.EncodeCompression())
+	_ = sb.EmitPushBytes(pair2.PublicKey.EncodeCompression())
+	_ = sb.EmitPushInt(2)
+	_ = sb.Emit(sc.CHECKMULTISIG)
+	e := NewExecutionEngine()
+	e.Message = message
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, []sc.ContractParameter{{Type: sc.Boolean, Value: true}}, e.GetInvokeResult().Stack)
+
+	// an empty array of signatures or public keys faults instead of passing
+	sb = sc.NewScriptBuilder()
+	_ = sb.EmitPushInt(0)
+	_ = sb.Emit(sc.NEWARRAY)
+	_ = sb.EmitPushBytes([]byte{2})
+	_ = sb.EmitPushInt(1)
+	_ = sb.Emit(sc.PACK)
+	_ = sb.Emit(sc.CHECKMULTISIG)
+	e = NewExecutionEngine()
+	e.Message = message
+	assert.Equal(t, FAULT, e.Run(sb.ToArray()))
+
+	sb = sc.NewScriptBuilder()
+	_ = sb.EmitPushBytes(signature1)
+	_ = sb.EmitPushInt(1)
+	_ = sb.Emit(sc.PACK)
+	_ = sb.EmitPushInt(0)
+	_ = sb.Emit(sc.NEWSTRUCT)
+	_ = sb.Emit(sc.CHECKMULTISIG)
+	e = NewExecutionEngine()
+	e.Message = message
+	assert.Equal(t, FAULT, e.Run(sb.ToArray()))
+
+	// a count of zero
+	e = NewExecutionEngine()
+	e.Message = message
+	assert.Equal(t, FAULT, e.Run([]byte{byte(sc.PUSH0), byte(sc.PUSHBYTES1), 2, byte(sc.PUSH1), byte(sc.CHECKMULTISIG)}))
+}
+
+func TestExecutionEngine_UnpackStruct(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushInt(2)
+	_ = sb.Emit(sc.NEWSTRUCT)
+	_ = sb.Emit(sc.UNPACK)
+	e := NewExecutionEngine()
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, 3, e.ResultStack.Count())
+	n, _ := e.PopBigInteger()
+	assert.Equal(t, int64(2), n.Int64())
+}
+
+func TestExecutionEngine_Notify(t *testing.T) {
+	sb := sc.NewScriptBuilder()
+	_ = sb.EmitPushInt(10)
+	_ = sb.EmitPushString("transfer")
+	_ = sb.EmitPushInt(2)
+	_ = sb.Emit(sc.PACK)
+	_ = sb.EmitVmSysCall("Neo.Runtime.Notify", false)
+	_ = sb.EmitPushString("hello")
+	_ = sb.EmitVmSysCall("System.Runtime.Log", true)
+	e := NewExecutionEngine()
+	assert.Equal(t, HALT, e.Run(sb.ToArray()))
+	assert.Equal(t, 1, len(e.Notifications))
+	scriptHash, _ := helper.BytesToScriptHash(sb.ToArray())
+	assert.Equal(t, scriptHash, e.Notifications[0].ScriptHash)
+	state := e.Notifications[0].State.(*ArrayItem)
+	assert.Equal(t, []byte("transfer"), state.Value[0].(*ByteArrayItem).Value)
+	assert.Equal(t, big.NewInt(10), state.Value[1].(*IntegerItem).Value)
+	assert.Equal(t, []LogEvent{{ScriptHash: scriptHash, Message: "hello"}}, e.Logs)
+}
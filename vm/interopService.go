@@ -0,0 +1,324 @@
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+	"github.com/joeqian10/neo-gogogo/sc"
+	"github.com/joeqian10/neo-gogogo/wallet/keys"
+)
+
+// MaxStorageKeySize is the max size of a storage key
+const MaxStorageKeySize = 1024
+
+// InteropHandler handles a SYSCALL, the arguments are popped from the evaluation stack of e
+type InteropHandler func(e *ExecutionEngine) error
+
+type interopMethod struct {
+	name    string
+	price   int64
+	handler InteropHandler
+}
+
+// InteropService is the table of the SYSCALL methods, they can be called by their names
+// or by their 4 bytes ids, which are the first 4 bytes of the sha256 of the names
+type InteropService struct {
+	methods map[uint32]interopMethod
+}
+
+// NewInteropService creates an InteropService without any method
+func NewInteropService() *InteropService {
+	return &InteropService{methods: make(map[uint32]interopMethod)}
+}
+
+// InteropMethodHash returns the 4 bytes id of a SYSCALL method
+func InteropMethodHash(name string) uint32 {
+	return sc.SysCallId(name)
+}
+
+// Register adds a method, price is in units of 0.001 GAS
+func (s *InteropService) Register(name string, price int64, handler InteropHandler) {
+	s.methods[InteropMethodHash(name)] = interopMethod{name: name, price: price, handler: handler}
+}
+
+func (s *InteropService) getMethod(api []byte) (interopMethod, bool) {
+	var id uint32
+	if len(api) == 4 {
+		id = binary.LittleEndian.Uint32(api)
+	} else {
+		id = InteropMethodHash(string(api))
+	}
+	m, ok := s.methods[id]
+	return m, ok
+}
+
+// GetPrice returns the price of a method, 1 if it is not registered
+func (s *InteropService) GetPrice(api []byte) int64 {
+	m, ok := s.getMethod(api)
+	if !ok {
+		return 1
+	}
+	return m.price
+}
+
+// Invoke calls the method of api
+func (s *InteropService) Invoke(e *ExecutionEngine, api []byte) error {
+	m, ok := s.getMethod(api)
+	if !ok {
+		return fmt.Errorf("syscall %s not found", string(api))
+	}
+	if err := m.handler(e); err != nil {
+		return fmt.Errorf("syscall %s failed: %v", m.name, err)
+	}
+	return nil
+}
+
+// NewStandardInteropService creates an InteropService with the Runtime, ExecutionEngine and Storage methods
+// of neo 2.x, they are registered with the System., Neo. and AntShares. prefixes like the neo node
+func NewStandardInteropService() *InteropService {
+	s := NewInteropService()
+	s.Register("System.Runtime.Platform", 1, runtimePlatform)
+	s.Register("System.Runtime.GetTrigger", 1, runtimeGetTrigger)
+	s.Register("System.Runtime.CheckWitness", 200, runtimeCheckWitness)
+	s.Register("System.Runtime.Notify", 1, runtimeNotify)
+	s.Register("System.Runtime.Log", 1, runtimeLog)
+	s.Register("System.Runtime.GetTime", 1, runtimeGetTime)
+	s.Register("System.ExecutionEngine.GetScriptContainer", 1, executionEngineGetScriptContainer)
+	s.Register("System.ExecutionEngine.GetExecutingScriptHash", 1, executionEngineGetExecutingScriptHash)
+	s.Register("System.ExecutionEngine.GetCallingScriptHash", 1, executionEngineGetCallingScriptHash)
+	s.Register("System.ExecutionEngine.GetEntryScriptHash", 1, executionEngineGetEntryScriptHash)
+	s.Register("System.Storage.GetContext", 1, storageGetContext)
+	s.Register("System.Storage.GetReadOnlyContext", 1, storageGetReadOnlyContext)
+	s.Register("System.Storage.Get", 100, storageGet)
+	s.Register("System.Storage.Put", 0, storagePut)
+	s.Register("System.Storage.PutEx", 0, storagePutEx)
+	s.Register("System.Storage.Delete", 100, storageDelete)
+	s.Register("System.StorageContext.AsReadOnly", 1, storageContextAsReadOnly)
+
+	for _, prefix := range []string{"Neo.", "AntShares."} {
+		s.Register(prefix+"Runtime.GetTrigger", 1, runtimeGetTrigger)
+		s.Register(prefix+"Runtime.CheckWitness", 200, runtimeCheckWitness)
+		s.Register(prefix+"Runtime.Notify", 1, runtimeNotify)
+		s.Register(prefix+"Runtime.Log", 1, runtimeLog)
+		s.Register(prefix+"Storage.GetContext", 1, storageGetContext)
+		s.Register(prefix+"Storage.GetReadOnlyContext", 1, storageGetReadOnlyContext)
+		s.Register(prefix+"Storage.Get", 100, storageGet)
+		s.Register(prefix+"Storage.Put", 0, storagePut)
+		s.Register(prefix+"Storage.Delete", 100, storageDelete)
+		s.Register(prefix+"StorageContext.AsReadOnly", 1, storageContextAsReadOnly)
+	}
+	s.Register("Neo.Runtime.GetTime", 1, runtimeGetTime)
+	return s
+}
+
+func runtimePlatform(e *ExecutionEngine) error {
+	e.EvaluationStack().Push(NewByteArrayItem([]byte("NEO")))
+	return nil
+}
+
+func runtimeGetTrigger(e *ExecutionEngine) error {
+	e.EvaluationStack().Push(NewIntegerItem(big.NewInt(int64(e.Trigger))))
+	return nil
+}
+
+// runtimeCheckWitness accepts a script hash or a public key
+func runtimeCheckWitness(e *ExecutionEngine) error {
+	b, err := e.PopByteArray()
+	if err != nil {
+		return err
+	}
+	var scriptHash helper.UInt160
+	switch len(b) {
+	case 20:
+		scriptHash, _ = helper.UInt160FromBytes(b)
+	case 33:
+		p, err := keys.NewPublicKey(b)
+		if err != nil {
+			return err
+		}
+		scriptHash = p.ScriptHash()
+	default:
+		return fmt.Errorf("invalid witness of size %d", len(b))
+	}
+	result := false
+	for _, w := range e.Witnesses {
+		if w == scriptHash {
+			result = true
+			break
+		}
+	}
+	e.EvaluationStack().Push(NewBooleanItem(result))
+	return nil
+}
+
+func runtimeNotify(e *ExecutionEngine) error {
+	state, err := e.EvaluationStack().Pop()
+	if err != nil {
+		return err
+	}
+	e.Notifications = append(e.Notifications, Notification{
+		ScriptHash: e.CurrentContext().ScriptHash(),
+		State:      state,
+	})
+	return nil
+}
+
+func runtimeLog(e *ExecutionEngine) error {
+	b, err := e.PopByteArray()
+	if err != nil {
+		return err
+	}
+	e.Logs = append(e.Logs, LogEvent{
+		ScriptHash: e.CurrentContext().ScriptHash(),
+		Message:    string(b),
+	})
+	return nil
+}
+
+func runtimeGetTime(e *ExecutionEngine) error {
+	e.EvaluationStack().Push(NewIntegerItem(big.NewInt(int64(e.Timestamp))))
+	return nil
+}
+
+// executionEngineGetScriptContainer pushes the signed message, there is no transaction offline
+func executionEngineGetScriptContainer(e *ExecutionEngine) error {
+	e.EvaluationStack().Push(NewInteropItem(e.Message))
+	return nil
+}
+
+func executionEngineGetExecutingScriptHash(e *ExecutionEngine) error {
+	e.EvaluationStack().Push(NewByteArrayItem(e.CurrentContext().ScriptHash().Bytes()))
+	return nil
+}
+
+func executionEngineGetCallingScriptHash(e *ExecutionEngine) error {
+	context := e.CallingContext()
+	if context == nil {
+		e.EvaluationStack().Push(NewByteArrayItem([]byte{}))
+		return nil
+	}
+	e.EvaluationStack().Push(NewByteArrayItem(context.ScriptHash().Bytes()))
+	return nil
+}
+
+func executionEngineGetEntryScriptHash(e *ExecutionEngine) error {
+	e.EvaluationStack().Push(NewByteArrayItem(e.EntryContext().ScriptHash().Bytes()))
+	return nil
+}
+
+func storageGetContext(e *ExecutionEngine) error {
+	e.EvaluationStack().Push(NewInteropItem(&StorageContext{ScriptHash: e.CurrentContext().ScriptHash()}))
+	return nil
+}
+
+func storageGetReadOnlyContext(e *ExecutionEngine) error {
+	e.EvaluationStack().Push(NewInteropItem(&StorageContext{ScriptHash: e.CurrentContext().ScriptHash(), IsReadOnly: true}))
+	return nil
+}
+
+func storageContextAsReadOnly(e *ExecutionEngine) error {
+	context, err := popStorageContext(e)
+	if err != nil {
+		return err
+	}
+	e.EvaluationStack().Push(NewInteropItem(&StorageContext{ScriptHash: context.ScriptHash, IsReadOnly: true}))
+	return nil
+}
+
+func popStorageContext(e *ExecutionEngine) (*StorageContext, error) {
+	v, err := e.PopInterop()
+	if err != nil {
+		return nil, err
+	}
+	context, ok := v.(*StorageContext)
+	if !ok {
+		return nil, fmt.Errorf("expected storage context")
+	}
+	return context, nil
+}
+
+func storageGet(e *ExecutionEngine) error {
+	context, err := popStorageContext(e)
+	if err != nil {
+		return err
+	}
+	key, err := e.PopByteArray()
+	if err != nil {
+		return err
+	}
+	value, err := e.Storage.Get(context.ScriptHash, key)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	e.EvaluationStack().Push(NewByteArrayItem(value))
+	return nil
+}
+
+func storagePut(e *ExecutionEngine) error {
+	context, err := popStorageContext(e)
+	if err != nil {
+		return err
+	}
+	return putStorage(e, context)
+}
+
+// storagePutEx ignores the storage flags, which have no effect offline
+func storagePutEx(e *ExecutionEngine) error {
+	context, err := popStorageContext(e)
+	if err != nil {
+		return err
+	}
+	if err = putStorage(e, context); err != nil {
+		return err
+	}
+	_, err = e.EvaluationStack().Pop()
+	return err
+}
+
+func putStorage(e *ExecutionEngine, context *StorageContext) error {
+	if e.Trigger != Application {
+		return fmt.Errorf("storage can only be written by the Application trigger")
+	}
+	if context.IsReadOnly {
+		return fmt.Errorf("storage context is read only")
+	}
+	key, err := e.PopByteArray()
+	if err != nil {
+		return err
+	}
+	if len(key) > MaxStorageKeySize {
+		return fmt.Errorf("storage key size exceeds %d", MaxStorageKeySize)
+	}
+	value, err := e.PopByteArray()
+	if err != nil {
+		return err
+	}
+	// 1 GAS per KB
+	if err = e.AddGas(int64((len(key)+len(value)-1)/1024+1) * 1000); err != nil {
+		return err
+	}
+	return e.Storage.Put(context.ScriptHash, key, value)
+}
+
+func storageDelete(e *ExecutionEngine) error {
+	if e.Trigger != Application {
+		return fmt.Errorf("storage can only be written by the Application trigger")
+	}
+	context, err := popStorageContext(e)
+	if err != nil {
+		return err
+	}
+	if context.IsReadOnly {
+		return fmt.Errorf("storage context is read only")
+	}
+	key, err := e.PopByteArray()
+	if err != nil {
+		return err
+	}
+	return e.Storage.Delete(context.ScriptHash, key)
+}
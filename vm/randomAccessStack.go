@@ -0,0 +1,73 @@
+package vm
+
+import "fmt"
+
+// RandomAccessStack is a stack whose items can be accessed by their index from the top
+type RandomAccessStack struct {
+	items []StackItem // the top is the last one
+}
+
+func NewRandomAccessStack() *RandomAccessStack {
+	return &RandomAccessStack{}
+}
+
+func (s *RandomAccessStack) Count() int {
+	return len(s.items)
+}
+
+func (s *RandomAccessStack) Push(item StackItem) {
+	s.items = append(s.items, item)
+}
+
+// Peek returns the item at index, 0 is the top
+func (s *RandomAccessStack) Peek(index int) (StackItem, error) {
+	if index < 0 || index >= len(s.items) {
+		return nil, fmt.Errorf("stack index %d out of range", index)
+	}
+	return s.items[len(s.items)-1-index], nil
+}
+
+func (s *RandomAccessStack) Pop() (StackItem, error) {
+	return s.Remove(0)
+}
+
+// Remove removes the item at index, 0 is the top
+func (s *RandomAccessStack) Remove(index int) (StackItem, error) {
+	item, err := s.Peek(index)
+	if err != nil {
+		return nil, err
+	}
+	i := len(s.items) - 1 - index
+	s.items = append(s.items[:i], s.items[i+1:]...)
+	return item, nil
+}
+
+// Insert inserts item at index, 0 is the top
+func (s *RandomAccessStack) Insert(index int, item StackItem) error {
+	if index < 0 || index > len(s.items) {
+		return fmt.Errorf("stack index %d out of range", index)
+	}
+	i := len(s.items) - index
+	s.items = append(s.items, nil)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+	return nil
+}
+
+// CopyTo pushes the top count items onto stack, keeping their order
+func (s *RandomAccessStack) CopyTo(stack *RandomAccessStack, count int) error {
+	if count < 0 || count > len(s.items) {
+		return fmt.Errorf("stack count %d out of range", count)
+	}
+	stack.items = append(stack.items, s.items[len(s.items)-count:]...)
+	return nil
+}
+
+// Items returns the items from the bottom to the top
+func (s *RandomAccessStack) Items() []StackItem {
+	return append([]StackItem{}, s.items...)
+}
+
+func (s *RandomAccessStack) Clear() {
+	s.items = nil
+}
@@ -0,0 +1,299 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+)
+
+// StackItem is an item of the evaluation stack, the same as neo-vm 2.x
+type StackItem interface {
+	GetBigInteger() (*big.Int, error)
+	GetBoolean() bool
+	GetByteArray() ([]byte, error)
+	Equals(other StackItem) bool
+}
+
+// ByteArrayItem is a byte array pushed by PUSHBYTES or PUSHDATA
+type ByteArrayItem struct {
+	Value []byte
+}
+
+// IntegerItem is a BigInteger
+type IntegerItem struct {
+	Value *big.Int
+}
+
+// BooleanItem is a boolean
+type BooleanItem struct {
+	Value bool
+}
+
+// ArrayItem is a reference type, a copy shares the items
+type ArrayItem struct {
+	Value []StackItem
+}
+
+// StructItem is a value type, it is cloned when it is put into an array
+type StructItem struct {
+	Value []StackItem
+}
+
+// MapItem is a reference type of key-value pairs in the order they are added, the keys are primitive items
+type MapItem struct {
+	Keys   []StackItem
+	Values []StackItem
+}
+
+// InteropItem wraps an object of the interop services, such as a StorageContext
+type InteropItem struct {
+	Value interface{}
+}
+
+func NewByteArrayItem(b []byte) *ByteArrayItem {
+	return &ByteArrayItem{Value: b}
+}
+
+func NewIntegerItem(i *big.Int) *IntegerItem {
+	return &IntegerItem{Value: i}
+}
+
+func NewBooleanItem(b bool) *BooleanItem {
+	return &BooleanItem{Value: b}
+}
+
+func NewArrayItem(items []StackItem) *ArrayItem {
+	return &ArrayItem{Value: items}
+}
+
+func NewStructItem(items []StackItem) *StructItem {
+	return &StructItem{Value: items}
+}
+
+func NewMapItem() *MapItem {
+	return &MapItem{}
+}
+
+func NewInteropItem(v interface{}) *InteropItem {
+	return &InteropItem{Value: v}
+}
+
+func (s *ByteArrayItem) GetBigInteger() (*big.Int, error) {
+	if len(s.Value) > MaxSizeForBigInteger {
+		return nil, fmt.Errorf("byte array of size %d is too long for an integer", len(s.Value))
+	}
+	return helper.BigIntFromNeoBytes(s.Value), nil
+}
+
+func (s *ByteArrayItem) GetBoolean() bool {
+	for _, b := range s.Value {
+		if b != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ByteArrayItem) GetByteArray() ([]byte, error) {
+	return s.Value, nil
+}
+
+func (s *ByteArrayItem) Equals(other StackItem) bool {
+	return equalBytes(s, other)
+}
+
+func (s *IntegerItem) GetBigInteger() (*big.Int, error) {
+	return s.Value, nil
+}
+
+func (s *IntegerItem) GetBoolean() bool {
+	return s.Value.Sign() != 0
+}
+
+func (s *IntegerItem) GetByteArray() ([]byte, error) {
+	return helper.BigIntToNeoBytes(s.Value), nil
+}
+
+func (s *IntegerItem) Equals(other StackItem) bool {
+	if i, ok := other.(*IntegerItem); ok {
+		return s.Value.Cmp(i.Value) == 0
+	}
+	return equalBytes(s, other)
+}
+
+func (s *BooleanItem) GetBigInteger() (*big.Int, error) {
+	if s.Value {
+		return big.NewInt(1), nil
+	}
+	return big.NewInt(0), nil
+}
+
+func (s *BooleanItem) GetBoolean() bool {
+	return s.Value
+}
+
+func (s *BooleanItem) GetByteArray() ([]byte, error) {
+	if s.Value {
+		return []byte{1}, nil
+	}
+	return []byte{}, nil
+}
+
+func (s *BooleanItem) Equals(other StackItem) bool {
+	if b, ok := other.(*BooleanItem); ok {
+		return s.Value == b.Value
+	}
+	return equalBytes(s, other)
+}
+
+func (s *ArrayItem) GetBigInteger() (*big.Int, error) {
+	return nil, fmt.Errorf("array can not be converted to an integer")
+}
+
+func (s *ArrayItem) GetBoolean() bool {
+	return true
+}
+
+func (s *ArrayItem) GetByteArray() ([]byte, error) {
+	return nil, fmt.Errorf("array can not be converted to a byte array")
+}
+
+func (s *ArrayItem) Equals(other StackItem) bool {
+	return StackItem(s) == other
+}
+
+func (s *StructItem) GetBigInteger() (*big.Int, error) {
+	return nil, fmt.Errorf("struct can not be converted to an integer")
+}
+
+func (s *StructItem) GetBoolean() bool {
+	return true
+}
+
+func (s *StructItem) GetByteArray() ([]byte, error) {
+	return nil, fmt.Errorf("struct can not be converted to a byte array")
+}
+
+// Equals compares the items of two structs
+func (s *StructItem) Equals(other StackItem) bool {
+	o, ok := other.(*StructItem)
+	if !ok {
+		return false
+	}
+	if s == o {
+		return true
+	}
+	if len(s.Value) != len(o.Value) {
+		return false
+	}
+	for i := range s.Value {
+		if !s.Value[i].Equals(o.Value[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone copies the struct and the structs in it, the arrays are shared
+func (s *StructItem) Clone() *StructItem {
+	items := make([]StackItem, len(s.Value))
+	for i, item := range s.Value {
+		if st, ok := item.(*StructItem); ok {
+			items[i] = st.Clone()
+		} else {
+			items[i] = item
+		}
+	}
+	return NewStructItem(items)
+}
+
+func (s *MapItem) GetBigInteger() (*big.Int, error) {
+	return nil, fmt.Errorf("map can not be converted to an integer")
+}
+
+func (s *MapItem) GetBoolean() bool {
+	return true
+}
+
+func (s *MapItem) GetByteArray() ([]byte, error) {
+	return nil, fmt.Errorf("map can not be converted to a byte array")
+}
+
+func (s *MapItem) Equals(other StackItem) bool {
+	return StackItem(s) == other
+}
+
+// Count returns the number of the pairs
+func (s *MapItem) Count() int {
+	return len(s.Keys)
+}
+
+func (s *MapItem) indexOf(key StackItem) int {
+	for i, k := range s.Keys {
+		if k.Equals(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the value of key, false if key is not found
+func (s *MapItem) Get(key StackItem) (StackItem, bool) {
+	i := s.indexOf(key)
+	if i < 0 {
+		return nil, false
+	}
+	return s.Values[i], true
+}
+
+// Set adds a pair or replaces the value of key
+func (s *MapItem) Set(key StackItem, value StackItem) {
+	if i := s.indexOf(key); i >= 0 {
+		s.Values[i] = value
+		return
+	}
+	s.Keys = append(s.Keys, key)
+	s.Values = append(s.Values, value)
+}
+
+// Remove removes the pair of key if it exists
+func (s *MapItem) Remove(key StackItem) {
+	if i := s.indexOf(key); i >= 0 {
+		s.Keys = append(s.Keys[:i], s.Keys[i+1:]...)
+		s.Values = append(s.Values[:i], s.Values[i+1:]...)
+	}
+}
+
+func (s *InteropItem) GetBigInteger() (*big.Int, error) {
+	return nil, fmt.Errorf("interop interface can not be converted to an integer")
+}
+
+func (s *InteropItem) GetBoolean() bool {
+	return s.Value != nil
+}
+
+func (s *InteropItem) GetByteArray() ([]byte, error) {
+	return nil, fmt.Errorf("interop interface can not be converted to a byte array")
+}
+
+func (s *InteropItem) Equals(other StackItem) bool {
+	o, ok := other.(*InteropItem)
+	return ok && s.Value == o.Value
+}
+
+// equalBytes compares the byte arrays of two primitive items
+func equalBytes(s StackItem, other StackItem) bool {
+	if s == other {
+		return true
+	}
+	switch other.(type) {
+	case *ByteArrayItem, *IntegerItem, *BooleanItem:
+	default:
+		return false
+	}
+	a, _ := s.GetByteArray()
+	b, _ := other.GetByteArray()
+	return bytes.Equal(a, b)
+}
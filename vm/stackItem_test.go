@@ -0,0 +1,44 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackItem_Convert(t *testing.T) {
+	i, err := NewByteArrayItem([]byte{0xff}).GetBigInteger()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), i.Int64())
+
+	_, err = NewByteArrayItem(make([]byte, MaxSizeForBigInteger+1)).GetBigInteger()
+	assert.NotNil(t, err)
+
+	b, _ := NewIntegerItem(big.NewInt(0)).GetByteArray()
+	assert.Equal(t, 0, len(b))
+	b, _ = NewIntegerItem(big.NewInt(128)).GetByteArray()
+	assert.Equal(t, []byte{0x80, 0x00}, b)
+
+	assert.False(t, NewByteArrayItem([]byte{0, 0}).GetBoolean())
+	assert.True(t, NewArrayItem(nil).GetBoolean())
+
+	_, err = NewArrayItem(nil).GetByteArray()
+	assert.NotNil(t, err)
+}
+
+func TestStackItem_Equals(t *testing.T) {
+	assert.True(t, NewIntegerItem(big.NewInt(1)).Equals(NewByteArrayItem([]byte{1})))
+	assert.True(t, NewBooleanItem(true).Equals(NewIntegerItem(big.NewInt(1))))
+	assert.False(t, NewByteArrayItem([]byte{1}).Equals(NewByteArrayItem([]byte{1, 0})))
+
+	a := NewArrayItem([]StackItem{NewBooleanItem(true)})
+	assert.True(t, a.Equals(a))
+	assert.False(t, a.Equals(NewArrayItem([]StackItem{NewBooleanItem(true)})))
+
+	s := NewStructItem([]StackItem{NewIntegerItem(big.NewInt(1))})
+	c := s.Clone()
+	assert.True(t, s.Equals(c))
+	c.Value[0] = NewIntegerItem(big.NewInt(2))
+	assert.False(t, s.Equals(c))
+}
@@ -0,0 +1,91 @@
+package vm
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/joeqian10/neo-gogogo/helper"
+)
+
+// IStorage is the contract storage used by the Storage interop services
+type IStorage interface {
+	// Get returns nil if the key is absent
+	Get(scriptHash helper.UInt160, key []byte) ([]byte, error)
+	Put(scriptHash helper.UInt160, key []byte, value []byte) error
+	Delete(scriptHash helper.UInt160, key []byte) error
+}
+
+// IScriptTable provides the scripts of the contracts called by APPCALL and TAILCALL
+type IScriptTable interface {
+	GetScript(scriptHash helper.UInt160) ([]byte, error)
+}
+
+// StorageContext is pushed by Storage.GetContext, it limits the storage to the calling contract
+type StorageContext struct {
+	ScriptHash helper.UInt160
+	IsReadOnly bool
+}
+
+// MemoryStorage keeps the storage of the contracts in memory
+type MemoryStorage struct {
+	mu    sync.RWMutex
+	items map[helper.UInt160]map[string][]byte
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		items: make(map[helper.UInt160]map[string][]byte),
+	}
+}
+
+func (s *MemoryStorage) Get(scriptHash helper.UInt160, key []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.items[scriptHash][string(key)], nil
+}
+
+func (s *MemoryStorage) Put(scriptHash helper.UInt160, key []byte, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.items[scriptHash]
+	if !ok {
+		m = make(map[string][]byte)
+		s.items[scriptHash] = m
+	}
+	m[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (s *MemoryStorage) Delete(scriptHash helper.UInt160, key []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items[scriptHash], string(key))
+	return nil
+}
+
+// ScriptTable is an IScriptTable in memory, the key is the script hash of the script
+type ScriptTable map[helper.UInt160][]byte
+
+// NewScriptTable creates a ScriptTable of the contract scripts
+func NewScriptTable(scripts ...[]byte) ScriptTable {
+	t := ScriptTable{}
+	for _, script := range scripts {
+		t.Add(script)
+	}
+	return t
+}
+
+// Add adds a script and returns its script hash
+func (t ScriptTable) Add(script []byte) helper.UInt160 {
+	scriptHash, _ := helper.BytesToScriptHash(script)
+	t[scriptHash] = script
+	return scriptHash
+}
+
+func (t ScriptTable) GetScript(scriptHash helper.UInt160) ([]byte, error) {
+	script, ok := t[scriptHash]
+	if !ok {
+		return nil, fmt.Errorf("contract %s not found", scriptHash.String())
+	}
+	return script, nil
+}
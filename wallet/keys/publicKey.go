@@ -198,7 +198,7 @@ func CreateSignatureRedeemScript(p *PublicKey) []byte {
 
 // create multi-signature check script
 func CreateMultiSigRedeemScript(m int, ps ...*PublicKey) ([]byte, error) {
-	if !(m >= 1 && m < len(ps) && len(ps) <= 1024) {
+	if !(m >= 1 && m <= len(ps) && len(ps) <= 1024) {
 		return nil, fmt.Errorf("argument exception: %v,%v", m, len(ps))
 	}
 
@@ -225,3 +225,55 @@ func CreateMultiSigRedeemScript(m int, ps ...*PublicKey) ([]byte, error) {
 	}
 	return builder.ToArray(), nil
 }
+
+// ParseMultiSigRedeemScript returns m and the public keys of a script made by CreateMultiSigRedeemScript
+func ParseMultiSigRedeemScript(script []byte) (int, []*PublicKey, error) {
+	m, offset, err := readPushInt(script, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	var ps []*PublicKey
+	for offset+34 <= len(script) && script[offset] == 33 {
+		p, err := NewPublicKey(script[offset+1 : offset+34])
+		if err != nil {
+			return 0, nil, err
+		}
+		ps = append(ps, p)
+		offset += 34
+	}
+	n, offset, err := readPushInt(script, offset)
+	if err != nil {
+		return 0, nil, err
+	}
+	if offset != len(script)-1 || script[offset] != byte(sc.CHECKMULTISIG) {
+		return 0, nil, fmt.Errorf("not a multi-signature script")
+	}
+	if n != len(ps) || m < 1 || m > n {
+		return 0, nil, fmt.Errorf("argument exception: %v,%v", m, n)
+	}
+	return m, ps, nil
+}
+
+// readPushInt reads an integer pushed by sc.ScriptBuilder.EmitPushInt, up to 1024
+func readPushInt(script []byte, offset int) (int, int, error) {
+	if offset >= len(script) {
+		return 0, offset, fmt.Errorf("unexpected end of script")
+	}
+	op := script[offset]
+	switch {
+	case op >= byte(sc.PUSH1) && op <= byte(sc.PUSH16):
+		return int(op-byte(sc.PUSH1)) + 1, offset + 1, nil
+	case op == 1 || op == 2:
+		if offset+1+int(op) > len(script) {
+			return 0, offset, fmt.Errorf("unexpected end of script")
+		}
+		data := script[offset+1 : offset+1+int(op)]
+		n := int(data[0])
+		if op == 2 {
+			n |= int(data[1]) << 8
+		}
+		return n, offset + 1 + int(op), nil
+	default:
+		return 0, offset, fmt.Errorf("invalid push opcode %d", op)
+	}
+}
@@ -35,3 +35,29 @@ func TestCreateMultiSigRedeemScript(t *testing.T) {
 
 	assert.Equal(t, "5321027d73c8b02e446340caceee7a517cddff72440e60c28cbb84884f307760ecad5b21038a2151948a908cdf2d680eead6512217769e34b9db196574572cb98e273516a12103b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe554444266192103d08d6f766b54e35745bc99d643c939ec6f3d37004f2a59006be0e53610f0be2554ae", hex.EncodeToString(multiSignature))
 }
+
+func TestParseMultiSigRedeemScript(t *testing.T) {
+	script, _ := hex.DecodeString("5321027d73c8b02e446340caceee7a517cddff72440e60c28cbb84884f307760ecad5b21038a2151948a908cdf2d680eead6512217769e34b9db196574572cb98e273516a12103b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe554444266192103d08d6f766b54e35745bc99d643c939ec6f3d37004f2a59006be0e53610f0be2554ae")
+	m, ps, err := ParseMultiSigRedeemScript(script)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, m)
+	assert.Equal(t, 4, len(ps))
+	assert.Equal(t, "027d73c8b02e446340caceee7a517cddff72440e60c28cbb84884f307760ecad5b", ps[0].String())
+
+	// round trip
+	rebuilt, _ := CreateMultiSigRedeemScript(m, ps...)
+	assert.Equal(t, script, rebuilt)
+
+	// a single validator
+	single, err := CreateMultiSigRedeemScript(1, ps[0])
+	assert.Nil(t, err)
+	m, ps, err = ParseMultiSigRedeemScript(single)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, m)
+	assert.Equal(t, 1, len(ps))
+
+	_, _, err = ParseMultiSigRedeemScript(CreateSignatureRedeemScript(ps[0]))
+	assert.NotNil(t, err)
+	_, _, err = ParseMultiSigRedeemScript(script[:len(script)-1])
+	assert.NotNil(t, err)
+}
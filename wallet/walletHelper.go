@@ -1,12 +1,10 @@
 package wallet
 
 import (
-	"fmt"
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/sc"
 	"github.com/joeqian10/neo-gogogo/tx"
 	"math/big"
-	"strconv"
 )
 
 type WalletHelper struct {
@@ -23,25 +21,34 @@ func NewWalletHelper(txBuilder *tx.TransactionBuilder, account *Account) *Wallet
 
 // GetBalance is used to transfer neo or gas or other utxo asset, single signature
 func (w *WalletHelper) GetBalance(address string) (neoBalance int, gasBalance float64, err error) {
+	neo, gas, err := w.GetBalanceFixed8(address)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(helper.Fixed8ToInt64(neo)), helper.Fixed8ToFloat64(gas), nil
+}
+
+// GetBalanceFixed8 is the same as GetBalance, but returns the exact balances
+func (w *WalletHelper) GetBalanceFixed8(address string) (neoBalance helper.Fixed8, gasBalance helper.Fixed8, err error) {
 	response := w.TxBuilder.Client.GetAccountState(address)
 	if response.HasError() {
-		return 0, 0, fmt.Errorf(response.ErrorResponse.Error.Message)
+		return helper.Zero, helper.Zero, response.GetError()
 	}
 	balances := response.Result.Balances
 	for _, balance := range balances {
 		assetId, err := helper.UInt256FromString(balance.Asset)
 		if err != nil {
-			return 0, 0, err
+			return helper.Zero, helper.Zero, err
 		}
 		if assetId == tx.NeoToken {
-			neoBalance, err = strconv.Atoi(balance.Value)
+			neoBalance, err = helper.Fixed8FromNumber(balance.Value)
 			if err != nil {
-				return 0, 0, err
+				return helper.Zero, helper.Zero, err
 			}
 		} else if assetId == tx.GasToken {
-			gasBalance, err = strconv.ParseFloat(balance.Value, 64)
+			gasBalance, err = helper.Fixed8FromNumber(balance.Value)
 			if err != nil {
-				return 0, 0, err
+				return helper.Zero, helper.Zero, err
 			}
 		}
 	}
@@ -50,6 +57,11 @@ func (w *WalletHelper) GetBalance(address string) (neoBalance int, gasBalance fl
 
 // Transfer is used to transfer neo or gas or other utxo asset, single signature, return txid
 func (w *WalletHelper) Transfer(assetId helper.UInt256, from string, to string, amount float64) (string, error) {
+	return w.TransferFixed8(assetId, from, to, helper.Fixed8FromFloat64(amount))
+}
+
+// TransferFixed8 is the same as Transfer, but takes an exact amount
+func (w *WalletHelper) TransferFixed8(assetId helper.UInt256, from string, to string, amount helper.Fixed8) (string, error) {
 	f, err := helper.AddressToScriptHash(from)
 	if err != nil {
 		return "", err
@@ -58,8 +70,7 @@ func (w *WalletHelper) Transfer(assetId helper.UInt256, from string, to string,
 	if err != nil {
 		return "", err
 	}
-	a := helper.Fixed8FromFloat64(amount)
-	ctx, err := w.TxBuilder.MakeContractTransaction(f, t, assetId, a, nil, helper.UInt160{}, helper.Zero)
+	ctx, err := w.TxBuilder.MakeContractTransaction(f, t, assetId, amount, nil, helper.UInt160{}, helper.Zero)
 	if err != nil {
 		return "", err
 	}
@@ -71,7 +82,7 @@ func (w *WalletHelper) Transfer(assetId helper.UInt256, from string, to string,
 	// use RPC to send the tx
 	response := w.TxBuilder.Client.SendRawTransaction(ctx.RawTransactionString())
 	if response.HasError() {
-		return "", fmt.Errorf(response.ErrorResponse.Error.Message)
+		return "", response.GetError()
 	}
 	return ctx.HashString(), nil
 }
@@ -94,12 +105,24 @@ func (w *WalletHelper) ClaimGas(from string) (string, error) {
 	// use RPC to send the tx
 	response := w.TxBuilder.Client.SendRawTransaction(ctx.RawTransactionString())
 	if response.HasError() {
-		return "", fmt.Errorf(response.ErrorResponse.Error.Message)
+		return "", response.GetError()
 	}
 	return ctx.HashString(), nil
 }
 
+// TransferNep5 is used to transfer a NEP-5 token with 8 decimals, use TransferNep5BigInt for the other tokens
 func (w *WalletHelper) TransferNep5(assetId helper.UInt160, from string, to string, amount float64) (string, error) {
+	return w.TransferNep5Fixed8(assetId, from, to, helper.Fixed8FromFloat64(amount))
+}
+
+// TransferNep5Fixed8 is the same as TransferNep5, but takes an exact amount of a token with 8 decimals
+func (w *WalletHelper) TransferNep5Fixed8(assetId helper.UInt160, from string, to string, a helper.Fixed8) (string, error) {
+	return w.TransferNep5BigInt(assetId, from, to, big.NewInt(a.Value))
+}
+
+// TransferNep5BigInt transfers amount in the smallest unit of the token, which is correct for any decimals,
+// see nep5.TokenAmount for the conversion
+func (w *WalletHelper) TransferNep5BigInt(assetId helper.UInt160, from string, to string, amount *big.Int) (string, error) {
 	f, err := helper.AddressToScriptHash(from)
 	if err != nil {
 		return "", err
@@ -108,7 +131,6 @@ func (w *WalletHelper) TransferNep5(assetId helper.UInt160, from string, to stri
 	if err != nil {
 		return "", err
 	}
-	a := helper.Fixed8FromFloat64(amount)
 	sb := sc.NewScriptBuilder()
 	cp1 := sc.ContractParameter{
 		Type:  sc.Hash160,
@@ -120,7 +142,7 @@ func (w *WalletHelper) TransferNep5(assetId helper.UInt160, from string, to stri
 	}
 	cp3 := sc.ContractParameter{
 		Type:  sc.Integer,
-		Value: *big.NewInt(a.Value),
+		Value: *amount,
 	}
 	sb.MakeInvocationScript(assetId.Bytes(), "transfer", []sc.ContractParameter{cp1, cp2, cp3})
 	script := sb.ToArray()
@@ -136,7 +158,7 @@ func (w *WalletHelper) TransferNep5(assetId helper.UInt160, from string, to stri
 	// use RPC to send the tx
 	response := w.TxBuilder.Client.SendRawTransaction(itx.RawTransactionString())
 	if response.HasError() {
-		return "", fmt.Errorf(response.ErrorResponse.Error.Message)
+		return "", response.GetError()
 	}
 	return itx.HashString(), nil
 }
@@ -215,7 +237,7 @@ func (w *WalletHelper) DeployContract(script []byte,
 	}
 	response := w.TxBuilder.Client.SendRawTransaction(itx.RawTransactionString())
 	if response.HasError() {
-		return nil, fmt.Errorf(response.ErrorResponse.Error.Message)
+		return nil, response.GetError()
 	}
 
 	return &scriptHash, nil
@@ -240,7 +262,7 @@ func (w *WalletHelper) InvokeContract(scriptHash helper.UInt160, method string,
 	}
 	response := w.TxBuilder.Client.SendRawTransaction(itx.RawTransactionString())
 	if response.HasError() {
-		return nil, fmt.Errorf(response.ErrorResponse.Error.Message)
+		return nil, response.GetError()
 	}
 	return &itx.Hash, nil
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/joeqian10/neo-gogogo/helper"
 	"github.com/joeqian10/neo-gogogo/rpc"
 	"github.com/joeqian10/neo-gogogo/rpc/models"
+	"github.com/joeqian10/neo-gogogo/sc"
 	"github.com/joeqian10/neo-gogogo/tx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -64,6 +65,11 @@ func TestWalletHelper_GetBalance(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 100, neoBalance)
 	assert.Equal(t, 90.12345678, gasBalance)
+
+	neo, gas, err := walletHelper.GetBalanceFixed8("APPmjituYcgfNxjuQDy9vP73R2PmhFsYJR")
+	assert.Nil(t, err)
+	assert.Equal(t, helper.Fixed8FromInt64(100), neo)
+	assert.Equal(t, int64(9012345678), gas.Value)
 }
 
 func TestWalletHelper_ClaimGas(t *testing.T) {
@@ -93,13 +99,13 @@ func TestWalletHelper_ClaimGas(t *testing.T) {
 					Value:       800000,
 					StartHeight: 476496,
 					EndHeight:   488154,
-					Generated:   746.112,
-					SysFee:      3.92,
-					Unclaimed:   750.032,
+					Generated:   helper.NewFixed8(74611200000), // 746.112
+					SysFee:      helper.NewFixed8(392000000),   // 3.92
+					Unclaimed:   helper.NewFixed8(75003200000), // 750.032
 				},
 			},
 			Address:   "AGofsxAUDwt52KjaB664GYsqVAkULYvKNt",
-			Unclaimed: 750.032,
+			Unclaimed: helper.NewFixed8(75003200000), // 750.032
 		},
 	})
 	clientMock.On("SendRawTransaction", mock.Anything).Return(rpc.SendRawTransactionResponse{
@@ -149,26 +155,26 @@ func TestWalletHelper_Transfer(t *testing.T) {
 						{
 							Txid:  "4ee4af75d5aa60598fbae40ce86fb9a23ffec5a75dfa8b59d259d15f9e304319",
 							N:     0,
-							Value: 27844.821,
+							Value: helper.NewFixed8(2784482100000), // 27844.821
 						},
 					},
 					AssetHash:   "602c79718b16e442de58778e148d0b1084e3b2dffd5de6b7b16cee7969282de7",
 					Asset:       "GAS",
 					AssetSymbol: "GAS",
-					Amount:      27844.821,
+					Amount:      helper.NewFixed8(2784482100000), // 27844.821
 				},
 				{
 					Unspents: []models.Unspent{
 						{
 							Txid:  "c3182952855314b3f4b1ecf01a03b891d4627d19426ce841275f6d4c186e729a",
 							N:     0,
-							Value: 800000,
+							Value: helper.Fixed8FromInt64(800000),
 						},
 					},
 					AssetHash:   "c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b",
 					Asset:       "NEO",
 					AssetSymbol: "NEO",
-					Amount:      800000,
+					Amount:      helper.Fixed8FromInt64(800000),
 				},
 			},
 			Address: "AGofsxAUDwt52KjaB664GYsqVAkULYvKNt",
@@ -221,26 +227,26 @@ func TestWalletHelper_TransferNep5(t *testing.T) {
 						{
 							Txid:  "4ee4af75d5aa60598fbae40ce86fb9a23ffec5a75dfa8b59d259d15f9e304319",
 							N:     0,
-							Value: 27844.821,
+							Value: helper.NewFixed8(2784482100000), // 27844.821
 						},
 					},
 					AssetHash:   "602c79718b16e442de58778e148d0b1084e3b2dffd5de6b7b16cee7969282de7",
 					Asset:       "GAS",
 					AssetSymbol: "GAS",
-					Amount:      27844.821,
+					Amount:      helper.NewFixed8(2784482100000), // 27844.821
 				},
 				{
 					Unspents: []models.Unspent{
 						{
 							Txid:  "c3182952855314b3f4b1ecf01a03b891d4627d19426ce841275f6d4c186e729a",
 							N:     0,
-							Value: 800000,
+							Value: helper.Fixed8FromInt64(800000),
 						},
 					},
 					AssetHash:   "c56f33fc6ecfcd0c225c4ab356fee59390af8560be0e930faebe74a6daff7c9b",
 					Asset:       "NEO",
 					AssetSymbol: "NEO",
-					Amount:      800000,
+					Amount:      helper.Fixed8FromInt64(800000),
 				},
 			},
 			Address: "AGofsxAUDwt52KjaB664GYsqVAkULYvKNt",
@@ -261,10 +267,10 @@ func TestWalletHelper_TransferNep5(t *testing.T) {
 			Script:      "00c1046e616d656763d26113bac4208254d98a3eebaee66230ead7b9",
 			State:       "HALT",
 			GasConsumed: "0.126",
-			Stack: []models.InvokeStack{
+			Stack: []sc.ContractParameter{
 				{
-					Type:  "Boolean",
-					Value: "True",
+					Type:  sc.Boolean,
+					Value: true,
 				},
 			},
 		},